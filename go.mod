@@ -5,6 +5,8 @@ go 1.25.3
 require (
 	github.com/modelcontextprotocol/go-sdk v1.2.0
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/mod v0.30.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -20,5 +22,4 @@ require (
 	golang.org/x/oauth2 v0.34.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
 	google.golang.org/adk v0.3.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,128 @@
+package bundler
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+)
+
+// Sources of locked gems, named after their Gemfile.lock sections.
+const (
+	SourceGem  = "GEM"
+	SourceGit  = "GIT"
+	SourcePath = "PATH"
+)
+
+// LockedGem is a spec of Gemfile.lock.
+type LockedGem struct {
+	Name    string
+	Version string
+	// Platforms are the platforms of the precompiled variants locked, such
+	// as x86_64-linux; the plain ruby variant is not listed.
+	Platforms []string
+	// Source is the section the gem is locked in, and Remote its gem
+	// server, repository or path; Revision is the commit of git gems.
+	Source   string
+	Remote   string
+	Revision string
+}
+
+type lockfile struct {
+	gems []LockedGem
+	// dependencies are the gems the Gemfile declares, with their
+	// requirements.
+	dependencies map[string]string
+	bundledWith  string
+}
+
+// specRE matches a gem and its version in parentheses, as in
+// "nokogiri (1.16.0-x86_64-linux)" or "rails (~> 7.1, >= 7.1.3)!".
+var specRE = regexp.MustCompile(`^([^\s(!]+)(?: \(([^)]*)\))?!?$`)
+
+// parseLockfile reads the sections of Gemfile.lock: GEM, GIT and PATH
+// hold the resolved specs, indented by four spaces under "specs:", and
+// DEPENDENCIES the gems of the Gemfile.
+func parseLockfile(data string) *lockfile {
+	lock := &lockfile{dependencies: make(map[string]string)}
+	byName := make(map[string]int)
+	var section, remote, revision string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		if line == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		text := strings.TrimSpace(line)
+		if indent == 0 {
+			section, remote, revision = text, "", ""
+			continue
+		}
+
+		switch section {
+		case SourceGem, SourceGit, SourcePath:
+			if indent == 2 {
+				if value, ok := strings.CutPrefix(text, "remote: "); ok && remote == "" {
+					remote = value
+				} else if value, ok := strings.CutPrefix(text, "revision: "); ok {
+					revision = value
+				}
+				continue
+			}
+			// Deeper lines are the dependencies of a spec.
+			if indent != 4 {
+				continue
+			}
+			m := specRE.FindStringSubmatch(text)
+			if m == nil {
+				continue
+			}
+			version, platform, _ := strings.Cut(m[2], "-")
+			if i, ok := byName[m[1]]; ok && lock.gems[i].Version == version {
+				if platform != "" {
+					lock.gems[i].Platforms = append(lock.gems[i].Platforms, platform)
+				}
+				continue
+			}
+			gem := LockedGem{Name: m[1], Version: version, Source: section, Remote: remote, Revision: revision}
+			if platform != "" {
+				gem.Platforms = []string{platform}
+			}
+			byName[m[1]] = len(lock.gems)
+			lock.gems = append(lock.gems, gem)
+		case "DEPENDENCIES":
+			if m := specRE.FindStringSubmatch(text); m != nil {
+				lock.dependencies[m[1]] = m[2]
+			}
+		case "BUNDLED WITH":
+			lock.bundledWith = text
+		}
+	}
+	return lock
+}
+
+// gemRE matches the gem declarations of a Gemfile and their version
+// requirements, as in gem "rails", "~> 7.1".
+var gemRE = regexp.MustCompile(`^\s*gem\s*\(?\s*["']([^"']+)["']((?:\s*,\s*["'][^"']*["'])*)`)
+
+var quotedRE = regexp.MustCompile(`["']([^"']*)["']`)
+
+// parseGemfile returns the gems a Gemfile declares with their
+// requirements. The Gemfile is Ruby code, so only literal declarations are
+// found.
+func parseGemfile(data string) map[string]string {
+	gems := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		m := gemRE.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		var requirements []string
+		for _, q := range quotedRE.FindAllStringSubmatch(m[2], -1) {
+			requirements = append(requirements, q[1])
+		}
+		gems[m[1]] = strings.Join(requirements, ", ")
+	}
+	return gems
+}
@@ -0,0 +1,271 @@
+// Package bundler resolves the dependencies of Ruby projects through the
+// Gemfile and Gemfile.lock, and locates the gem sources bundler or
+// RubyGems installed.
+package bundler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+)
+
+// ErrNoGemfile is returned when no Gemfile can be found for a directory.
+var ErrNoGemfile = errors.New("Gemfile not found")
+
+// Dependency is a gem locked for the project, or declared in its Gemfile.
+type Dependency struct {
+	Name string `json:"name"`
+	// Requirement is the version requirement the Gemfile declares, and
+	// Direct is set for the gems it declares.
+	Requirement string `json:"requirement,omitempty"`
+	Direct      bool   `json:"direct,omitempty"`
+	// Locked is the version Gemfile.lock resolves the gem to.
+	Locked   string `json:"locked,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Remote   string `json:"remote,omitempty"`
+	Revision string `json:"revision,omitempty"`
+	// Dir is the directory of the installed gem, when found.
+	Dir string `json:"dir,omitempty"`
+}
+
+// Project is a Ruby project managed by bundler.
+type Project struct {
+	Dir string
+	// Lockfile is the path of Gemfile.lock, empty when there is none.
+	Lockfile    string
+	BundledWith string
+	// GemDirs are the existing gem installation directories searched for
+	// gems, in order: the bundler install path, GEM_HOME, GEM_PATH and
+	// the usual per-user and system locations. Each holds gems/ and, for
+	// git gems installed by bundler, bundler/gems/.
+	GemDirs []string
+
+	lock     *lockfile
+	gemfile  map[string]string
+	warnings []string
+}
+
+// LoadProject finds the Gemfile governing dir, walking up the directory
+// tree, and the Gemfile.lock next to it.
+func LoadProject(dir string) (*Project, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	rootDir, ok := fsutil.FindUp(absDir, "Gemfile")
+	if !ok {
+		return nil, fmt.Errorf("%w in %s or any parent directory", ErrNoGemfile, absDir)
+	}
+	data, err := os.ReadFile(filepath.Join(rootDir, "Gemfile"))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Project{Dir: rootDir, gemfile: parseGemfile(string(data))}
+	lockfile := filepath.Join(rootDir, "Gemfile.lock")
+	if data, err := os.ReadFile(lockfile); err == nil {
+		p.Lockfile = lockfile
+		p.lock = parseLockfile(string(data))
+		p.BundledWith = p.lock.bundledWith
+	} else {
+		p.warnings = append(p.warnings, "no Gemfile.lock found; run bundle lock to pin versions")
+	}
+	p.GemDirs = p.gemDirs()
+	if len(p.GemDirs) == 0 {
+		p.warnings = append(p.warnings, "no installed gems found; run bundle install, or set GEM_HOME")
+	}
+	return p, nil
+}
+
+// Warnings reports problems found while loading the project.
+func (p *Project) Warnings() []string {
+	return p.warnings
+}
+
+// Dependencies lists the gems of Gemfile.lock, those the Gemfile declares
+// first, sorted by name. Without a lockfile it lists the gems the Gemfile
+// declares.
+func (p *Project) Dependencies() []Dependency {
+	deps := make([]Dependency, 0)
+	if p.lock == nil {
+		for name, requirement := range p.gemfile {
+			deps = append(deps, Dependency{Name: name, Requirement: requirement, Direct: true})
+		}
+	} else {
+		for _, gem := range p.lock.gems {
+			deps = append(deps, p.dependency(&gem))
+		}
+		// Gems declared but not locked, as after editing the Gemfile.
+		for name, requirement := range p.lock.dependencies {
+			if p.lockedGem(name) == nil {
+				deps = append(deps, Dependency{Name: name, Requirement: requirement, Direct: true})
+			}
+		}
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Direct != deps[j].Direct {
+			return deps[i].Direct
+		}
+		return deps[i].Name < deps[j].Name
+	})
+	return deps
+}
+
+// Dependency returns the locked gem with the given name, or the declared
+// one when there is no lockfile.
+func (p *Project) Dependency(name string) (*Dependency, error) {
+	for _, dep := range p.Dependencies() {
+		if dep.Name == name {
+			return &dep, nil
+		}
+	}
+	return nil, fmt.Errorf("gem %s is not a dependency of %s", name, p.Dir)
+}
+
+func (p *Project) lockedGem(name string) *LockedGem {
+	for i, gem := range p.lock.gems {
+		if gem.Name == name {
+			return &p.lock.gems[i]
+		}
+	}
+	return nil
+}
+
+func (p *Project) dependency(gem *LockedGem) Dependency {
+	dep := Dependency{Name: gem.Name, Locked: gem.Version, Source: gem.Source, Remote: gem.Remote, Revision: gem.Revision}
+	dep.Requirement, dep.Direct = p.lock.dependencies[gem.Name]
+	dep.Dir = p.locate(gem)
+	return dep
+}
+
+// locate returns the directory of an installed gem: the path of PATH gems,
+// bundler/gems/<repository>-<short revision> for git gems and
+// gems/<name>-<version>[-<platform>] otherwise.
+func (p *Project) locate(gem *LockedGem) string {
+	switch gem.Source {
+	case SourcePath:
+		dir := gem.Remote
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(p.Dir, filepath.FromSlash(dir))
+		}
+		if fsutil.IsDir(dir) {
+			return dir
+		}
+	case SourceGit:
+		if len(gem.Revision) < 12 {
+			return ""
+		}
+		repo := strings.TrimSuffix(filepath.Base(strings.TrimSuffix(gem.Remote, "/")), ".git")
+		for _, gemDir := range p.GemDirs {
+			checkout := filepath.Join(gemDir, "bundler", "gems", repo+"-"+gem.Revision[:12])
+			if !fsutil.IsDir(checkout) {
+				continue
+			}
+			// Repositories such as rails hold several gems, one per
+			// directory.
+			if !fsutil.IsFile(filepath.Join(checkout, gem.Name+".gemspec")) && fsutil.IsFile(filepath.Join(checkout, gem.Name, gem.Name+".gemspec")) {
+				return filepath.Join(checkout, gem.Name)
+			}
+			return checkout
+		}
+	default:
+		names := []string{gem.Name + "-" + gem.Version}
+		for _, platform := range gem.Platforms {
+			names = append(names, gem.Name+"-"+gem.Version+"-"+platform)
+		}
+		for _, gemDir := range p.GemDirs {
+			for _, name := range names {
+				if dir := filepath.Join(gemDir, "gems", name); fsutil.IsDir(dir) {
+					return dir
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// gemDirs returns the gem installation directories that exist. bundler
+// installs into <BUNDLE_PATH>/ruby/<ABI version> when a path is configured
+// in the environment, the project's .bundle/config or ~/.bundle/config.
+func (p *Project) gemDirs() []string {
+	var candidates []string
+	home, _ := os.UserHomeDir()
+
+	bundlePath := os.Getenv("BUNDLE_PATH")
+	for _, config := range []string{filepath.Join(p.Dir, ".bundle", "config"), filepath.Join(home, ".bundle", "config")} {
+		if bundlePath != "" {
+			break
+		}
+		settings := readBundleConfig(config)
+		bundlePath = settings["BUNDLE_PATH"]
+		if bundlePath == "" && settings["BUNDLE_DEPLOYMENT"] == "true" {
+			bundlePath = "vendor/bundle"
+		}
+	}
+	if bundlePath != "" {
+		if strings.HasPrefix(bundlePath, "~/") {
+			bundlePath = filepath.Join(home, bundlePath[2:])
+		} else if !filepath.IsAbs(bundlePath) {
+			bundlePath = filepath.Join(p.Dir, filepath.FromSlash(bundlePath))
+		}
+		candidates = append(candidates, globNewest(filepath.Join(bundlePath, "ruby", "*"))...)
+	}
+
+	if gemHome := os.Getenv("GEM_HOME"); gemHome != "" {
+		candidates = append(candidates, gemHome)
+	}
+	candidates = append(candidates, filepath.SplitList(os.Getenv("GEM_PATH"))...)
+	if home != "" {
+		candidates = append(candidates, globNewest(filepath.Join(home, ".gem", "ruby", "*"))...)
+		candidates = append(candidates, globNewest(filepath.Join(home, ".local", "share", "gem", "ruby", "*"))...)
+		candidates = append(candidates, globNewest(filepath.Join(home, ".rbenv", "versions", "*", "lib", "ruby", "gems", "*"))...)
+		candidates = append(candidates, globNewest(filepath.Join(home, ".rvm", "gems", "*"))...)
+	}
+	candidates = append(candidates, globNewest("/usr/local/lib/ruby/gems/*")...)
+	candidates = append(candidates, globNewest("/var/lib/gems/*")...)
+	candidates = append(candidates, globNewest("/usr/lib/ruby/gems/*")...)
+
+	dirs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, dir := range candidates {
+		if dir == "" || seen[dir] || !fsutil.IsDir(filepath.Join(dir, "gems")) {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// readBundleConfig reads the KEY: "value" settings of a bundler config
+// file.
+func readBundleConfig(path string) map[string]string {
+	settings := make(map[string]string)
+	f, err := os.Open(path)
+	if err != nil {
+		return settings
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || !strings.HasPrefix(key, "BUNDLE_") {
+			continue
+		}
+		settings[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return settings
+}
+
+// globNewest returns the matches of pattern, the highest versions first.
+func globNewest(pattern string) []string {
+	matches, _ := filepath.Glob(pattern)
+	sort.Sort(sort.Reverse(sort.StringSlice(matches)))
+	return matches
+}
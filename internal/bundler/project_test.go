@@ -0,0 +1,147 @@
+package bundler_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/bundler"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func dependenciesByName(project *bundler.Project) map[string]bundler.Dependency {
+	deps := make(map[string]bundler.Dependency)
+	for _, dep := range project.Dependencies() {
+		deps[dep.Name] = dep
+	}
+	return deps
+}
+
+const gemfileLock = `GIT
+  remote: https://github.com/rails/rails.git
+  revision: 0123456789abcdef0123456789abcdef01234567
+  branch: main
+  specs:
+    activesupport (7.2.0.alpha)
+      concurrent-ruby (~> 1.0, >= 1.0.2)
+
+PATH
+  remote: engines/billing
+  specs:
+    billing (0.1.0)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    concurrent-ruby (1.2.3)
+    nokogiri (1.16.0)
+      racc (~> 1.4)
+    nokogiri (1.16.0-x86_64-linux)
+      racc (~> 1.4)
+    racc (1.7.3)
+    rake (13.1.0)
+
+PLATFORMS
+  ruby
+  x86_64-linux
+
+DEPENDENCIES
+  activesupport!
+  billing!
+  nokogiri (~> 1.16)
+  rake (>= 13)
+
+BUNDLED WITH
+   2.5.6
+`
+
+func TestLoadProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	app := filepath.Join(tmpDir, "app")
+	gemHome := filepath.Join(tmpDir, "gemhome")
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"app/Gemfile": `source "https://rubygems.org"
+
+gem "activesupport", github: "rails/rails"
+gem "billing", path: "engines/billing"
+gem 'nokogiri', '~> 1.16'
+gem "rake", ">= 13"
+`,
+		"app/Gemfile.lock":                    gemfileLock,
+		"app/.bundle/config":                  "---\nBUNDLE_PATH: \"vendor/bundle\"\n",
+		"app/engines/billing/billing.gemspec": "",
+		"app/lib/tasks/.keep":                 "",
+		"app/vendor/bundle/ruby/3.3.0/gems/nokogiri-1.16.0-x86_64-linux/lib/nokogiri.rb":                   "",
+		"app/vendor/bundle/ruby/3.3.0/gems/racc-1.7.3/lib/racc.rb":                                         "",
+		"app/vendor/bundle/ruby/3.3.0/bundler/gems/rails-0123456789ab/activesupport/activesupport.gemspec": "",
+		"gemhome/gems/rake-13.1.0/lib/rake.rb":                                                             "",
+		"gemhome/gems/concurrent-ruby-1.2.2/lib/concurrent-ruby.rb":                                        "",
+	})
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("BUNDLE_PATH", "")
+	t.Setenv("GEM_HOME", gemHome)
+	t.Setenv("GEM_PATH", "")
+
+	project, err := bundler.LoadProject(filepath.Join(app, "lib", "tasks"))
+	require.NoError(t, err)
+	assert.Equal(t, app, project.Dir)
+	assert.Equal(t, filepath.Join(app, "Gemfile.lock"), project.Lockfile)
+	assert.Equal(t, "2.5.6", project.BundledWith)
+	require.GreaterOrEqual(t, len(project.GemDirs), 2)
+	assert.Equal(t, []string{filepath.Join(app, "vendor", "bundle", "ruby", "3.3.0"), gemHome}, project.GemDirs[:2], "The bundler path comes before GEM_HOME and the system gems")
+	assert.Empty(t, project.Warnings())
+
+	var names []string
+	for _, dep := range project.Dependencies() {
+		names = append(names, dep.Name)
+	}
+	assert.Equal(t, []string{"activesupport", "billing", "nokogiri", "rake", "concurrent-ruby", "racc"}, names, "Gems of the Gemfile come first")
+
+	deps := dependenciesByName(project)
+	assert.Equal(t, bundler.Dependency{
+		Name:     "activesupport",
+		Direct:   true,
+		Locked:   "7.2.0.alpha",
+		Source:   bundler.SourceGit,
+		Remote:   "https://github.com/rails/rails.git",
+		Revision: "0123456789abcdef0123456789abcdef01234567",
+		Dir:      filepath.Join(app, "vendor", "bundle", "ruby", "3.3.0", "bundler", "gems", "rails-0123456789ab", "activesupport"),
+	}, deps["activesupport"], "Git gems are found in their repository checkout")
+	assert.Equal(t, filepath.Join(app, "engines", "billing"), deps["billing"].Dir)
+	assert.Equal(t, "~> 1.16", deps["nokogiri"].Requirement)
+	assert.Equal(t, "1.16.0", deps["nokogiri"].Locked)
+	assert.Equal(t, filepath.Join(app, "vendor", "bundle", "ruby", "3.3.0", "gems", "nokogiri-1.16.0-x86_64-linux"), deps["nokogiri"].Dir, "Precompiled gems carry their platform")
+	assert.Equal(t, filepath.Join(gemHome, "gems", "rake-13.1.0"), deps["rake"].Dir, "Gems are also found in GEM_HOME")
+	assert.False(t, deps["racc"].Direct)
+	assert.Empty(t, deps["concurrent-ruby"].Dir, "Only the locked version is used")
+
+	dep, err := project.Dependency("racc")
+	require.NoError(t, err)
+	assert.Equal(t, "1.7.3", dep.Locked)
+	_, err = project.Dependency("rails")
+	assert.ErrorContains(t, err, "gem rails is not a dependency")
+}
+
+func TestLoadProjectWithoutLockfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"Gemfile": "source 'https://rubygems.org'\ngem 'sinatra', '~> 4.0', '>= 4.0.1'\ngem('puma')\n",
+	})
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("BUNDLE_PATH", "")
+	t.Setenv("GEM_HOME", "")
+	t.Setenv("GEM_PATH", "")
+
+	project, err := bundler.LoadProject(tmpDir)
+	require.NoError(t, err)
+	assert.Empty(t, project.Lockfile)
+	assert.Contains(t, project.Warnings(), "no Gemfile.lock found; run bundle lock to pin versions")
+	assert.Equal(t, []bundler.Dependency{
+		{Name: "puma", Direct: true},
+		{Name: "sinatra", Requirement: "~> 4.0, >= 4.0.1", Direct: true},
+	}, project.Dependencies())
+
+	_, err = bundler.LoadProject(t.TempDir())
+	assert.ErrorIs(t, err, bundler.ErrNoGemfile)
+}
@@ -0,0 +1,411 @@
+package cargo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+)
+
+// Crate is the module tree of a library crate, found by a lightweight parse
+// of its sources: modules, items and pub use re-exports are recognized line
+// by line, without expanding macros or evaluating cfg attributes.
+type Crate struct {
+	Name    string    `json:"name"`
+	Version string    `json:"version"`
+	Dir     string    `json:"dir"`
+	Modules []*Module `json:"modules"`
+
+	lines map[string][]string
+}
+
+// Module is a module of a crate. Path is the module path below the crate
+// root, empty for the root itself.
+type Module struct {
+	Path string `json:"path"`
+	File string `json:"file"`
+	// Line is the line of an inline mod block, 0 for a module file.
+	Line int `json:"line,omitempty"`
+	// Public reports that the module and all its parents are pub.
+	Public    bool       `json:"public"`
+	Items     []Item     `json:"items"`
+	Reexports []Reexport `json:"reexports,omitempty"`
+}
+
+// Item is an item declared in a module: a fn, struct, enum, trait, type,
+// const, static, union, macro, or a method of an impl block.
+type Item struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	// Parent is the type of the impl block declaring a method.
+	Parent string `json:"parent,omitempty"`
+	Public bool   `json:"public"`
+	Line   int    `json:"line"`
+}
+
+// Reexport is a name a pub use declaration exports from a module, e.g.
+// Mutex for pub use self::mutex::Mutex. Name is * for glob re-exports.
+type Reexport struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Line int    `json:"line"`
+}
+
+var (
+	itemRE  = regexp.MustCompile(`^(pub(?:\s*\([^)]*\))?\s+)?(?:(?:default|const|async|unsafe|extern(?:\s+"[^"]*")?)\s+)*(fn|struct|enum|trait|type|const|static|union|mod)\s+(?:mut\s+)?(?:r#)?([A-Za-z_][A-Za-z0-9_]*)`)
+	macroRE = regexp.MustCompile(`^macro_rules!\s*([A-Za-z_][A-Za-z0-9_]*)`)
+	useRE   = regexp.MustCompile(`^(pub(?:\s*\([^)]*\))?\s+)?use\s`)
+	implRE  = regexp.MustCompile(`^(?:unsafe\s+)?impl\b`)
+	// Macro invocations such as cfg_feature! { ... } usually wrap items;
+	// their bodies are scanned as part of the enclosing module or impl.
+	macroBlockRE = regexp.MustCompile(`^(?:[a-z_][a-z0-9_]*::)*[a-z_][a-z0-9_]*!\s*\{`)
+	pathAttrRE   = regexp.MustCompile(`^#\[path\s*=\s*"([^"]+)"\]`)
+)
+
+// ParseCrate parses the library crate in dir, starting from the [lib] path
+// of its Cargo.toml or src/lib.rs.
+func ParseCrate(dir string) (*Crate, error) {
+	manifest, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	c := &Crate{
+		Name:    tomlString(tomlTable(manifest, "package"), "name"),
+		Version: tomlString(tomlTable(manifest, "package"), "version"),
+		Dir:     dir,
+		Modules: make([]*Module, 0),
+		lines:   make(map[string][]string),
+	}
+	root := filepath.Join(dir, "src", "lib.rs")
+	if path := tomlString(tomlTable(manifest, "lib"), "path"); path != "" {
+		root = filepath.Join(dir, filepath.FromSlash(path))
+	}
+	if !fsutil.IsFile(root) {
+		return nil, fmt.Errorf("%s has no library target (%s)", c.Name, root)
+	}
+
+	if err := c.parseFile(root, "", filepath.Dir(root), true); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(c.Modules, func(i, j int) bool {
+		return c.Modules[i].Path < c.Modules[j].Path
+	})
+	return c, nil
+}
+
+// Module returns the module with the given path, or nil.
+func (c *Crate) Module(path string) *Module {
+	for _, mod := range c.Modules {
+		if mod.Path == path {
+			return mod
+		}
+	}
+	return nil
+}
+
+// frame is a block being scanned: a module body, an impl block or a macro
+// invocation wrapping items of the enclosing one.
+type frame struct {
+	module *Module
+	// dir is the directory of the files of child modules.
+	dir       string
+	bodyDepth int
+	endLine   int
+	impl      string
+	traitImpl bool
+}
+
+func (c *Crate) parseFile(file, modPath, dir string, public bool) error {
+	if c.Module(modPath) != nil {
+		// Another cfg variant of a module already parsed.
+		return nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	original := strings.Split(string(data), "\n")
+	lines := strings.Split(blankCode(string(data)), "\n")
+	c.lines[file] = original
+	depths := braceDepths(lines)
+
+	type childFile struct {
+		file, path, dir string
+		public          bool
+	}
+	var children []childFile
+
+	root := &Module{Path: modPath, File: file, Public: public, Items: make([]Item, 0)}
+	c.Modules = append(c.Modules, root)
+	stack := []frame{{module: root, dir: dir, endLine: len(lines)}}
+	for i := 0; i < len(lines); i++ {
+		for len(stack) > 1 && i > stack[len(stack)-1].endLine {
+			stack = stack[:len(stack)-1]
+		}
+		top := stack[len(stack)-1]
+		if depths[i] != top.bodyDepth {
+			continue
+		}
+		line := strings.TrimSpace(lines[i])
+		mod := top.module
+
+		if macroBlockRE.MatchString(line) && !strings.HasPrefix(line, "macro_rules!") {
+			end := itemEnd(lines, i, false)
+			stack = append(stack, frame{module: mod, dir: top.dir, bodyDepth: depths[i] + 1, endLine: end, impl: top.impl, traitImpl: top.traitImpl})
+			continue
+		}
+
+		if top.impl != "" {
+			if m := itemRE.FindStringSubmatch(line); m != nil && m[2] == "fn" {
+				mod.Items = append(mod.Items, Item{
+					Name:   m[3],
+					Kind:   "method",
+					Parent: top.impl,
+					Public: strings.TrimSpace(m[1]) == "pub" || top.traitImpl,
+					Line:   i + 1,
+				})
+			}
+			continue
+		}
+
+		switch {
+		case useRE.MatchString(line):
+			m := useRE.FindStringSubmatch(line)
+			end := itemEnd(lines, i, true)
+			if strings.TrimSpace(m[1]) == "pub" {
+				statement := strings.Join(lines[i:end+1], " ")
+				statement = statement[strings.Index(statement, "use")+len("use"):]
+				statement, _, _ = strings.Cut(statement, ";")
+				for _, reexport := range expandUse(statement) {
+					reexport.Line = i + 1
+					mod.Reexports = append(mod.Reexports, reexport)
+				}
+			}
+			i = end
+
+		case implRE.MatchString(line):
+			end := itemEnd(lines, i, false)
+			self, trait := implType(strings.Join(lines[i:end+1], " "))
+			stack = append(stack, frame{module: mod, dir: top.dir, bodyDepth: depths[i] + 1, endLine: end, impl: self, traitImpl: trait})
+
+		case macroRE.MatchString(line):
+			name := macroRE.FindStringSubmatch(line)[1]
+			mod.Items = append(mod.Items, Item{Name: name, Kind: "macro", Public: hasAttribute(original, i, "macro_export"), Line: i + 1})
+			i = itemEnd(lines, i, false)
+
+		case itemRE.MatchString(line):
+			m := itemRE.FindStringSubmatch(line)
+			visibility, kind, name := strings.TrimSpace(m[1]), m[2], m[3]
+			switch kind {
+			case "trait":
+				// Trait methods are listed like the methods of an impl block.
+				mod.Items = append(mod.Items, Item{Name: name, Kind: kind, Public: visibility == "pub", Line: i + 1})
+				end := itemEnd(lines, i, false)
+				stack = append(stack, frame{module: mod, dir: top.dir, bodyDepth: depths[i] + 1, endLine: end, impl: name, traitImpl: visibility == "pub"})
+				continue
+			case "mod":
+			default:
+				mod.Items = append(mod.Items, Item{Name: name, Kind: kind, Public: visibility == "pub", Line: i + 1})
+				i = itemEnd(lines, i, kind == "const" || kind == "static" || kind == "type")
+				continue
+			}
+
+			childPath := joinPath(mod.Path, name)
+			childPublic := mod.Public && visibility == "pub"
+			if semicolon, brace := strings.IndexByte(line, ';'), strings.IndexByte(line, '{'); semicolon >= 0 && (brace < 0 || semicolon < brace) {
+				child := childFile{path: childPath, public: childPublic}
+				if custom := pathAttribute(original, i); custom != "" {
+					// Paths are relative to the file, or inside an inline
+					// module to the directory it stands for.
+					base := filepath.Dir(file)
+					if mod.Line > 0 {
+						base = top.dir
+					}
+					child.file = filepath.Join(base, filepath.FromSlash(custom))
+					child.dir = strings.TrimSuffix(child.file, ".rs")
+					if filepath.Base(child.file) == "mod.rs" {
+						child.dir = filepath.Dir(child.file)
+					}
+				} else if candidate := filepath.Join(top.dir, name+".rs"); fsutil.IsFile(candidate) {
+					child.file, child.dir = candidate, filepath.Join(top.dir, name)
+				} else if candidate := filepath.Join(top.dir, name, "mod.rs"); fsutil.IsFile(candidate) {
+					child.file, child.dir = candidate, filepath.Join(top.dir, name)
+				}
+				if child.file != "" && fsutil.IsFile(child.file) {
+					children = append(children, child)
+				}
+				continue
+			}
+
+			end := itemEnd(lines, i, false)
+			inline := &Module{Path: childPath, File: file, Line: i + 1, Public: childPublic, Items: make([]Item, 0)}
+			if c.Module(childPath) == nil {
+				c.Modules = append(c.Modules, inline)
+			}
+			stack = append(stack, frame{module: inline, dir: filepath.Join(top.dir, name), bodyDepth: depths[i] + 1, endLine: end})
+		}
+	}
+
+	for _, child := range children {
+		if err := c.parseFile(child.file, child.path, child.dir, child.public); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// implType returns the self type of an impl block header and whether it
+// implements a trait.
+func implType(header string) (string, bool) {
+	header, _, _ = strings.Cut(header, "{")
+	header, _, _ = strings.Cut(header, " where ")
+	header = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(header), "unsafe"))
+	header = strings.TrimSpace(strings.TrimPrefix(header, "impl"))
+	header = skipGenerics(header)
+	trait := false
+	if _, self, ok := strings.Cut(header, " for "); ok {
+		header, trait = self, true
+	}
+	header = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(header), "&*"))
+	header = strings.TrimSpace(strings.TrimPrefix(header, "mut "))
+	header = strings.TrimSpace(strings.TrimPrefix(header, "dyn "))
+	end := 0
+	for end < len(header) && (isIdentByte(header[end]) || header[end] == ':') {
+		end++
+	}
+	path := header[:end]
+	if i := strings.LastIndex(path, "::"); i >= 0 {
+		path = path[i+2:]
+	}
+	return path, trait
+}
+
+// skipGenerics removes a leading <...> parameter list.
+func skipGenerics(s string) string {
+	if !strings.HasPrefix(s, "<") {
+		return s
+	}
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '<':
+			depth++
+		case '>':
+			if i > 0 && s[i-1] == '-' {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				return strings.TrimSpace(s[i+1:])
+			}
+		}
+	}
+	return s
+}
+
+// hasAttribute reports whether the attributes above line include name.
+func hasAttribute(lines []string, line int, name string) bool {
+	for i := line - 1; i >= 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, "#[") && !strings.HasPrefix(trimmed, "///") {
+			return false
+		}
+		if strings.Contains(trimmed, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathAttribute returns the value of a #[path = "..."] attribute above
+// line.
+func pathAttribute(lines []string, line int) string {
+	for i := line - 1; i >= 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, "#[") && !strings.HasPrefix(trimmed, "///") {
+			return ""
+		}
+		if m := pathAttrRE.FindStringSubmatch(trimmed); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "::" + name
+}
+
+// expandUse returns the names a use tree such as
+// self::mutex::{Mutex, MutexGuard as Guard} brings into scope, with their
+// full paths.
+func expandUse(tree string) []Reexport {
+	tokens := useTokenRE.FindAllString(tree, -1)
+	var reexports []Reexport
+	var parse func(prefix []string)
+	pos := 0
+	parse = func(prefix []string) {
+		segments := append([]string(nil), prefix...)
+		if pos < len(tokens) && tokens[pos] == "::" {
+			pos++
+		}
+		for pos < len(tokens) {
+			switch token := tokens[pos]; token {
+			case "{":
+				pos++
+				for pos < len(tokens) && tokens[pos] != "}" {
+					parse(segments)
+					if pos < len(tokens) && tokens[pos] == "," {
+						pos++
+					}
+				}
+				pos++
+				return
+			case "*":
+				pos++
+				reexports = append(reexports, Reexport{Name: "*", Path: strings.Join(append(segments, "*"), "::")})
+				return
+			case ",", "}":
+				return
+			default:
+				pos++
+				segments = append(segments, strings.TrimPrefix(token, "r#"))
+				if pos < len(tokens) && tokens[pos] == "::" {
+					pos++
+					continue
+				}
+				name := segments[len(segments)-1]
+				path := segments
+				if name == "self" && len(segments) > 1 {
+					path = segments[:len(segments)-1]
+					name = path[len(path)-1]
+				}
+				if pos+1 < len(tokens) && tokens[pos] == "as" {
+					name = tokens[pos+1]
+					pos += 2
+				}
+				if name != "_" {
+					reexports = append(reexports, Reexport{Name: name, Path: strings.Join(path, "::")})
+				}
+				return
+			}
+		}
+	}
+	for pos < len(tokens) {
+		before := pos
+		parse(nil)
+		if pos == before {
+			pos++
+		}
+	}
+	return reexports
+}
+
+var useTokenRE = regexp.MustCompile(`::|[{},*]|(?:r#)?[A-Za-z_][A-Za-z0-9_]*`)
@@ -0,0 +1,165 @@
+package cargo_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/cargo"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func TestParseCrate(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"Cargo.toml": "[package]\nname = \"mini-tokio\"\nversion = \"1.0.0\"\n",
+		"src/lib.rs": `//! A runtime.
+
+pub mod sync;
+mod util;
+
+#[doc(hidden)]
+pub mod macros {
+    /// Waits on several futures.
+    #[macro_export]
+    macro_rules! join {
+        ($($e:expr),*) => {};
+    }
+}
+
+cfg_rt! {
+    pub mod runtime;
+}
+
+pub use util::spawn;
+`,
+		"src/sync/mod.rs": `mod mutex;
+pub use self::mutex::{Mutex, MutexGuard as Guard};
+
+pub(crate) fn internal() {}
+`,
+		"src/sync/mutex.rs": `use std::cell::UnsafeCell;
+
+/// An asynchronous mutex.
+///
+/// Braces in "strings { like this" and '{' characters are ignored.
+#[derive(Debug)]
+pub struct Mutex<T: ?Sized> {
+    c: UnsafeCell<T>,
+}
+
+pub struct MutexGuard<'a, T> {
+    lock: &'a Mutex<T>,
+}
+
+impl<T> Mutex<T> {
+    /// Creates a new lock.
+    pub const fn new(t: T) -> Mutex<T> {
+        let _ = "}";
+        Mutex { c: UnsafeCell::new(t) }
+    }
+
+    fn inner(&self) {}
+}
+
+impl<T> Drop for Mutex<T> {
+    fn drop(&mut self) {}
+}
+
+pub const MAX_READS: u32 = {
+    1 << 29
+};
+`,
+		"src/util.rs": `pub fn spawn() {}
+`,
+		"src/runtime.rs": `pub trait Handle {
+    fn enter(&self);
+}
+
+pub struct Runtime;
+
+impl Runtime {
+    cfg_rt! {
+        pub fn block_on(&self) {}
+    }
+}
+`,
+	})
+
+	crate, err := cargo.ParseCrate(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, "mini-tokio", crate.Name)
+
+	var paths []string
+	for _, mod := range crate.Modules {
+		paths = append(paths, mod.Path)
+	}
+	assert.Equal(t, []string{"", "macros", "runtime", "sync", "sync::mutex", "util"}, paths)
+	assert.True(t, crate.Module("sync").Public)
+	assert.False(t, crate.Module("sync::mutex").Public)
+	assert.True(t, crate.Module("runtime").Public, "Modules inside macro invocations are found")
+	assert.Equal(t, []cargo.Reexport{
+		{Name: "Mutex", Path: "self::mutex::Mutex", Line: 2},
+		{Name: "Guard", Path: "self::mutex::MutexGuard", Line: 2},
+	}, crate.Module("sync").Reexports)
+
+	macros := crate.Module("macros")
+	require.Len(t, macros.Items, 1)
+	assert.Equal(t, cargo.Item{Name: "join", Kind: "macro", Public: true, Line: 10}, macros.Items[0])
+
+	mutex := crate.Module("sync::mutex")
+	assert.Equal(t, []cargo.Item{
+		{Name: "Mutex", Kind: "struct", Public: true, Line: 7},
+		{Name: "MutexGuard", Kind: "struct", Public: true, Line: 11},
+		{Name: "new", Kind: "method", Parent: "Mutex", Public: true, Line: 17},
+		{Name: "inner", Kind: "method", Parent: "Mutex", Line: 22},
+		{Name: "drop", Kind: "method", Parent: "Mutex", Public: true, Line: 26},
+		{Name: "MAX_READS", Kind: "const", Public: true, Line: 29},
+	}, mutex.Items)
+
+	source, err := crate.FindItem("mini_tokio::sync::Mutex")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmpDir, "src", "sync", "mutex.rs"), source.File)
+	assert.Equal(t, 3, source.StartLine, "Doc comments and attributes are included")
+	assert.Equal(t, 9, source.EndLine)
+	assert.Equal(t, []string{"sync: pub use self::mutex::Mutex"}, source.Via)
+
+	source, err = crate.FindItem("sync::Mutex::new")
+	require.NoError(t, err)
+	assert.Equal(t, 16, source.StartLine)
+	assert.Equal(t, 20, source.EndLine)
+
+	source, err = crate.FindItem("sync::Guard")
+	require.NoError(t, err)
+	assert.Equal(t, "MutexGuard", source.Item.Name)
+
+	source, err = crate.FindItem("sync::mutex::MAX_READS")
+	require.NoError(t, err)
+	assert.Equal(t, 31, source.EndLine)
+
+	source, err = crate.FindItem("spawn")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmpDir, "src", "util.rs"), source.File)
+
+	source, err = crate.FindItem("runtime::Runtime::block_on")
+	require.NoError(t, err, "Methods inside macro invocations are found")
+	assert.Equal(t, 9, source.StartLine)
+
+	source, err = crate.FindItem("runtime::Handle::enter")
+	require.NoError(t, err)
+	assert.Equal(t, "method", source.Item.Kind)
+
+	source, err = crate.FindItem("join")
+	require.NoError(t, err, "Exported macros are found at the crate root")
+	assert.Equal(t, "macros", source.Module.Path)
+
+	source, err = crate.FindItem("macros")
+	require.NoError(t, err)
+	assert.Nil(t, source.Item)
+	assert.Equal(t, 6, source.StartLine)
+	assert.Equal(t, 13, source.EndLine)
+
+	_, err = crate.FindItem("sync::RwLock")
+	assert.ErrorContains(t, err, "no item RwLock in module sync")
+}
@@ -0,0 +1,418 @@
+// Package cargo resolves the dependencies of Rust projects through
+// Cargo.toml and Cargo.lock, locates the crate sources cargo unpacked in
+// its registry and git caches, and parses the public items of a crate.
+package cargo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+)
+
+// ErrNoManifest is returned when no Cargo.toml can be found for a
+// directory.
+var ErrNoManifest = errors.New("Cargo.toml not found")
+
+// Dependency kinds, named after their Cargo.toml tables. KindTransitive
+// marks a crate only found in Cargo.lock.
+const (
+	KindNormal     = "dependencies"
+	KindDev        = "dev-dependencies"
+	KindBuild      = "build-dependencies"
+	KindTransitive = "transitive"
+)
+
+var dependencyKinds = []string{KindNormal, KindDev, KindBuild}
+
+// Dependency is a crate the project's Cargo.toml depends on.
+type Dependency struct {
+	// Name is the name the crate is used under, and Package the name of
+	// the crate it renames, if any.
+	Name    string `json:"name"`
+	Package string `json:"package,omitempty"`
+	// Requirement is the version requirement Cargo.toml declares.
+	Requirement string `json:"requirement,omitempty"`
+	Kind        string `json:"kind"`
+	// Target is the cfg expression or target triple of a
+	// [target.<target>.dependencies] table.
+	Target   string `json:"target,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	// Locked is the version Cargo.lock resolves the dependency to, and
+	// Source where it comes from: registry+<index>, git+<url>#<commit> or
+	// empty for path dependencies.
+	Locked string `json:"locked,omitempty"`
+	Source string `json:"source,omitempty"`
+	Path   string `json:"path,omitempty"`
+	// Dir is the directory of the crate sources, when found.
+	Dir string `json:"dir,omitempty"`
+}
+
+// CrateName returns the name of the crate on its registry.
+func (d *Dependency) CrateName() string {
+	if d.Package != "" {
+		return d.Package
+	}
+	return d.Name
+}
+
+// LockedPackage is a [[package]] entry of Cargo.lock.
+type LockedPackage struct {
+	Name         string
+	Version      string
+	Source       string
+	Dependencies []string
+}
+
+// Project is a Rust package on disk.
+type Project struct {
+	Dir string
+	// Name and Version come from the [package] table; a virtual workspace
+	// manifest has neither.
+	Name    string
+	Version string
+	// Lockfile is the path of Cargo.lock, empty when there is none.
+	Lockfile string
+	// CargoHome is the directory holding cargo's registry and git caches.
+	CargoHome string
+
+	manifest     map[string]any
+	workspace    map[string]any
+	workspaceDir string
+	locked       []LockedPackage
+	warnings     []string
+}
+
+// LoadProject finds the Cargo.toml governing dir, walking up the directory
+// tree, and the Cargo.lock next to it or at the root of its workspace.
+func LoadProject(dir string) (*Project, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	rootDir, ok := fsutil.FindUp(absDir, "Cargo.toml")
+	if !ok {
+		return nil, fmt.Errorf("%w in %s or any parent directory", ErrNoManifest, absDir)
+	}
+	manifest, err := readManifest(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Project{Dir: rootDir, manifest: manifest, CargoHome: cargoHome()}
+	if pkg := tomlTable(manifest, "package"); pkg != nil {
+		p.Name = tomlString(pkg, "name")
+		p.Version = tomlString(pkg, "version")
+	}
+
+	// Members of a workspace share the lockfile and [workspace.dependencies]
+	// of the root manifest.
+	for current := rootDir; ; {
+		if p.workspace == nil {
+			if root, err := readManifest(current); err == nil && tomlTable(root, "workspace") != nil {
+				p.workspace = tomlTable(root, "workspace")
+				p.workspaceDir = current
+			}
+		}
+		if lockfile := filepath.Join(current, "Cargo.lock"); fsutil.IsFile(lockfile) && p.Lockfile == "" {
+			if err := p.loadLockfile(lockfile); err != nil {
+				return nil, err
+			}
+		}
+		parent := filepath.Dir(current)
+		if parent == current || (p.workspace != nil && p.Lockfile != "") {
+			break
+		}
+		current = parent
+	}
+	if p.Lockfile == "" {
+		p.warnings = append(p.warnings, "no Cargo.lock found; run cargo generate-lockfile to pin versions")
+	}
+	return p, nil
+}
+
+func readManifest(dir string) (map[string]any, error) {
+	path := filepath.Join(dir, "Cargo.toml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := parseTOML(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return manifest, nil
+}
+
+func (p *Project) loadLockfile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lock, err := parseTOML(string(data))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	packages, _ := lock["package"].([]any)
+	for _, entry := range packages {
+		table, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		pkg := LockedPackage{
+			Name:    tomlString(table, "name"),
+			Version: tomlString(table, "version"),
+			Source:  tomlString(table, "source"),
+		}
+		deps, _ := table["dependencies"].([]any)
+		for _, dep := range deps {
+			if s, ok := dep.(string); ok {
+				pkg.Dependencies = append(pkg.Dependencies, s)
+			}
+		}
+		p.locked = append(p.locked, pkg)
+	}
+	p.Lockfile = path
+	return nil
+}
+
+// Warnings reports problems found while loading the project.
+func (p *Project) Warnings() []string {
+	return p.warnings
+}
+
+// Dependencies lists the dependencies declared in Cargo.toml, including
+// target-specific ones, sorted by kind and name.
+func (p *Project) Dependencies() []Dependency {
+	deps := make([]Dependency, 0)
+	add := func(tables map[string]any, target string) {
+		for _, kind := range dependencyKinds {
+			for name, spec := range tomlTable(tables, kind) {
+				deps = append(deps, p.dependency(name, spec, kind, target))
+			}
+		}
+	}
+	add(p.manifest, "")
+	for target, tables := range tomlTable(p.manifest, "target") {
+		if tables, ok := tables.(map[string]any); ok {
+			add(tables, target)
+		}
+	}
+	// A virtual workspace manifest only declares shared dependencies.
+	if tomlTable(p.manifest, "package") == nil {
+		for name, spec := range tomlTable(tomlTable(p.manifest, "workspace"), "dependencies") {
+			deps = append(deps, p.dependency(name, spec, KindNormal, ""))
+		}
+	}
+
+	kindOrder := map[string]int{KindNormal: 0, KindDev: 1, KindBuild: 2}
+	sort.SliceStable(deps, func(i, j int) bool {
+		if deps[i].Kind != deps[j].Kind {
+			return kindOrder[deps[i].Kind] < kindOrder[deps[j].Kind]
+		}
+		if deps[i].Name != deps[j].Name {
+			return deps[i].Name < deps[j].Name
+		}
+		return deps[i].Target < deps[j].Target
+	})
+	return deps
+}
+
+// Dependency returns the dependency used under name, or the locked package
+// with that name when the project depends on it indirectly.
+func (p *Project) Dependency(name string) (*Dependency, error) {
+	normalized := strings.ReplaceAll(name, "-", "_")
+	for _, dep := range p.Dependencies() {
+		if strings.ReplaceAll(dep.Name, "-", "_") == normalized || strings.ReplaceAll(dep.CrateName(), "-", "_") == normalized {
+			return &dep, nil
+		}
+	}
+	// Fall back to the newest locked version of a transitive dependency.
+	var found *LockedPackage
+	for i, pkg := range p.locked {
+		if strings.ReplaceAll(pkg.Name, "-", "_") != normalized || pkg.Source == "" {
+			continue
+		}
+		if found == nil || compareVersions(pkg.Version, found.Version) > 0 {
+			found = &p.locked[i]
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%s is not a dependency of %s", name, p.Dir)
+	}
+	dep := &Dependency{Name: found.Name, Kind: KindTransitive, Locked: found.Version, Source: found.Source}
+	dep.Dir = p.locateSource(found.Name, found.Version, found.Source)
+	return dep, nil
+}
+
+func (p *Project) dependency(name string, spec any, kind, target string) Dependency {
+	dep := Dependency{Name: name, Kind: kind, Target: target}
+	switch spec := spec.(type) {
+	case string:
+		dep.Requirement = spec
+	case map[string]any:
+		if inherited, _ := spec["workspace"].(bool); inherited {
+			switch shared := tomlTable(p.workspace, "dependencies")[name].(type) {
+			case string:
+				dep.Requirement = shared
+			case map[string]any:
+				dep.Requirement = tomlString(shared, "version")
+				dep.Package = tomlString(shared, "package")
+				if path := tomlString(shared, "path"); path != "" {
+					dep.Path = filepath.Join(p.workspaceDir, filepath.FromSlash(path))
+				}
+			}
+		}
+		if version := tomlString(spec, "version"); version != "" {
+			dep.Requirement = version
+		}
+		if pkg := tomlString(spec, "package"); pkg != "" {
+			dep.Package = pkg
+		}
+		if path := tomlString(spec, "path"); path != "" {
+			dep.Path = path
+		}
+		if git := tomlString(spec, "git"); git != "" && dep.Requirement == "" {
+			dep.Requirement = git
+		}
+		dep.Optional, _ = spec["optional"].(bool)
+	}
+
+	if dep.Path != "" {
+		dir := dep.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(p.Dir, filepath.FromSlash(dir))
+		}
+		if fsutil.IsDir(dir) {
+			dep.Dir = dir
+		}
+	}
+	if locked := p.lockedPackage(dep.CrateName()); locked != nil {
+		dep.Locked = locked.Version
+		dep.Source = locked.Source
+		if dep.Dir == "" {
+			dep.Dir = p.locateSource(locked.Name, locked.Version, locked.Source)
+		}
+	}
+	return dep
+}
+
+// lockedPackage returns the locked package the project depends on under
+// the crate name. When Cargo.lock holds several versions, the project's own
+// entry tells which one, as "name version".
+func (p *Project) lockedPackage(crate string) *LockedPackage {
+	var candidates []*LockedPackage
+	for i, pkg := range p.locked {
+		if pkg.Name == crate {
+			candidates = append(candidates, &p.locked[i])
+		}
+	}
+	if len(candidates) <= 1 {
+		if len(candidates) == 1 {
+			return candidates[0]
+		}
+		return nil
+	}
+	for _, pkg := range p.locked {
+		if pkg.Name != p.Name || pkg.Source != "" {
+			continue
+		}
+		for _, dep := range pkg.Dependencies {
+			fields := strings.Fields(dep)
+			if len(fields) < 2 || fields[0] != crate {
+				continue
+			}
+			for _, candidate := range candidates {
+				if candidate.Version == fields[1] {
+					return candidate
+				}
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return compareVersions(candidates[i].Version, candidates[j].Version) > 0
+	})
+	return candidates[0]
+}
+
+// locateSource returns the directory cargo unpacked a crate into:
+// registry/src/<index>/<name>-<version> for registry crates and
+// git/checkouts/<repository>-<hash>/<short commit> for git ones.
+func (p *Project) locateSource(name, version, source string) string {
+	switch {
+	case strings.HasPrefix(source, "registry+"), strings.HasPrefix(source, "sparse+"):
+		matches, _ := filepath.Glob(filepath.Join(p.CargoHome, "registry", "src", "*", name+"-"+version))
+		for _, match := range matches {
+			if fsutil.IsDir(match) {
+				return match
+			}
+		}
+	case strings.HasPrefix(source, "git+"):
+		return gitCheckout(p.CargoHome, name, source)
+	}
+	return ""
+}
+
+func gitCheckout(home, name, source string) string {
+	url, commit, ok := strings.Cut(strings.TrimPrefix(source, "git+"), "#")
+	if !ok || len(commit) < 7 {
+		return ""
+	}
+	url, _, _ = strings.Cut(url, "?")
+	repo := strings.TrimSuffix(filepath.Base(strings.TrimSuffix(url, "/")), ".git")
+	checkouts, _ := filepath.Glob(filepath.Join(home, "git", "checkouts", repo+"-*", commit[:7]+"*"))
+	for _, checkout := range checkouts {
+		if dir := findCrateDir(checkout, name, 3); dir != "" {
+			return dir
+		}
+	}
+	return ""
+}
+
+// findCrateDir looks for the directory below root, at most depth levels
+// deep, whose Cargo.toml declares the named package.
+func findCrateDir(root, name string, depth int) string {
+	if manifest, err := readManifest(root); err == nil && tomlString(tomlTable(manifest, "package"), "name") == name {
+		return root
+	}
+	if depth == 0 {
+		return ""
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || entry.Name() == "target" {
+			continue
+		}
+		if dir := findCrateDir(filepath.Join(root, entry.Name()), name, depth-1); dir != "" {
+			return dir
+		}
+	}
+	return ""
+}
+
+// cargoHome returns CARGO_HOME, defaulting to ~/.cargo.
+func cargoHome() string {
+	if home := os.Getenv("CARGO_HOME"); home != "" {
+		return home
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".cargo")
+}
+
+// compareVersions compares two crate versions, which follow semver without
+// the v prefix.
+func compareVersions(a, b string) int {
+	return semver.Compare("v"+a, "v"+b)
+}
@@ -0,0 +1,142 @@
+package cargo_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/cargo"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func TestLoadProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	cargoHome := filepath.Join(tmpDir, "cargo")
+	t.Setenv("CARGO_HOME", cargoHome)
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"app/Cargo.toml": `[workspace]
+members = ["server"]
+
+[workspace.dependencies]
+serde = { version = "1.0", features = ["derive"] }
+`,
+		"app/server/Cargo.toml": `[package]
+name = "server"
+version = "0.1.0"
+description = """
+A server.
+"""
+
+[dependencies]
+serde.workspace = true
+tokio = { version = "1.38", features = [
+    "rt-multi-thread", # the scheduler
+    "macros",
+] }
+rand_core = { package = "rand_core", version = "0.6" }
+axum = { git = "https://github.com/tokio-rs/axum", branch = "main" }
+
+[dependencies.log]
+version = "0.4"
+optional = true
+
+[dev-dependencies]
+tempfile = "3"
+
+[target.'cfg(unix)'.dependencies]
+libc = "0.2"
+`,
+		"app/Cargo.lock": `# This file is automatically @generated by Cargo.
+version = 3
+
+[[package]]
+name = "axum"
+version = "0.8.0"
+source = "git+https://github.com/tokio-rs/axum?branch=main#0123456789abcdef0123456789abcdef01234567"
+
+[[package]]
+name = "libc"
+version = "0.2.155"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "log"
+version = "0.4.22"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "rand_core"
+version = "0.5.1"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "rand_core"
+version = "0.6.4"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "serde"
+version = "1.0.203"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "7253ab4de971e72fb7be983802300c30b5a7f0c2e56fab8abfc6a214307c0094"
+
+[[package]]
+name = "server"
+version = "0.1.0"
+dependencies = [
+ "axum",
+ "libc",
+ "log",
+ "rand_core 0.6.4",
+ "serde",
+ "tokio",
+]
+
+[[package]]
+name = "tokio"
+version = "1.38.0"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`,
+		"cargo/registry/src/index.crates.io-6f17d22bba15001f/serde-1.0.203/Cargo.toml": "[package]\nname = \"serde\"\n",
+		"cargo/git/checkouts/axum-1a2b3c4d5e6f7a8b/0123456/axum/Cargo.toml":            "[package]\nname = \"axum\"\n",
+	})
+
+	project, err := cargo.LoadProject(filepath.Join(tmpDir, "app", "server"))
+	require.NoError(t, err)
+	assert.Equal(t, "server", project.Name)
+	assert.Equal(t, filepath.Join(tmpDir, "app", "Cargo.lock"), project.Lockfile, "Members share the workspace lockfile")
+	assert.Empty(t, project.Warnings())
+
+	deps := project.Dependencies()
+	byName := make(map[string]cargo.Dependency)
+	for _, dep := range deps {
+		byName[dep.Name] = dep
+	}
+	require.Len(t, deps, 7)
+
+	assert.Equal(t, "1.0", byName["serde"].Requirement, "Workspace dependencies are inherited")
+	assert.Equal(t, "1.0.203", byName["serde"].Locked)
+	assert.Equal(t, filepath.Join(cargoHome, "registry", "src", "index.crates.io-6f17d22bba15001f", "serde-1.0.203"), byName["serde"].Dir)
+
+	assert.Equal(t, "1.38", byName["tokio"].Requirement)
+	assert.Equal(t, "1.38.0", byName["tokio"].Locked)
+	assert.Empty(t, byName["tokio"].Dir, "tokio is not in the registry cache")
+
+	assert.Equal(t, "0.6.4", byName["rand_core"].Locked, "The project's lock entry picks among several versions")
+	assert.Equal(t, filepath.Join(cargoHome, "git", "checkouts", "axum-1a2b3c4d5e6f7a8b", "0123456", "axum"), byName["axum"].Dir)
+	assert.True(t, byName["log"].Optional)
+	assert.Equal(t, "0.4.22", byName["log"].Locked)
+	assert.Equal(t, cargo.KindDev, byName["tempfile"].Kind)
+	assert.Equal(t, "cfg(unix)", byName["libc"].Target)
+
+	dep, err := project.Dependency("rand-core")
+	require.NoError(t, err)
+	assert.Equal(t, "rand_core", dep.Name)
+
+	_, err = project.Dependency("hyper")
+	assert.ErrorContains(t, err, "not a dependency")
+
+	_, err = cargo.LoadProject(t.TempDir())
+	assert.ErrorIs(t, err, cargo.ErrNoManifest)
+}
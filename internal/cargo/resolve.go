@@ -0,0 +1,219 @@
+package cargo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxReexportHops bounds the pub use chains FindItem follows.
+const maxReexportHops = 8
+
+// ItemSource is the source of an item, found by FindItem.
+type ItemSource struct {
+	// Path is the path the item was looked up with, and Module and Item
+	// where it is declared. Item is nil for a module.
+	Path   string  `json:"path"`
+	Module *Module `json:"module"`
+	Item   *Item   `json:"item,omitempty"`
+	// Via lists the pub use re-exports followed to reach the declaration.
+	Via []string `json:"via,omitempty"`
+	// File is the file declaring the item, and StartLine and EndLine the
+	// lines of the item with its doc comments and attributes. EndLine is 0
+	// when the item spans the rest of the file, as for a module file.
+	File      string `json:"file"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line,omitempty"`
+}
+
+// FindItem finds the declaration of the item at path, such as sync::Mutex,
+// sync::Mutex::lock or tokio::sync::Mutex, following pub use re-exports
+// within the crate.
+func (c *Crate) FindItem(path string) (*ItemSource, error) {
+	segments := strings.Split(strings.TrimSpace(path), "::")
+	if segments[0] == "crate" || segments[0] == strings.ReplaceAll(c.Name, "-", "_") {
+		segments = segments[1:]
+	}
+	for _, segment := range segments {
+		if segment == "" {
+			return nil, fmt.Errorf("invalid item path %q", path)
+		}
+	}
+
+	source := &ItemSource{Path: path}
+	if err := c.resolve(source, segments, 0); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+func (c *Crate) resolve(source *ItemSource, segments []string, hops int) error {
+	// The longest prefix naming a module is the module declaring the item.
+	mod, rest := c.Module(""), segments
+	for i := len(segments); i >= 0; i-- {
+		found := c.Module(strings.Join(segments[:i], "::"))
+		if found == nil {
+			continue
+		}
+		mod, rest = found, segments[i:]
+		// A private module is usually named after the item its parent
+		// re-exports, as in mod spawn; pub use spawn::spawn.
+		if i > 0 && i == len(segments) && !found.Public {
+			if parent := c.Module(strings.Join(segments[:i-1], "::")); parent != nil && parent.declares(segments[i-1]) {
+				mod, rest = parent, segments[i-1:]
+			}
+		}
+		break
+	}
+	switch len(rest) {
+	case 0:
+		source.Module = mod
+		source.File = mod.File
+		source.StartLine = 1
+		if mod.Line > 0 {
+			source.StartLine, source.EndLine = c.itemLines(mod.File, mod.Line, "mod")
+		}
+		return nil
+	case 1, 2:
+		if item := mod.item(rest); item != nil {
+			source.Module = mod
+			source.Item = item
+			source.File = mod.File
+			source.StartLine, source.EndLine = c.itemLines(mod.File, item.Line, item.Kind)
+			return nil
+		}
+	}
+
+	if hops == maxReexportHops {
+		return fmt.Errorf("too many re-exports following %s", source.Path)
+	}
+	for _, reexport := range mod.Reexports {
+		if reexport.Name != rest[0] && reexport.Name != "*" {
+			continue
+		}
+		target, ok := c.reexportTarget(mod, reexport.Path)
+		if !ok {
+			if reexport.Name != "*" {
+				return fmt.Errorf("%s is re-exported by %s from %s, outside crate %s", rest[0], displayPath(mod.Path), reexport.Path, c.Name)
+			}
+			continue
+		}
+		next := target
+		if reexport.Name == "*" {
+			next = append(next, rest...)
+		} else {
+			next = append(next, rest[1:]...)
+		}
+		attempt := *source
+		attempt.Via = append(append([]string(nil), source.Via...), fmt.Sprintf("%s: pub use %s", displayPath(mod.Path), reexport.Path))
+		if err := c.resolve(&attempt, next, hops+1); err != nil {
+			if reexport.Name == "*" {
+				continue
+			}
+			return err
+		}
+		*source = attempt
+		return nil
+	}
+	// Exported macros are at the crate root, whatever module defines them.
+	if mod.Path == "" && len(rest) == 1 {
+		for _, candidate := range c.Modules {
+			if item := candidate.item(rest); item != nil && item.Kind == "macro" && item.Public {
+				source.Module = candidate
+				source.Item = item
+				source.File = candidate.File
+				source.StartLine, source.EndLine = c.itemLines(candidate.File, item.Line, item.Kind)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("no item %s in module %s of crate %s; list the crate's modules to find it", strings.Join(rest, "::"), displayPath(mod.Path), c.Name)
+}
+
+// item returns the item named by rest, a name or a type and method name.
+func (m *Module) item(rest []string) *Item {
+	for i := range m.Items {
+		item := &m.Items[i]
+		switch {
+		case len(rest) == 1 && item.Parent == "" && item.Name == rest[0]:
+			return item
+		case len(rest) == 2 && item.Kind == "method" && item.Parent == rest[0] && item.Name == rest[1]:
+			return item
+		}
+	}
+	return nil
+}
+
+// declares reports whether the module declares or re-exports name.
+func (m *Module) declares(name string) bool {
+	if m.item([]string{name}) != nil {
+		return true
+	}
+	for _, reexport := range m.Reexports {
+		if reexport.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// reexportTarget returns the segments below the crate root a use path
+// names, relative to the module declaring it. ok is false for paths into
+// other crates.
+func (c *Crate) reexportTarget(mod *Module, path string) ([]string, bool) {
+	segments := strings.Split(strings.TrimSuffix(path, "::*"), "::")
+	var base []string
+	if mod.Path != "" {
+		base = strings.Split(mod.Path, "::")
+	}
+	switch segments[0] {
+	case "crate":
+		return segments[1:], true
+	case "self":
+		return append(base, segments[1:]...), true
+	case "super":
+		for len(segments) > 0 && segments[0] == "super" {
+			if len(base) > 0 {
+				base = base[:len(base)-1]
+			}
+			segments = segments[1:]
+		}
+		return append(base, segments...), true
+	}
+	// A relative path starts at a child module, or in the 2015 edition at
+	// the crate root.
+	if c.Module(joinPath(mod.Path, segments[0])) != nil {
+		return append(base, segments...), true
+	}
+	if c.Module(segments[0]) != nil {
+		return segments, true
+	}
+	if len(segments) == 1 && mod.item(segments) != nil {
+		return append(base, segments...), true
+	}
+	return nil, false
+}
+
+// itemLines returns the lines of the item declared at line, including the
+// doc comments and attributes above it.
+func (c *Crate) itemLines(file string, line int, kind string) (int, int) {
+	original := c.lines[file]
+	lines := strings.Split(blankCode(strings.Join(original, "\n")), "\n")
+	end := itemEnd(lines, line-1, kind == "const" || kind == "static" || kind == "type") + 1
+
+	start := line
+	for start > 1 {
+		trimmed := strings.TrimSpace(original[start-2])
+		if !strings.HasPrefix(trimmed, "///") && !strings.HasPrefix(trimmed, "#[") {
+			break
+		}
+		start--
+	}
+	return start, end
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "crate root"
+	}
+	return path
+}
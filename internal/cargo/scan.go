@@ -0,0 +1,158 @@
+package cargo
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// blankCode returns src with the contents of comments, strings and
+// character literals replaced by spaces, keeping newlines and the length of
+// every line, so that braces and keywords can be matched line by line.
+func blankCode(src string) string {
+	out := []byte(src)
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(out); i++ {
+			if out[i] != '\n' {
+				out[i] = ' '
+			}
+		}
+	}
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			blank(i, i+end)
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			// Block comments nest in Rust.
+			depth, j := 0, i
+			for j < len(src) {
+				if strings.HasPrefix(src[j:], "/*") {
+					depth++
+					j += 2
+				} else if strings.HasPrefix(src[j:], "*/") {
+					depth--
+					j += 2
+					if depth == 0 {
+						break
+					}
+				} else {
+					j++
+				}
+			}
+			blank(i, j)
+			i = j
+		case c == 'r' && (i == 0 || !isIdentByte(src[i-1]) || (src[i-1] == 'b' && (i == 1 || !isIdentByte(src[i-2])))) && rawStringStart(src[i+1:]) >= 0:
+			hashes := rawStringStart(src[i+1:])
+			start := i + 2 + hashes
+			closing := "\"" + strings.Repeat("#", hashes)
+			end := strings.Index(src[start:], closing)
+			if end < 0 {
+				end = len(src) - start
+			}
+			blank(start, start+end)
+			i = start + end + len(closing)
+		case c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			blank(i+1, j)
+			i = j + 1
+		case c == '\'':
+			if end := charLiteralEnd(src[i+1:]); end > 0 {
+				blank(i+1, i+1+end)
+				i += end + 2
+				continue
+			}
+			// A lifetime or label.
+			i++
+		default:
+			i++
+		}
+	}
+	return string(out)
+}
+
+// rawStringStart returns the number of # of a raw string opening at the
+// start of s (after the r), or -1.
+func rawStringStart(s string) int {
+	hashes := 0
+	for hashes < len(s) && s[hashes] == '#' {
+		hashes++
+	}
+	if hashes < len(s) && s[hashes] == '"' {
+		return hashes
+	}
+	return -1
+}
+
+// charLiteralEnd returns the length of the character literal content at the
+// start of s (after the opening quote), or 0 when the quote starts a
+// lifetime.
+func charLiteralEnd(s string) int {
+	if strings.HasPrefix(s, `\`) {
+		if end := strings.IndexByte(s[1:], '\''); end >= 0 && end < 10 {
+			return end + 1
+		}
+		return 0
+	}
+	_, size := utf8.DecodeRuneInString(s)
+	if size > 0 && size < len(s) && s[size] == '\'' {
+		return size
+	}
+	return 0
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// itemEnd returns the index of the last line of the item starting at line
+// start of the blanked lines: the line closing its body, or the line of
+// the terminating semicolon. With semicolon set, as for const, static and
+// type items, only the semicolon ends the item.
+func itemEnd(lines []string, start int, semicolon bool) int {
+	braces, others, opened := 0, 0, false
+	for i := start; i < len(lines); i++ {
+		for _, c := range []byte(lines[i]) {
+			switch c {
+			case '(', '[':
+				others++
+			case ')', ']':
+				others--
+			case '{':
+				braces++
+				opened = true
+			case '}':
+				braces--
+				if opened && braces == 0 && others == 0 && !semicolon {
+					return i
+				}
+			case ';':
+				if braces == 0 && others == 0 && (semicolon || !opened) {
+					return i
+				}
+			}
+		}
+	}
+	return len(lines) - 1
+}
+
+// braceDepths returns the brace depth at the start of every line.
+func braceDepths(lines []string) []int {
+	depths := make([]int, len(lines))
+	depth := 0
+	for i, line := range lines {
+		depths[i] = depth
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+	}
+	return depths
+}
@@ -0,0 +1,433 @@
+package cargo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML parses the subset of TOML found in Cargo.toml and Cargo.lock
+// files: tables, arrays of tables, dotted and quoted keys, strings, arrays
+// and inline tables. Booleans are decoded as bool; numbers and dates are
+// kept as their text.
+func parseTOML(data string) (map[string]any, error) {
+	p := &tomlParser{s: data, line: 1}
+	root := make(map[string]any)
+	current := root
+	for {
+		p.skipSpace(true)
+		if p.eof() {
+			return root, nil
+		}
+		var err error
+		switch {
+		case strings.HasPrefix(p.s[p.pos:], "[["):
+			p.pos += 2
+			current, err = p.arrayTableHeader(root)
+		case p.peek() == '[':
+			p.pos++
+			current, err = p.tableHeader(root)
+		default:
+			err = p.keyValue(current)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := p.endOfLine(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+type tomlParser struct {
+	s    string
+	pos  int
+	line int
+}
+
+func (p *tomlParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips blanks and comments, and newlines too when newlines is
+// set.
+func (p *tomlParser) skipSpace(newlines bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '\n' && newlines:
+			p.pos++
+			p.line++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) endOfLine() error {
+	p.skipSpace(false)
+	if p.eof() || p.peek() == '\n' {
+		return nil
+	}
+	return p.errorf("unexpected %q after value", p.peek())
+}
+
+func (p *tomlParser) tableHeader(root map[string]any) (map[string]any, error) {
+	keys, err := p.keyPath()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != ']' {
+		return nil, p.errorf("expected ] after table name")
+	}
+	p.pos++
+	return p.table(root, keys)
+}
+
+func (p *tomlParser) arrayTableHeader(root map[string]any) (map[string]any, error) {
+	keys, err := p.keyPath()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(p.s[p.pos:], "]]") {
+		return nil, p.errorf("expected ]] after array table name")
+	}
+	p.pos += 2
+	parent, err := p.table(root, keys[:len(keys)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := keys[len(keys)-1]
+	array, _ := parent[last].([]any)
+	if parent[last] != nil && array == nil {
+		return nil, p.errorf("%s is not an array of tables", strings.Join(keys, "."))
+	}
+	table := make(map[string]any)
+	parent[last] = append(array, table)
+	return table, nil
+}
+
+// table returns the table at the key path, creating missing tables. A path
+// through an array of tables continues in its last element.
+func (p *tomlParser) table(root map[string]any, keys []string) (map[string]any, error) {
+	current := root
+	for _, key := range keys {
+		switch value := current[key].(type) {
+		case nil:
+			table := make(map[string]any)
+			current[key] = table
+			current = table
+		case map[string]any:
+			current = value
+		case []any:
+			if len(value) == 0 {
+				return nil, p.errorf("%s is an empty array", key)
+			}
+			table, ok := value[len(value)-1].(map[string]any)
+			if !ok {
+				return nil, p.errorf("%s is not a table", key)
+			}
+			current = table
+		default:
+			return nil, p.errorf("%s is not a table", key)
+		}
+	}
+	return current, nil
+}
+
+func (p *tomlParser) keyValue(table map[string]any) error {
+	keys, err := p.keyPath()
+	if err != nil {
+		return err
+	}
+	if p.peek() != '=' {
+		return p.errorf("expected = after key %s", strings.Join(keys, "."))
+	}
+	p.pos++
+	p.skipSpace(false)
+	value, err := p.value()
+	if err != nil {
+		return err
+	}
+	parent, err := p.table(table, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	parent[keys[len(keys)-1]] = value
+	return nil
+}
+
+// keyPath parses a dotted key such as target.'cfg(unix)'.dependencies.
+func (p *tomlParser) keyPath() ([]string, error) {
+	var keys []string
+	for {
+		p.skipSpace(false)
+		var key string
+		switch c := p.peek(); {
+		case c == '"':
+			p.pos++
+			value, err := p.basicString()
+			if err != nil {
+				return nil, err
+			}
+			key = value
+		case c == '\'':
+			p.pos++
+			end := strings.IndexAny(p.s[p.pos:], "'\n")
+			if end < 0 || p.s[p.pos+end] != '\'' {
+				return nil, p.errorf("unterminated quoted key")
+			}
+			key = p.s[p.pos : p.pos+end]
+			p.pos += end + 1
+		default:
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("expected a key, found %q", c)
+			}
+			key = p.s[start:p.pos]
+		}
+		keys = append(keys, key)
+		p.skipSpace(false)
+		if p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c == '_' || c == '-' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func (p *tomlParser) value() (any, error) {
+	rest := p.s[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, `"""`):
+		p.pos += 3
+		return p.multilineString(`"""`, true)
+	case strings.HasPrefix(rest, "'''"):
+		p.pos += 3
+		return p.multilineString("'''", false)
+	case strings.HasPrefix(rest, `"`):
+		p.pos++
+		return p.basicString()
+	case strings.HasPrefix(rest, "'"):
+		p.pos++
+		end := strings.IndexAny(rest[1:], "'\n")
+		if end < 0 || rest[1+end] != '\'' {
+			return nil, p.errorf("unterminated literal string")
+		}
+		p.pos += end + 1
+		return rest[1 : 1+end], nil
+	case strings.HasPrefix(rest, "["):
+		p.pos++
+		return p.array()
+	case strings.HasPrefix(rest, "{"):
+		p.pos++
+		return p.inlineTable()
+	}
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(",]}# \t\r\n", rune(p.peek())) {
+		p.pos++
+	}
+	switch text := p.s[start:p.pos]; text {
+	case "":
+		return nil, p.errorf("expected a value")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return text, nil
+	}
+}
+
+// basicString parses a double-quoted string after its opening quote.
+func (p *tomlParser) basicString() (string, error) {
+	var b strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.peek()
+		p.pos++
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+func (p *tomlParser) multilineString(delimiter string, escapes bool) (string, error) {
+	// A newline right after the opening delimiter is trimmed.
+	if strings.HasPrefix(p.s[p.pos:], "\r\n") {
+		p.pos += 2
+		p.line++
+	} else if p.peek() == '\n' {
+		p.pos++
+		p.line++
+	}
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated multi-line string")
+		}
+		if strings.HasPrefix(p.s[p.pos:], delimiter) {
+			// Up to two quotes right before the closing delimiter
+			// belong to the string, as in """a "quoted" word"""".
+			quotes := len(delimiter)
+			for quotes < len(delimiter)+2 && p.pos+quotes < len(p.s) && p.s[p.pos+quotes] == delimiter[0] {
+				quotes++
+			}
+			b.WriteString(p.s[p.pos : p.pos+quotes-len(delimiter)])
+			p.pos += quotes
+			return b.String(), nil
+		}
+		c := p.peek()
+		p.pos++
+		switch {
+		case c == '\n':
+			p.line++
+			b.WriteByte(c)
+		case c == '\\' && escapes:
+			// A backslash at the end of a line trims the following
+			// whitespace.
+			if rest := strings.TrimLeft(p.s[p.pos:], " \t\r"); strings.HasPrefix(rest, "\n") {
+				for !p.eof() && strings.ContainsRune(" \t\r\n", rune(p.peek())) {
+					if p.peek() == '\n' {
+						p.line++
+					}
+					p.pos++
+				}
+				continue
+			}
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+func (p *tomlParser) escape(b *strings.Builder) error {
+	if p.eof() {
+		return p.errorf("unterminated escape")
+	}
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case '"', '\\':
+		b.WriteByte(c)
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.s) {
+			return p.errorf("short unicode escape")
+		}
+		code, err := strconv.ParseUint(p.s[p.pos:p.pos+size], 16, 32)
+		if err != nil {
+			return p.errorf("invalid unicode escape")
+		}
+		p.pos += size
+		b.WriteRune(rune(code))
+	default:
+		return p.errorf("invalid escape \\%c", c)
+	}
+	return nil
+}
+
+func (p *tomlParser) array() ([]any, error) {
+	values := make([]any, 0)
+	for {
+		p.skipSpace(true)
+		if p.peek() == ']' {
+			p.pos++
+			return values, nil
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		p.skipSpace(true)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, p.errorf("expected , or ] in array")
+		}
+	}
+}
+
+func (p *tomlParser) inlineTable() (map[string]any, error) {
+	table := make(map[string]any)
+	for {
+		p.skipSpace(true)
+		if p.peek() == '}' {
+			p.pos++
+			return table, nil
+		}
+		if err := p.keyValue(table); err != nil {
+			return nil, err
+		}
+		p.skipSpace(true)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			return nil, p.errorf("expected , or } in inline table")
+		}
+	}
+}
+
+// tomlTable returns the table under key, or nil.
+func tomlTable(table map[string]any, key string) map[string]any {
+	value, _ := table[key].(map[string]any)
+	return value
+}
+
+// tomlString returns the string under key, or "".
+func tomlString(table map[string]any, key string) string {
+	value, _ := table[key].(string)
+	return value
+}
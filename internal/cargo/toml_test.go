@@ -0,0 +1,105 @@
+package cargo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]any
+	}{
+		{
+			name:  "basic values",
+			input: "name = \"serde\" # comment\nversion = '1.0'\npublish = false\nedition = 2021\n",
+			want:  map[string]any{"name": "serde", "version": "1.0", "publish": false, "edition": "2021"},
+		},
+		{
+			name:  "bare values starting with true or false",
+			input: "a = trueish\nb = false_\nc = true\n",
+			want:  map[string]any{"a": "trueish", "b": "false_", "c": true},
+		},
+		{
+			name:  "escapes",
+			input: `s = "tab\tquote\" backslash\\ \u00e9 \U0001F600"` + "\n",
+			want:  map[string]any{"s": "tab\tquote\" backslash\\ \u00e9 \U0001F600"},
+		},
+		{
+			name:  "multi-line basic string",
+			input: "s = \"\"\"\nfirst\nsecond \\\n    joined\"\"\"\n",
+			want:  map[string]any{"s": "first\nsecond joined"},
+		},
+		{
+			name:  "multi-line string ending in quotes",
+			input: "a = \"\"\"hi\"\"\"\"\nb = \"\"\"hi\"\"\"\"\"\nc = '''it''''\n",
+			want:  map[string]any{"a": `hi"`, "b": `hi""`, "c": "it'"},
+		},
+		{
+			name:  "multi-line literal string keeps backslashes",
+			input: "s = '''\nC:\\path\\n'''\n",
+			want:  map[string]any{"s": "C:\\path\\n"},
+		},
+		{
+			name:  "dotted and quoted keys",
+			input: "[target.'cfg(unix)'.dependencies]\nlibc.version = \"0.2\"\n\"quoted key\" = 1\n",
+			want: map[string]any{"target": map[string]any{
+				"cfg(unix)": map[string]any{"dependencies": map[string]any{
+					"libc":       map[string]any{"version": "0.2"},
+					"quoted key": "1",
+				}},
+			}},
+		},
+		{
+			name:  "array tables",
+			input: "[[package]]\nname = \"a\"\n[package.source]\nkind = \"git\"\n\n[[package]]\nname = \"b\"\n",
+			want: map[string]any{"package": []any{
+				map[string]any{"name": "a", "source": map[string]any{"kind": "git"}},
+				map[string]any{"name": "b"},
+			}},
+		},
+		{
+			name:  "inline tables and arrays",
+			input: "serde = { version = \"1.0\", features = [\"derive\",\n  \"rc\", # trailing comma\n], opt.x = true }\nempty = []\n",
+			want: map[string]any{
+				"serde": map[string]any{
+					"version":  "1.0",
+					"features": []any{"derive", "rc"},
+					"opt":      map[string]any{"x": true},
+				},
+				"empty": []any{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTOML(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{name: "unterminated string", input: "a = \"abc\n", err: "line 1: unterminated string"},
+		{name: "unterminated multi-line string", input: "a = \"\"\"abc\n\n", err: "line 3: unterminated multi-line string"},
+		{name: "trailing garbage", input: "a = \"x\" y\n", err: "line 1: unexpected 'y' after value"},
+		{name: "invalid escape", input: `a = "\q"`, err: `line 1: invalid escape \q`},
+		{name: "missing equals", input: "\n\na b\n", err: "line 3: expected = after key a"},
+		{name: "table over value", input: "a = 1\n[a.b]\n", err: "line 2: a is not a table"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTOML(tt.input)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
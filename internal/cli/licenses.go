@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/svetlyi/mcp-local-context/internal/config"
+	"github.com/svetlyi/mcp-local-context/internal/goenv"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+	"github.com/svetlyi/mcp-local-context/internal/licenses"
+)
+
+// Licenses prints the license inventory of a Go project's dependencies.
+//
+//	mcp-local-context licenses [-json] [project_dir]
+func Licenses(cfg *config.Config, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("licenses", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the inventory as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: mcp-local-context licenses [-json] [project_dir]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	projectDir := "."
+	if flags.NArg() > 0 {
+		projectDir = flags.Arg(0)
+	}
+
+	env, err := goenv.Detect(goenv.WithOverrides(cfg.GoEnv))
+	if err != nil {
+		return fmt.Errorf("failed to detect Go environment: %w", err)
+	}
+	project, err := gomod.LoadProject(projectDir, env.GOMODCACHE, gomod.WithModFlag(env.ModFlag()))
+	if err != nil {
+		return err
+	}
+
+	entries, warnings, err := licenses.Inventory(project)
+	if err != nil {
+		return err
+	}
+	warnings = append(project.Warnings(), warnings...)
+	for _, warning := range warnings {
+		fmt.Fprintf(stderr, "warning: %s\n", warning)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+	return licenses.WriteTable(stdout, entries)
+}
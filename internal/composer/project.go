@@ -0,0 +1,271 @@
+// Package composer resolves the dependencies of PHP projects through
+// composer.json, composer.lock and the packages installed in the vendor
+// directory.
+package composer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+)
+
+// ErrNoComposerJSON is returned when no composer.json can be found for a
+// directory.
+var ErrNoComposerJSON = errors.New("composer.json not found")
+
+// Dependency kinds, named after their composer.json fields. KindTransitive
+// marks a package only found in composer.lock.
+const (
+	KindRequire    = "require"
+	KindDev        = "require-dev"
+	KindTransitive = "transitive"
+)
+
+// ComposerJSON holds the fields of a composer.json file used to resolve
+// dependencies.
+type ComposerJSON struct {
+	Name       string            `json:"name"`
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+	Config     struct {
+		VendorDir string `json:"vendor-dir"`
+	} `json:"config"`
+}
+
+// Dependency is a package the project requires, directly or through other
+// packages.
+type Dependency struct {
+	Name string `json:"name"`
+	// Constraint is the version constraint composer.json declares.
+	Constraint string `json:"constraint,omitempty"`
+	Kind       string `json:"kind"`
+	// Locked is the version composer.lock resolves the package to, and
+	// Reference the commit or tag of its source.
+	Locked    string `json:"locked,omitempty"`
+	Reference string `json:"reference,omitempty"`
+	// Installed is the version recorded in vendor/composer/installed.json,
+	// and Dir the directory of the installed package.
+	Installed string `json:"installed,omitempty"`
+	Dir       string `json:"dir,omitempty"`
+}
+
+type lockedPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Source  struct {
+		Reference string `json:"reference"`
+	} `json:"source"`
+	// InstallPath is only set in installed.json, relative to
+	// vendor/composer.
+	InstallPath string `json:"install-path"`
+}
+
+// Project is a PHP package on disk.
+type Project struct {
+	Dir      string
+	Manifest *ComposerJSON
+	// Lockfile is the path of composer.lock, empty when there is none.
+	Lockfile  string
+	VendorDir string
+
+	locked    []lockedPackage
+	lockedDev []lockedPackage
+	installed map[string]lockedPackage
+	warnings  []string
+}
+
+// LoadProject finds the composer.json governing dir, walking up the
+// directory tree, the composer.lock next to it and the installed packages
+// of its vendor directory.
+func LoadProject(dir string) (*Project, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	rootDir, ok := fsutil.FindUp(absDir, "composer.json")
+	if !ok {
+		return nil, fmt.Errorf("%w in %s or any parent directory", ErrNoComposerJSON, absDir)
+	}
+	manifestPath := filepath.Join(rootDir, "composer.json")
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	var manifest ComposerJSON
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", manifestPath, err)
+	}
+
+	p := &Project{Dir: rootDir, Manifest: &manifest, VendorDir: filepath.Join(rootDir, "vendor"), installed: make(map[string]lockedPackage)}
+	if vendorDir := manifest.Config.VendorDir; vendorDir != "" {
+		p.VendorDir = vendorDir
+		if !filepath.IsAbs(vendorDir) {
+			p.VendorDir = filepath.Join(rootDir, filepath.FromSlash(vendorDir))
+		}
+	}
+	if err := p.loadLockfile(); err != nil {
+		return nil, err
+	}
+	if err := p.loadInstalled(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Project) loadLockfile() error {
+	path := filepath.Join(p.Dir, "composer.lock")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		p.warnings = append(p.warnings, "no composer.lock found; versions are taken from the vendor directory")
+		return nil
+	}
+	var lock struct {
+		Packages    []lockedPackage `json:"packages"`
+		PackagesDev []lockedPackage `json:"packages-dev"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	p.Lockfile = path
+	p.locked = lock.Packages
+	p.lockedDev = lock.PackagesDev
+	return nil
+}
+
+// loadInstalled reads vendor/composer/installed.json, a list of packages
+// before Composer 2 and an object holding the list since.
+func (p *Project) loadInstalled() error {
+	path := filepath.Join(p.VendorDir, "composer", "installed.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		p.warnings = append(p.warnings, "no installed packages found; run composer install")
+		return nil
+	}
+	var packages []lockedPackage
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(data, &packages)
+	} else {
+		var installed struct {
+			Packages []lockedPackage `json:"packages"`
+		}
+		err = json.Unmarshal(data, &installed)
+		packages = installed.Packages
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, pkg := range packages {
+		p.installed[strings.ToLower(pkg.Name)] = pkg
+	}
+	return nil
+}
+
+// Warnings describes problems that may make the reported versions differ
+// from what composer installs.
+func (p *Project) Warnings() []string {
+	return p.warnings
+}
+
+// Dependencies lists the packages of composer.lock and those composer.json
+// requires, sorted by kind and name. Platform requirements such as php and
+// ext-json are left out.
+func (p *Project) Dependencies() []Dependency {
+	deps := make([]Dependency, 0)
+	seen := make(map[string]bool)
+	for _, pkg := range append(append([]lockedPackage{}, p.locked...), p.lockedDev...) {
+		key := strings.ToLower(pkg.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		dep := p.resolve(pkg.Name)
+		dep.Locked = pkg.Version
+		dep.Reference = pkg.Source.Reference
+		deps = append(deps, dep)
+	}
+	for _, requires := range []map[string]string{p.Manifest.Require, p.Manifest.RequireDev} {
+		for name := range requires {
+			key := strings.ToLower(name)
+			if seen[key] || isPlatform(name) {
+				continue
+			}
+			seen[key] = true
+			deps = append(deps, p.resolve(name))
+		}
+	}
+
+	kindOrder := map[string]int{KindRequire: 0, KindDev: 1, KindTransitive: 2}
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Kind != deps[j].Kind {
+			return kindOrder[deps[i].Kind] < kindOrder[deps[j].Kind]
+		}
+		return deps[i].Name < deps[j].Name
+	})
+	return deps
+}
+
+// Dependency returns the package with the given name, compared without
+// case as composer does.
+func (p *Project) Dependency(name string) (*Dependency, error) {
+	for _, dep := range p.Dependencies() {
+		if strings.EqualFold(dep.Name, name) {
+			return &dep, nil
+		}
+	}
+	return nil, fmt.Errorf("package %s is not a dependency of %s", name, p.displayName())
+}
+
+// resolve fills in what composer.json declares about a package and where
+// it is installed.
+func (p *Project) resolve(name string) Dependency {
+	dep := Dependency{Name: name, Kind: KindTransitive}
+	for _, k := range []struct {
+		kind     string
+		requires map[string]string
+	}{
+		{KindRequire, p.Manifest.Require},
+		{KindDev, p.Manifest.RequireDev},
+	} {
+		for required, constraint := range k.requires {
+			if strings.EqualFold(required, name) {
+				dep.Kind, dep.Constraint = k.kind, constraint
+				break
+			}
+		}
+		if dep.Kind != KindTransitive {
+			break
+		}
+	}
+
+	dir := filepath.Join(p.VendorDir, filepath.FromSlash(strings.ToLower(name)))
+	if installed, ok := p.installed[strings.ToLower(name)]; ok {
+		dep.Installed = installed.Version
+		// Path repositories are symlinked or copied elsewhere.
+		if installed.InstallPath != "" {
+			dir = filepath.Join(p.VendorDir, "composer", filepath.FromSlash(installed.InstallPath))
+		}
+	}
+	if fsutil.IsDir(dir) {
+		dep.Dir = dir
+	}
+	return dep
+}
+
+func (p *Project) displayName() string {
+	if p.Manifest.Name != "" {
+		return p.Manifest.Name
+	}
+	return p.Dir
+}
+
+// isPlatform reports whether a requirement is a platform package such as
+// php, ext-mbstring or composer-plugin-api rather than a vendor/package.
+func isPlatform(name string) bool {
+	return !strings.Contains(name, "/")
+}
@@ -0,0 +1,89 @@
+package composer_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/composer"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func TestLoadProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"composer.json": `{
+  "name": "acme/shop",
+  "require": {
+    "php": "^8.2",
+    "ext-mbstring": "*",
+    "laravel/framework": "^11.0",
+    "Monolog/Monolog": "^3.0",
+    "acme/unlocked": "^1.0"
+  },
+  "require-dev": {"phpunit/phpunit": "^11.0"}
+}`,
+		"composer.lock": `{
+  "packages": [
+    {"name": "laravel/framework", "version": "v11.9.2", "source": {"type": "git", "reference": "2b3e8d75f10b0ed17416282946355dc026bf326c"}},
+    {"name": "monolog/monolog", "version": "3.6.0"},
+    {"name": "psr/log", "version": "3.0.0"}
+  ],
+  "packages-dev": [
+    {"name": "phpunit/phpunit", "version": "11.2.0"}
+  ]
+}`,
+		"vendor/composer/installed.json": `{
+  "packages": [
+    {"name": "laravel/framework", "version": "v11.9.2", "install-path": "../laravel/framework"},
+    {"name": "monolog/monolog", "version": "3.5.0", "install-path": "../monolog/monolog"},
+    {"name": "psr/log", "version": "3.0.0", "install-path": "../psr/log"}
+  ]
+}`,
+		"vendor/laravel/framework/composer.json": "{}",
+		"vendor/monolog/monolog/composer.json":   "{}",
+		"vendor/psr/log/composer.json":           "{}",
+		"app/Http/.keep":                         "",
+	})
+
+	project, err := composer.LoadProject(filepath.Join(tmpDir, "app", "Http"))
+	require.NoError(t, err)
+	assert.Equal(t, tmpDir, project.Dir)
+	assert.Equal(t, filepath.Join(tmpDir, "composer.lock"), project.Lockfile)
+	assert.Equal(t, filepath.Join(tmpDir, "vendor"), project.VendorDir)
+	assert.Empty(t, project.Warnings())
+
+	assert.Equal(t, []composer.Dependency{
+		{Name: "acme/unlocked", Constraint: "^1.0", Kind: composer.KindRequire},
+		{Name: "laravel/framework", Constraint: "^11.0", Kind: composer.KindRequire, Locked: "v11.9.2", Reference: "2b3e8d75f10b0ed17416282946355dc026bf326c", Installed: "v11.9.2", Dir: filepath.Join(tmpDir, "vendor", "laravel", "framework")},
+		{Name: "monolog/monolog", Constraint: "^3.0", Kind: composer.KindRequire, Locked: "3.6.0", Installed: "3.5.0", Dir: filepath.Join(tmpDir, "vendor", "monolog", "monolog")},
+		{Name: "phpunit/phpunit", Constraint: "^11.0", Kind: composer.KindDev, Locked: "11.2.0"},
+		{Name: "psr/log", Kind: composer.KindTransitive, Locked: "3.0.0", Installed: "3.0.0", Dir: filepath.Join(tmpDir, "vendor", "psr", "log")},
+	}, project.Dependencies(), "Platform requirements are left out")
+
+	dep, err := project.Dependency("Monolog/Monolog")
+	require.NoError(t, err)
+	assert.Equal(t, "monolog/monolog", dep.Name, "Names compare without case")
+	_, err = project.Dependency("symfony/console")
+	assert.ErrorContains(t, err, "package symfony/console is not a dependency of acme/shop")
+}
+
+func TestLoadProjectWithoutLockfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"composer.json":                              `{"require": {"guzzlehttp/guzzle": "^7.8"}, "config": {"vendor-dir": "lib/vendor"}}`,
+		"lib/vendor/composer/installed.json":         `[{"name": "guzzlehttp/guzzle", "version": "7.8.1"}]`,
+		"lib/vendor/guzzlehttp/guzzle/composer.json": "{}",
+	})
+
+	project, err := composer.LoadProject(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"no composer.lock found; versions are taken from the vendor directory"}, project.Warnings())
+	assert.Equal(t, []composer.Dependency{
+		{Name: "guzzlehttp/guzzle", Constraint: "^7.8", Kind: composer.KindRequire, Installed: "7.8.1", Dir: filepath.Join(tmpDir, "lib", "vendor", "guzzlehttp", "guzzle")},
+	}, project.Dependencies(), "Composer 1 lists the installed packages in an array")
+
+	_, err = composer.LoadProject(t.TempDir())
+	assert.ErrorIs(t, err, composer.ErrNoComposerJSON)
+}
@@ -18,6 +18,25 @@ type Config struct {
 	LogLevel         string   `json:"log_level,omitempty"`
 	LogFile          string   `json:"log_file,omitempty"`
 	CustomPromptDirs []string `json:"custom_prompt_dirs,omitempty"`
+	// GOOS, GOARCH, CgoEnabled and BuildTags select the build constraints
+	// Go packages are parsed with. Empty values default to the host.
+	GOOS       string   `json:"goos,omitempty"`
+	GOARCH     string   `json:"goarch,omitempty"`
+	CgoEnabled *bool    `json:"cgo_enabled,omitempty"`
+	BuildTags  []string `json:"build_tags,omitempty"`
+	// GoEnv overrides Go environment variables (GOROOT, GOPATH, GOMODCACHE,
+	// GOFLAGS, GOPRIVATE) for the server, taking precedence over the
+	// process environment and the go/env file.
+	GoEnv map[string]string `json:"go_env,omitempty"`
+	// SourceRoots are extra directories the file tools may read, besides
+	// the module cache, GOROOT, vendor directories and replace targets.
+	SourceRoots []string `json:"source_roots,omitempty"`
+	// PythonPrefix is the prefix of the Python environment the Python
+	// tools inspect, instead of the project's .venv or venv directory.
+	PythonPrefix string `json:"python_prefix,omitempty"`
+	// LanguageAliases add to or replace the default aliases languages are
+	// requested by, e.g. {"golang": "go"}.
+	LanguageAliases map[string]string `json:"language_aliases,omitempty"`
 }
 
 func DefaultConfig() *Config {
@@ -77,6 +96,16 @@ func Load() (*Config, error) {
 
 	config.LogFile = expandPath(config.LogFile)
 
+	for i, dir := range config.SourceRoots {
+		config.SourceRoots[i] = expandPath(dir)
+	}
+
+	config.PythonPrefix = expandPath(config.PythonPrefix)
+
+	for name, value := range config.GoEnv {
+		config.GoEnv[name] = expandPath(value)
+	}
+
 	return config, nil
 }
 
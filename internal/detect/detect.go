@@ -0,0 +1,203 @@
+// Package detect finds the languages of a project from the manifests and
+// build files in its directory tree.
+package detect
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultMaxDepth is how many directory levels below the root are
+	// scanned by default, and MaxDepth the most that can be requested.
+	DefaultMaxDepth = 3
+	MaxDepth        = 8
+	// maxEvidence bounds the evidence files reported per language.
+	maxEvidence = 20
+)
+
+// manifest is a file name pattern marking a project of a language.
+type manifest struct {
+	pattern  string
+	language string
+	// contains, when set, must appear in the file for it to count, as for
+	// Gradle builds applying the Kotlin plugin.
+	contains string
+}
+
+// manifests are matched against the base names of the files scanned.
+var manifests = []manifest{
+	{pattern: "go.mod", language: "go"},
+	{pattern: "go.work", language: "go"},
+	{pattern: "package.json", language: "javascript"},
+	{pattern: "tsconfig.json", language: "typescript"},
+	{pattern: "pyproject.toml", language: "python"},
+	{pattern: "setup.py", language: "python"},
+	{pattern: "setup.cfg", language: "python"},
+	{pattern: "requirements.txt", language: "python"},
+	{pattern: "Pipfile", language: "python"},
+	{pattern: "Cargo.toml", language: "rust"},
+	{pattern: "pom.xml", language: "java"},
+	{pattern: "build.gradle", language: "java"},
+	{pattern: "build.gradle.kts", language: "java"},
+	{pattern: "pom.xml", language: "kotlin", contains: "kotlin"},
+	{pattern: "build.gradle", language: "kotlin", contains: "kotlin"},
+	{pattern: "build.gradle.kts", language: "kotlin", contains: "kotlin"},
+	{pattern: "Gemfile", language: "ruby"},
+	{pattern: "*.gemspec", language: "ruby"},
+	{pattern: "composer.json", language: "php"},
+	{pattern: "*.csproj", language: "csharp"},
+	{pattern: "*.sln", language: "csharp"},
+}
+
+// skippedDirs are never scanned: they hold dependencies or build output
+// whose manifests are not the project's own.
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"build":        true,
+	"dist":         true,
+	"__pycache__":  true,
+}
+
+// Language is a language detected in a project and the files showing it.
+type Language struct {
+	Name string `json:"name"`
+	// Evidence are the paths of the manifests found, relative to the
+	// scanned directory and shallowest first.
+	Evidence []string `json:"evidence"`
+}
+
+// Result is the outcome of a scan.
+type Result struct {
+	Dir       string     `json:"dir"`
+	Languages []Language `json:"languages"`
+	// Truncated is set when evidence files were left out.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Scan walks dir up to maxDepth levels deep, skipping hidden directories,
+// dependency and build directories, virtual environments and the paths its
+// .gitignore files exclude, and reports the languages whose manifests it
+// finds. Languages are ordered by their shallowest evidence, so the
+// project's main language comes first.
+func Scan(dir string, maxDepth int) (*Result, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	maxDepth = min(maxDepth, MaxDepth)
+
+	result := &Result{Dir: root, Languages: make([]Language, 0)}
+	byName := make(map[string]*Language)
+	var ig ignorer
+	var walk func(rel string, depth int) error
+	walk = func(rel string, depth int) error {
+		ig.load(root, rel)
+		entries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			if rel == "" {
+				return fmt.Errorf("failed to read %s: %w", dir, err)
+			}
+			return nil
+		}
+		var subdirs []string
+		for _, entry := range entries {
+			name := entry.Name()
+			entryRel := path.Join(rel, name)
+			if entry.IsDir() {
+				if strings.HasPrefix(name, ".") || skippedDirs[name] || depth == maxDepth || ig.ignored(entryRel, true) {
+					continue
+				}
+				// Virtual environments hold the manifests of installed
+				// packages.
+				if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(entryRel), "pyvenv.cfg")); err == nil {
+					continue
+				}
+				subdirs = append(subdirs, entryRel)
+				continue
+			}
+			if !entry.Type().IsRegular() || ig.ignored(entryRel, false) {
+				continue
+			}
+			for _, language := range matchManifest(filepath.Join(root, filepath.FromSlash(entryRel)), name) {
+				lang, ok := byName[language]
+				if !ok {
+					lang = &Language{Name: language, Evidence: make([]string, 0)}
+					byName[language] = lang
+				}
+				lang.Evidence = append(lang.Evidence, entryRel)
+			}
+		}
+		for _, subdir := range subdirs {
+			if err := walk(subdir, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk("", 0); err != nil {
+		return nil, err
+	}
+
+	for _, lang := range byName {
+		sort.SliceStable(lang.Evidence, func(i, j int) bool {
+			return depthOf(lang.Evidence[i]) < depthOf(lang.Evidence[j])
+		})
+		if len(lang.Evidence) > maxEvidence {
+			lang.Evidence = lang.Evidence[:maxEvidence]
+			result.Truncated = true
+		}
+		result.Languages = append(result.Languages, *lang)
+	}
+	sort.Slice(result.Languages, func(i, j int) bool {
+		a, b := result.Languages[i].Evidence[0], result.Languages[j].Evidence[0]
+		if depthOf(a) != depthOf(b) {
+			return depthOf(a) < depthOf(b)
+		}
+		return result.Languages[i].Name < result.Languages[j].Name
+	})
+	return result, nil
+}
+
+// matchManifest returns the languages the file marks.
+func matchManifest(file, name string) []string {
+	var languages []string
+	var content string
+	read := false
+	for _, m := range manifests {
+		if ok, _ := path.Match(m.pattern, name); !ok {
+			continue
+		}
+		if m.contains != "" {
+			if !read {
+				data, _ := os.ReadFile(file)
+				content, read = string(data), true
+			}
+			if !strings.Contains(content, m.contains) {
+				continue
+			}
+		}
+		languages = append(languages, m.language)
+	}
+	return languages
+}
+
+func depthOf(rel string) int {
+	return strings.Count(rel, "/")
+}
@@ -0,0 +1,68 @@
+package detect_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/detect"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func TestScan(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"go.mod": "module example.com/app\n",
+		".gitignore": `# build output
+/out/
+*.generated.json
+scratch/
+!scratch/keep/
+`,
+		"web/package.json":                    "{}",
+		"web/tsconfig.json":                   "{}",
+		"web/node_modules/react/package.json": "{}",
+		"web/schema.generated.json":           "{}",
+		"web/.gitignore":                      "legacy/\n",
+		"web/legacy/package.json":             "{}",
+		"android/build.gradle.kts":            `plugins { kotlin("jvm") version "2.0.0" }`,
+		"server/pom.xml":                      "<project/>",
+		"tools/requirements.txt":              "requests\n",
+		"tools/.venv/pyvenv.cfg":              "home = /usr/bin\n",
+		"tools/.venv/lib/site/setup.py":       "",
+		"tools/venv2/pyvenv.cfg":              "home = /usr/bin\n",
+		"tools/venv2/lib/pyproject.toml":      "",
+		"out/Cargo.toml":                      "",
+		"scratch/Gemfile":                     "",
+		"scratch/keep/composer.json":          "{}",
+		"a/b/c/d/Cargo.toml":                  "",
+		".github/workflows/package.json":      "{}",
+		"services/billing/Billing.csproj":     "<Project/>",
+	})
+
+	result, err := detect.Scan(tmpDir, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []detect.Language{
+		{Name: "go", Evidence: []string{"go.mod"}},
+		{Name: "java", Evidence: []string{"android/build.gradle.kts", "server/pom.xml"}},
+		{Name: "javascript", Evidence: []string{"web/package.json"}},
+		{Name: "kotlin", Evidence: []string{"android/build.gradle.kts"}},
+		{Name: "python", Evidence: []string{"tools/requirements.txt"}},
+		{Name: "typescript", Evidence: []string{"web/tsconfig.json"}},
+		{Name: "csharp", Evidence: []string{"services/billing/Billing.csproj"}},
+	}, result.Languages, "Ignored, hidden, dependency and too deep directories are skipped")
+
+	result, err = detect.Scan(tmpDir, 4)
+	require.NoError(t, err)
+	var names []string
+	for _, lang := range result.Languages {
+		names = append(names, lang.Name)
+	}
+	assert.Contains(t, names, "rust")
+	assert.NotContains(t, names, "ruby", "scratch/ is ignored")
+	assert.NotContains(t, names, "php", "A directory cannot be re-included once its parent is excluded")
+
+	_, err = detect.Scan(filepath.Join(tmpDir, "go.mod"), 0)
+	assert.ErrorContains(t, err, "is not a directory")
+}
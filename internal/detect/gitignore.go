@@ -0,0 +1,122 @@
+package detect
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreRule is a pattern of a .gitignore file.
+type ignoreRule struct {
+	// base is the slash-separated directory of the .gitignore file,
+	// relative to the scanned root ("" for the root).
+	base     string
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// ignorer holds the .gitignore rules of the directories scanned so far.
+type ignorer struct {
+	rules []ignoreRule
+}
+
+// load adds the rules of the .gitignore file in the directory rel of root,
+// and for the root also those of .git/info/exclude.
+func (ig *ignorer) load(root, rel string) {
+	dir := filepath.Join(root, filepath.FromSlash(rel))
+	if rel == "" {
+		ig.loadFile(filepath.Join(dir, ".git", "info", "exclude"), rel)
+	}
+	ig.loadFile(filepath.Join(dir, ".gitignore"), rel)
+}
+
+func (ig *ignorer) loadFile(file, base string) {
+	f, err := os.Open(file)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		// A slash anywhere but at the end anchors the pattern to the
+		// directory of the .gitignore file.
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		rule.pattern = line
+		ig.rules = append(ig.rules, rule)
+	}
+}
+
+// ignored reports whether the slash-separated path rel, relative to the
+// scanned root, is ignored. The last matching rule decides, as in git.
+func (ig *ignorer) ignored(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range ig.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		target := rel
+		if rule.base != "" {
+			var ok bool
+			if target, ok = strings.CutPrefix(rel, rule.base+"/"); !ok {
+				continue
+			}
+		}
+		var matched bool
+		if rule.anchored {
+			matched = matchGlob(strings.Split(rule.pattern, "/"), strings.Split(target, "/"))
+		} else {
+			matched, _ = path.Match(rule.pattern, path.Base(target))
+		}
+		if matched {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// matchGlob matches path segments against pattern segments, where "**"
+// matches any number of segments.
+func matchGlob(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlob(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchGlob(pattern[1:], segments[1:])
+}
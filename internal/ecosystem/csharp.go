@@ -0,0 +1,70 @@
+package ecosystem
+
+import (
+	"path/filepath"
+
+	"github.com/svetlyi/mcp-local-context/internal/nuget"
+)
+
+// CSharp is the ecosystem of NuGet packages restored into the global
+// packages folder. Packages usually ship compiled assemblies and their XML
+// documentation rather than sources.
+type CSharp struct {
+	dirFiles
+}
+
+func NewCSharp() *CSharp {
+	return &CSharp{}
+}
+
+func (c *CSharp) Name() string {
+	return "csharp"
+}
+
+func (c *CSharp) Detect(dir string) (string, bool) {
+	file, ok := nuget.FindProjectFile(dir)
+	if !ok {
+		return "", false
+	}
+	return filepath.Dir(file), true
+}
+
+func (c *CSharp) Load(dir string) (*Project, error) {
+	project, err := nuget.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	output := &Project{Ecosystem: c.Name(), Dir: project.Dir, Dependencies: make([]Dependency, 0), Warnings: project.Warnings()}
+	for _, dep := range project.Dependencies() {
+		output.Dependencies = append(output.Dependencies, nugetDependency(&dep))
+	}
+	return output, nil
+}
+
+func (c *CSharp) Locate(dir, name string) (*Dependency, error) {
+	project, err := nuget.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	found, err := project.Dependency(name)
+	if err != nil {
+		return nil, err
+	}
+	dep := nugetDependency(found)
+	return &dep, nil
+}
+
+func nugetDependency(found *nuget.Dependency) Dependency {
+	dep := Dependency{Name: found.ID, Version: found.Resolved, Requested: found.Requested, Root: found.Dir}
+	if found.Kind != nuget.KindDirect {
+		dep.Kind = "transitive"
+	}
+	switch {
+	case found.Dir != "":
+	case found.Resolved == "":
+		dep.Note = "version not resolved without packages.lock.json; run dotnet restore"
+	default:
+		dep.Note = "not in the global packages folder; run dotnet restore"
+	}
+	return dep
+}
@@ -0,0 +1,156 @@
+// Package ecosystem gives the languages the server supports a common
+// interface: detecting a project, listing its dependencies with their
+// versions, locating the sources of a dependency and reading them. The
+// prompts of a language tell how to work with its dependencies, and its
+// ecosystem does the work behind the shared dependency tools.
+package ecosystem
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/sandbox"
+)
+
+// ErrNoProject is returned when no registered ecosystem recognizes a
+// directory.
+var ErrNoProject = errors.New("no supported project found")
+
+// Dependency is a dependency of a project and the location of its sources.
+type Dependency struct {
+	Name string `json:"name"`
+	// Version is the exact version the project builds against, and
+	// Requested the version or range its manifest declares, when they
+	// differ.
+	Version   string `json:"version,omitempty"`
+	Requested string `json:"requested,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	// Root is the directory, or the archive, holding the sources. It is
+	// empty when they are not on disk.
+	Root string `json:"root,omitempty"`
+	Note string `json:"note,omitempty"`
+}
+
+// Project is a project detected by an ecosystem and its dependencies.
+type Project struct {
+	Ecosystem    string       `json:"ecosystem"`
+	Dir          string       `json:"dir"`
+	Dependencies []Dependency `json:"dependencies"`
+	Warnings     []string     `json:"warnings,omitempty"`
+}
+
+// Ecosystem is the dependency management of a language.
+type Ecosystem interface {
+	// Name is the language key of the ecosystem in the prompt registry.
+	Name() string
+	// Detect returns the root of the project of this ecosystem governing
+	// dir, if any.
+	Detect(dir string) (string, bool)
+	// Load reads the project governing dir and lists its dependencies.
+	Load(dir string) (*Project, error)
+	// Locate finds a dependency of the project governing dir.
+	Locate(dir, name string) (*Dependency, error)
+	// ListFiles lists a directory of the sources of dep, given its path
+	// relative to dep.Root.
+	ListFiles(dep *Dependency, path string, maxEntries int) ([]sandbox.DirEntry, bool, error)
+	// ReadFile reads a file of the sources of dep, given its path relative
+	// to dep.Root.
+	ReadFile(dep *Dependency, path string, opts sandbox.ReadOptions) (*sandbox.FileContent, error)
+}
+
+// Registry holds the ecosystems in order of preference.
+type Registry struct {
+	ecosystems []Ecosystem
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		ecosystems: make([]Ecosystem, 0),
+	}
+}
+
+func (r *Registry) Register(ecosystem Ecosystem) {
+	r.ecosystems = append(r.ecosystems, ecosystem)
+}
+
+// Names lists the names of the registered ecosystems.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.ecosystems))
+	for _, ecosystem := range r.ecosystems {
+		names = append(names, ecosystem.Name())
+	}
+	return names
+}
+
+// Get returns the ecosystem with the given name, or nil.
+func (r *Registry) Get(name string) Ecosystem {
+	for _, ecosystem := range r.ecosystems {
+		if ecosystem.Name() == name {
+			return ecosystem
+		}
+	}
+	return nil
+}
+
+// Detect returns the ecosystem of the project governing dir: the one whose
+// project root is nearest to dir, the first registered winning a tie, as
+// when a Go module also has a package.json for its tooling.
+func (r *Registry) Detect(dir string) (Ecosystem, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	var best Ecosystem
+	var bestRoot string
+	for _, ecosystem := range r.ecosystems {
+		root, ok := ecosystem.Detect(absDir)
+		if ok && len(root) > len(bestRoot) {
+			best, bestRoot = ecosystem, root
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("%w in %s or any parent directory (supported: %s)", ErrNoProject, absDir, strings.Join(r.Names(), ", "))
+	}
+	return best, nil
+}
+
+// Resolve returns the ecosystem with the given name or, without a name,
+// the one detected for dir.
+func (r *Registry) Resolve(dir, name string) (Ecosystem, error) {
+	if name == "" {
+		return r.Detect(dir)
+	}
+	if ecosystem := r.Get(name); ecosystem != nil {
+		return ecosystem, nil
+	}
+	return nil, fmt.Errorf("unknown ecosystem %s (supported: %s)", name, strings.Join(r.Names(), ", "))
+}
+
+// dirFiles serves the sources of dependencies unpacked into a directory,
+// confined to that directory.
+type dirFiles struct{}
+
+func (dirFiles) ListFiles(dep *Dependency, path string, maxEntries int) ([]sandbox.DirEntry, bool, error) {
+	sb, err := dirSandbox(dep)
+	if err != nil {
+		return nil, false, err
+	}
+	return sb.ListDir(filepath.Join(dep.Root, filepath.FromSlash(path)), maxEntries)
+}
+
+func (dirFiles) ReadFile(dep *Dependency, path string, opts sandbox.ReadOptions) (*sandbox.FileContent, error) {
+	sb, err := dirSandbox(dep)
+	if err != nil {
+		return nil, err
+	}
+	return sb.ReadFile(filepath.Join(dep.Root, filepath.FromSlash(path)), opts)
+}
+
+func dirSandbox(dep *Dependency) (*sandbox.Sandbox, error) {
+	if dep.Root == "" {
+		return nil, fmt.Errorf("the sources of %s are not on disk: %s", dep.Name, dep.Note)
+	}
+	return sandbox.New([]sandbox.Root{{Path: dep.Root, Kind: sandbox.RootPackage}})
+}
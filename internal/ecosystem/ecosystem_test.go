@@ -0,0 +1,184 @@
+package ecosystem_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/ecosystem"
+	"github.com/svetlyi/mcp-local-context/internal/goenv"
+	"github.com/svetlyi/mcp-local-context/internal/sandbox"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func newRegistry(modCache string) *ecosystem.Registry {
+	registry := ecosystem.NewRegistry()
+	registry.Register(ecosystem.NewGolang(goenv.WithOverrides(map[string]string{"GOMODCACHE": modCache, "GOFLAGS": "-mod=mod"})))
+	registry.Register(ecosystem.NewJavaScript())
+	registry.Register(ecosystem.NewPython(""))
+	registry.Register(ecosystem.NewRust())
+	registry.Register(ecosystem.NewJava())
+	registry.Register(ecosystem.NewRuby())
+	registry.Register(ecosystem.NewPHP())
+	registry.Register(ecosystem.NewCSharp())
+	return registry
+}
+
+func TestRegistryDetect(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"repo/go.mod":               "module example.com/repo\n",
+		"repo/package.json":         "{}",
+		"repo/web/package.json":     "{}",
+		"repo/web/src/index.ts":     "",
+		"repo/tools/pyproject.toml": "",
+		"repo/native/Cargo.toml":    "",
+		"repo/android/build.gradle": "",
+		"repo/legacy/Gemfile":       "",
+		"repo/admin/composer.json":  "{}",
+		"repo/api/src/Api.csproj":   "<Project />",
+	})
+	registry := newRegistry(t.TempDir())
+	assert.Equal(t, []string{"go", "javascript", "python", "rust", "java", "ruby", "php", "csharp"}, registry.Names())
+
+	for dir, want := range map[string]string{
+		"repo":         "go",
+		"repo/web/src": "javascript",
+		"repo/tools":   "python",
+		"repo/native":  "rust",
+		"repo/android": "java",
+		"repo/legacy":  "ruby",
+		"repo/admin":   "php",
+		"repo/api/src": "csharp",
+	} {
+		eco, err := registry.Detect(filepath.Join(tmpDir, dir))
+		require.NoError(t, err, dir)
+		assert.Equal(t, want, eco.Name(), "The nearest project wins, and the first registered on a tie: %s", dir)
+	}
+
+	_, err := registry.Detect(t.TempDir())
+	assert.ErrorIs(t, err, ecosystem.ErrNoProject)
+
+	eco, err := registry.Resolve(filepath.Join(tmpDir, "repo"), "javascript")
+	require.NoError(t, err)
+	assert.Equal(t, "javascript", eco.Name())
+	_, err = registry.Resolve(tmpDir, "cobol")
+	assert.ErrorContains(t, err, "unknown ecosystem cobol")
+}
+
+func TestGolang(t *testing.T) {
+	tmpDir := t.TempDir()
+	modCache := filepath.Join(tmpDir, "modcache")
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"app/go.mod": `module example.com/app
+
+go 1.22
+
+require (
+	github.com/nats-io/nats.go v1.48.0
+	golang.org/x/text v0.14.0 // indirect
+)
+`,
+		"modcache/github.com/nats-io/nats.go@v1.48.0/nats.go":          "package nats\n\nfunc Connect() {}\n",
+		"modcache/github.com/nats-io/nats.go@v1.48.0/jetstream/api.go": "package jetstream\n",
+	})
+	golang := newRegistry(modCache).Get("go")
+	projectDir := filepath.Join(tmpDir, "app")
+
+	project, err := golang.Load(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, "go", project.Ecosystem)
+	natsDir := filepath.Join(modCache, "github.com", "nats-io", "nats.go@v1.48.0")
+	assert.Equal(t, []ecosystem.Dependency{
+		{Name: "github.com/nats-io/nats.go", Version: "v1.48.0", Root: natsDir},
+		{Name: "golang.org/x/text", Version: "v0.14.0", Kind: "indirect", Note: "not downloaded; run go mod download golang.org/x/text@v0.14.0"},
+	}, project.Dependencies)
+
+	dep, err := golang.Locate(projectDir, "github.com/nats-io/nats.go/jetstream")
+	require.NoError(t, err, "Packages resolve to their module")
+	assert.Equal(t, natsDir, dep.Root)
+
+	entries, truncated, err := golang.ListFiles(dep, "", 10)
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	assert.Equal(t, []sandbox.DirEntry{{Name: "jetstream", Type: "dir"}, {Name: "nats.go", Type: "file", Size: 32}}, entries)
+
+	content, err := golang.ReadFile(dep, "nats.go", sandbox.ReadOptions{StartLine: 3, EndLine: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "func Connect() {}\n", content.Content)
+
+	_, err = golang.ReadFile(dep, "../../../../app/go.mod", sandbox.ReadOptions{})
+	assert.Error(t, err, "Files outside the source root are rejected")
+
+	_, err = golang.Locate(projectDir, "github.com/unknown/mod")
+	assert.ErrorContains(t, err, "not required")
+}
+
+func TestJava(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("GRADLE_USER_HOME", filepath.Join(tmpDir, "gradle-home"))
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"app/gradle.lockfile": "com.squareup.okio:okio:3.6.0=runtimeClasspath\n",
+		"app/build.gradle":    "",
+	})
+	jarDir := filepath.Join(tmpDir, "gradle-home", "caches", "modules-2", "files-2.1", "com.squareup.okio", "okio", "3.6.0", "abc")
+	require.NoError(t, os.MkdirAll(jarDir, 0o755))
+	jar := filepath.Join(jarDir, "okio-3.6.0-sources.jar")
+	f, err := os.Create(jar)
+	require.NoError(t, err)
+	w := zip.NewWriter(f)
+	entry, err := w.Create("okio/Buffer.kt")
+	require.NoError(t, err)
+	_, err = entry.Write([]byte("package okio\n\nclass Buffer\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, f.Close())
+
+	java := newRegistry(t.TempDir()).Get("java")
+	dep, err := java.Locate(filepath.Join(tmpDir, "app"), "okio")
+	require.NoError(t, err)
+	assert.Equal(t, "com.squareup.okio:okio", dep.Name)
+	assert.Equal(t, jar, dep.Root)
+
+	entries, _, err := java.ListFiles(dep, "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []sandbox.DirEntry{{Name: "okio", Type: "dir"}}, entries)
+	entries, _, err = java.ListFiles(dep, "okio", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []sandbox.DirEntry{{Name: "Buffer.kt", Type: "file", Size: 27}}, entries)
+
+	content, err := java.ReadFile(dep, "okio/Buffer.kt", sandbox.ReadOptions{StartLine: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "class Buffer\n", content.Content)
+	assert.Equal(t, jar+"!/okio/Buffer.kt", content.Path)
+}
+
+func TestPHP(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"composer.json":                                 `{"require": {"php": "^8.2", "monolog/monolog": "^3.0"}, "require-dev": {"phpunit/phpunit": "^11.0"}}`,
+		"composer.lock":                                 `{"packages": [{"name": "monolog/monolog", "version": "3.6.0"}, {"name": "psr/log", "version": "3.0.0"}], "packages-dev": [{"name": "phpunit/phpunit", "version": "11.2.0"}]}`,
+		"vendor/composer/installed.json":                `{"packages": [{"name": "monolog/monolog", "version": "3.6.0"}, {"name": "psr/log", "version": "3.0.0"}]}`,
+		"vendor/monolog/monolog/src/Monolog/Logger.php": "<?php\n\nnamespace Monolog;\n",
+		"vendor/psr/log/src/LoggerInterface.php":        "<?php\n",
+	})
+
+	php := newRegistry(t.TempDir()).Get("php")
+	project, err := php.Load(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, []ecosystem.Dependency{
+		{Name: "monolog/monolog", Version: "3.6.0", Requested: "^3.0", Root: filepath.Join(tmpDir, "vendor", "monolog", "monolog")},
+		{Name: "phpunit/phpunit", Version: "11.2.0", Requested: "^11.0", Kind: "require-dev", Note: "not installed; run composer install"},
+		{Name: "psr/log", Version: "3.0.0", Kind: "transitive", Root: filepath.Join(tmpDir, "vendor", "psr", "log")},
+	}, project.Dependencies)
+
+	dep, err := php.Locate(tmpDir, "monolog/monolog")
+	require.NoError(t, err)
+	content, err := php.ReadFile(dep, "src/Monolog/Logger.php", sandbox.ReadOptions{StartLine: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "namespace Monolog;\n", content.Content)
+}
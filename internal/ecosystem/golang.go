@@ -0,0 +1,92 @@
+package ecosystem
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+	"github.com/svetlyi/mcp-local-context/internal/goenv"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+)
+
+// Golang is the ecosystem of Go modules, resolved against the module cache
+// or the vendor directory.
+type Golang struct {
+	dirFiles
+	envOptions []goenv.Option
+}
+
+// NewGolang creates the Go ecosystem. The options are used to resolve the
+// Go environment of every call.
+func NewGolang(envOptions ...goenv.Option) *Golang {
+	return &Golang{envOptions: envOptions}
+}
+
+func (g *Golang) Name() string {
+	return "go"
+}
+
+func (g *Golang) Detect(dir string) (string, bool) {
+	return fsutil.FindUp(dir, "go.mod")
+}
+
+func (g *Golang) Load(dir string) (*Project, error) {
+	project, err := g.loadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	output := &Project{Ecosystem: g.Name(), Dir: project.Dir, Dependencies: make([]Dependency, 0), Warnings: project.Warnings()}
+	indirect := make(map[string]bool)
+	for _, req := range project.File.Require {
+		indirect[req.Mod.Path] = req.Indirect
+	}
+	for _, mod := range project.Modules() {
+		if mod.Main {
+			continue
+		}
+		dep := goDependency(mod)
+		if indirect[mod.Path] {
+			dep.Kind = "indirect"
+		}
+		output.Dependencies = append(output.Dependencies, dep)
+	}
+	return output, nil
+}
+
+func (g *Golang) Locate(dir, name string) (*Dependency, error) {
+	project, err := g.loadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	mod := project.ModuleForImport(name)
+	if mod == nil || mod.Main {
+		return nil, fmt.Errorf("module %s is not required by %s", name, project.Path)
+	}
+	dep := goDependency(mod)
+	return &dep, nil
+}
+
+func (g *Golang) loadProject(dir string) (*gomod.Project, error) {
+	env, err := goenv.Detect(g.envOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect Go environment: %w", err)
+	}
+	return gomod.LoadProject(dir, env.GOMODCACHE, gomod.WithModFlag(env.ModFlag()))
+}
+
+func goDependency(mod *gomod.Module) Dependency {
+	dep := Dependency{Name: mod.Path, Version: mod.Version, Root: mod.Dir}
+	switch {
+	case mod.Replace != nil && mod.Replace.Version != "":
+		dep.Note = fmt.Sprintf("replaced by %s@%s", mod.Replace.Path, mod.Replace.Version)
+	case mod.Replace != nil:
+		dep.Note = "replaced by the directory " + mod.Replace.Path
+	case mod.Vendored:
+		dep.Note = "vendored"
+	}
+	if _, err := os.Stat(mod.Dir); err != nil {
+		dep.Root = ""
+		dep.Note = fmt.Sprintf("not downloaded; run go mod download %s@%s", mod.Path, mod.Version)
+	}
+	return dep
+}
@@ -0,0 +1,106 @@
+package ecosystem
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+	"github.com/svetlyi/mcp-local-context/internal/jvm"
+	"github.com/svetlyi/mcp-local-context/internal/sandbox"
+)
+
+// Java is the ecosystem of Maven and Gradle artifacts, whose sources are
+// read from the -sources.jar files of the Maven repository and the Gradle
+// cache.
+type Java struct{}
+
+func NewJava() *Java {
+	return &Java{}
+}
+
+func (j *Java) Name() string {
+	return "java"
+}
+
+func (j *Java) Detect(dir string) (string, bool) {
+	return fsutil.FindUp(dir, "pom.xml", "build.gradle.kts", "build.gradle", "settings.gradle.kts", "settings.gradle")
+}
+
+func (j *Java) Load(dir string) (*Project, error) {
+	project, err := jvm.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	output := &Project{Ecosystem: j.Name(), Dir: project.Dir, Dependencies: make([]Dependency, 0), Warnings: project.Warnings()}
+	for _, dep := range project.Dependencies() {
+		output.Dependencies = append(output.Dependencies, jvmDependency(&dep))
+	}
+	return output, nil
+}
+
+func (j *Java) Locate(dir, name string) (*Dependency, error) {
+	project, err := jvm.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	found, err := project.Dependency(name)
+	if err != nil {
+		return nil, err
+	}
+	dep := jvmDependency(found)
+	return &dep, nil
+}
+
+func (j *Java) ListFiles(dep *Dependency, dir string, maxEntries int) ([]sandbox.DirEntry, bool, error) {
+	if dep.Root == "" {
+		return nil, false, fmt.Errorf("no jar of %s is on disk: %s", dep.Name, dep.Note)
+	}
+	entries, err := jvm.ListEntries(dep.Root, dir)
+	if err != nil {
+		return nil, false, err
+	}
+	truncated := false
+	if maxEntries > 0 && len(entries) > maxEntries {
+		entries, truncated = entries[:maxEntries], true
+	}
+	result := make([]sandbox.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		dirEntry := sandbox.DirEntry{Name: entry.Name, Type: "file", Size: entry.Size}
+		if entry.Dir {
+			dirEntry.Type = "dir"
+		}
+		result = append(result, dirEntry)
+	}
+	return result, truncated, nil
+}
+
+func (j *Java) ReadFile(dep *Dependency, name string, opts sandbox.ReadOptions) (*sandbox.FileContent, error) {
+	if dep.Root == "" {
+		return nil, fmt.Errorf("no jar of %s is on disk: %s", dep.Name, dep.Note)
+	}
+	if path.Ext(name) == ".class" {
+		return nil, fmt.Errorf("%s is a compiled class, which has no source to read", name)
+	}
+	data, err := jvm.ReadEntry(dep.Root, name)
+	if err != nil {
+		return nil, err
+	}
+	return sandbox.ReadContent(dep.Root+"!/"+name, data, opts)
+}
+
+// jvmDependency roots a dependency at its -sources.jar, or at its binary
+// jar when only that one was downloaded.
+func jvmDependency(found *jvm.Dependency) Dependency {
+	dep := Dependency{Name: found.Group + ":" + found.Artifact, Version: found.Version, Kind: found.Scope, Root: found.SourcesJar}
+	switch {
+	case found.SourcesJar != "":
+	case found.Jar != "":
+		dep.Root = found.Jar
+		dep.Note = fmt.Sprintf("only the binary jar exists; download the sources with mvn dependency:get -Dartifact=%s:jar:sources", found.Coordinates())
+	case found.Version == "":
+		dep.Note = "the version could not be resolved; run mvn dependency:resolve"
+	default:
+		dep.Note = "not downloaded"
+	}
+	return dep
+}
@@ -0,0 +1,60 @@
+package ecosystem
+
+import (
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+	"github.com/svetlyi/mcp-local-context/internal/npm"
+)
+
+// JavaScript is the ecosystem of npm packages installed in node_modules.
+type JavaScript struct {
+	dirFiles
+}
+
+func NewJavaScript() *JavaScript {
+	return &JavaScript{}
+}
+
+func (j *JavaScript) Name() string {
+	return "javascript"
+}
+
+func (j *JavaScript) Detect(dir string) (string, bool) {
+	return fsutil.FindUp(dir, "package.json")
+}
+
+func (j *JavaScript) Load(dir string) (*Project, error) {
+	project, err := npm.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	output := &Project{Ecosystem: j.Name(), Dir: project.Dir, Dependencies: make([]Dependency, 0), Warnings: project.Warnings()}
+	for _, dep := range project.Dependencies() {
+		output.Dependencies = append(output.Dependencies, npmDependency(&dep))
+	}
+	return output, nil
+}
+
+func (j *JavaScript) Locate(dir, name string) (*Dependency, error) {
+	project, err := npm.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	found, err := project.Dependency(name)
+	if err != nil {
+		return nil, err
+	}
+	dep := npmDependency(found)
+	return &dep, nil
+}
+
+func npmDependency(found *npm.Dependency) Dependency {
+	dep := Dependency{Name: found.Name, Version: found.Installed, Requested: found.Range, Kind: found.Kind, Root: found.Dir}
+	switch {
+	case found.Dir == "":
+		dep.Version = found.Locked
+		dep.Note = "not installed; run the package manager's install command"
+	case found.Locked != "" && found.Locked != found.Installed:
+		dep.Note = "the lockfile resolves " + found.Locked + "; reinstall to match it"
+	}
+	return dep
+}
@@ -0,0 +1,66 @@
+package ecosystem
+
+import (
+	"github.com/svetlyi/mcp-local-context/internal/composer"
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+)
+
+// PHP is the ecosystem of composer packages installed in the vendor
+// directory.
+type PHP struct {
+	dirFiles
+}
+
+func NewPHP() *PHP {
+	return &PHP{}
+}
+
+func (p *PHP) Name() string {
+	return "php"
+}
+
+func (p *PHP) Detect(dir string) (string, bool) {
+	return fsutil.FindUp(dir, "composer.json")
+}
+
+func (p *PHP) Load(dir string) (*Project, error) {
+	project, err := composer.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	output := &Project{Ecosystem: p.Name(), Dir: project.Dir, Dependencies: make([]Dependency, 0), Warnings: project.Warnings()}
+	for _, dep := range project.Dependencies() {
+		output.Dependencies = append(output.Dependencies, composerDependency(&dep))
+	}
+	return output, nil
+}
+
+func (p *PHP) Locate(dir, name string) (*Dependency, error) {
+	project, err := composer.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	found, err := project.Dependency(name)
+	if err != nil {
+		return nil, err
+	}
+	dep := composerDependency(found)
+	return &dep, nil
+}
+
+func composerDependency(found *composer.Dependency) Dependency {
+	dep := Dependency{Name: found.Name, Version: found.Installed, Requested: found.Constraint, Root: found.Dir}
+	if found.Kind != composer.KindRequire {
+		dep.Kind = found.Kind
+	}
+	switch {
+	case found.Dir == "":
+		dep.Version = found.Locked
+		dep.Note = "not installed; run composer install"
+	case found.Installed == "":
+		dep.Version = found.Locked
+	case found.Locked != "" && found.Locked != found.Installed:
+		dep.Note = "composer.lock resolves " + found.Locked + "; run composer install to match it"
+	}
+	return dep
+}
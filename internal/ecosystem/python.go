@@ -0,0 +1,87 @@
+package ecosystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+	"github.com/svetlyi/mcp-local-context/internal/pyenv"
+)
+
+// Python is the ecosystem of the distributions installed in a project's
+// virtual environment.
+type Python struct {
+	dirFiles
+	prefix string
+}
+
+// NewPython creates the Python ecosystem. A non-empty prefix is used as
+// the environment of every project.
+func NewPython(prefix string) *Python {
+	return &Python{prefix: prefix}
+}
+
+func (p *Python) Name() string {
+	return "python"
+}
+
+func (p *Python) Detect(dir string) (string, bool) {
+	return fsutil.FindUp(dir, "pyproject.toml", "setup.py", "setup.cfg", "requirements.txt", filepath.Join(".venv", "pyvenv.cfg"), filepath.Join("venv", "pyvenv.cfg"))
+}
+
+func (p *Python) Load(dir string) (*Project, error) {
+	env, err := pyenv.FindEnv(dir, p.prefix)
+	if err != nil {
+		return nil, err
+	}
+	dists, err := env.Distributions()
+	if err != nil {
+		return nil, err
+	}
+	root, ok := p.Detect(dir)
+	if !ok {
+		root = dir
+	}
+	output := &Project{Ecosystem: p.Name(), Dir: root, Dependencies: make([]Dependency, 0, len(dists))}
+	for _, dist := range dists {
+		output.Dependencies = append(output.Dependencies, pythonDependency(env, &dist))
+	}
+	return output, nil
+}
+
+// Locate finds an installed distribution by its name or by the name of a
+// module it installs, e.g. PyYAML or yaml.
+func (p *Python) Locate(dir, name string) (*Dependency, error) {
+	env, err := pyenv.FindEnv(dir, p.prefix)
+	if err != nil {
+		return nil, err
+	}
+	dist, err := env.Distribution(name)
+	if err != nil {
+		mod, modErr := env.FindModule(name)
+		if modErr != nil || mod.Distribution == nil {
+			return nil, err
+		}
+		dist = mod.Distribution
+	}
+	dep := pythonDependency(env, dist)
+	return &dep, nil
+}
+
+// pythonDependency roots a distribution at its package directory when it
+// installs a single package, and at site-packages otherwise.
+func pythonDependency(env *pyenv.Env, dist *pyenv.Distribution) Dependency {
+	dep := Dependency{Name: dist.Name, Version: dist.Version, Root: env.SitePackages}
+	if len(dist.ImportNames) == 1 {
+		dir := filepath.Join(env.SitePackages, dist.ImportNames[0])
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dep.Root = dir
+			return dep
+		}
+	}
+	if len(dist.ImportNames) > 0 {
+		dep.Note = "installs " + strings.Join(dist.ImportNames, ", ") + " into site-packages"
+	}
+	return dep
+}
@@ -0,0 +1,66 @@
+package ecosystem
+
+import (
+	"github.com/svetlyi/mcp-local-context/internal/bundler"
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+)
+
+// Ruby is the ecosystem of gems locked by Gemfile.lock and installed by
+// bundler or RubyGems.
+type Ruby struct {
+	dirFiles
+}
+
+func NewRuby() *Ruby {
+	return &Ruby{}
+}
+
+func (r *Ruby) Name() string {
+	return "ruby"
+}
+
+func (r *Ruby) Detect(dir string) (string, bool) {
+	return fsutil.FindUp(dir, "Gemfile")
+}
+
+func (r *Ruby) Load(dir string) (*Project, error) {
+	project, err := bundler.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	output := &Project{Ecosystem: r.Name(), Dir: project.Dir, Dependencies: make([]Dependency, 0), Warnings: project.Warnings()}
+	for _, dep := range project.Dependencies() {
+		output.Dependencies = append(output.Dependencies, gemDependency(&dep))
+	}
+	return output, nil
+}
+
+func (r *Ruby) Locate(dir, name string) (*Dependency, error) {
+	project, err := bundler.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	found, err := project.Dependency(name)
+	if err != nil {
+		return nil, err
+	}
+	dep := gemDependency(found)
+	return &dep, nil
+}
+
+func gemDependency(found *bundler.Dependency) Dependency {
+	dep := Dependency{Name: found.Name, Version: found.Locked, Requested: found.Requirement, Root: found.Dir}
+	if !found.Direct {
+		dep.Kind = "transitive"
+	}
+	switch {
+	case found.Dir != "":
+	case found.Source == bundler.SourcePath:
+		dep.Note = "path " + found.Remote + " not found"
+	case found.Locked == "":
+		dep.Note = "not in Gemfile.lock; run bundle lock"
+	default:
+		dep.Note = "not installed; run bundle install"
+	}
+	return dep
+}
@@ -0,0 +1,66 @@
+package ecosystem
+
+import (
+	"github.com/svetlyi/mcp-local-context/internal/cargo"
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+)
+
+// Rust is the ecosystem of crates locked by Cargo.lock and unpacked in the
+// cargo registry and git caches.
+type Rust struct {
+	dirFiles
+}
+
+func NewRust() *Rust {
+	return &Rust{}
+}
+
+func (r *Rust) Name() string {
+	return "rust"
+}
+
+func (r *Rust) Detect(dir string) (string, bool) {
+	return fsutil.FindUp(dir, "Cargo.toml")
+}
+
+func (r *Rust) Load(dir string) (*Project, error) {
+	project, err := cargo.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	output := &Project{Ecosystem: r.Name(), Dir: project.Dir, Dependencies: make([]Dependency, 0), Warnings: project.Warnings()}
+	for _, dep := range project.Dependencies() {
+		output.Dependencies = append(output.Dependencies, cargoDependency(&dep))
+	}
+	return output, nil
+}
+
+func (r *Rust) Locate(dir, name string) (*Dependency, error) {
+	project, err := cargo.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	found, err := project.Dependency(name)
+	if err != nil {
+		return nil, err
+	}
+	dep := cargoDependency(found)
+	return &dep, nil
+}
+
+func cargoDependency(found *cargo.Dependency) Dependency {
+	dep := Dependency{Name: found.Name, Version: found.Locked, Requested: found.Requirement, Root: found.Dir}
+	if found.Kind != cargo.KindNormal {
+		dep.Kind = found.Kind
+	}
+	switch {
+	case found.Dir != "":
+	case found.Path != "":
+		dep.Note = "path " + found.Path + " not found"
+	case found.Locked == "":
+		dep.Note = "not in Cargo.lock; run cargo generate-lockfile"
+	default:
+		dep.Note = "not downloaded; run cargo fetch"
+	}
+	return dep
+}
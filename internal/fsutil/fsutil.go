@@ -0,0 +1,37 @@
+// Package fsutil holds the file system helpers the ecosystem packages share
+// to find manifests and dependency sources on disk.
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FindUp returns the nearest directory from dir upwards holding a file with
+// one of the names, and whether there is one.
+func FindUp(dir string, names ...string) (string, bool) {
+	for current := dir; ; {
+		for _, name := range names {
+			if IsFile(filepath.Join(current, name)) {
+				return current, true
+			}
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", false
+		}
+		current = parent
+	}
+}
+
+// IsFile reports whether path exists and is not a directory.
+func IsFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// IsDir reports whether path exists and is a directory.
+func IsDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,41 @@
+package fsutil_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func TestFindUp(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"app/package.json":     "{}",
+		"app/lib/sub/index.js": "",
+	})
+	start := filepath.Join(tmpDir, "app", "lib", "sub")
+
+	dir, ok := fsutil.FindUp(start, "package.json")
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(tmpDir, "app"), dir)
+
+	dir, ok = fsutil.FindUp(start, "Cargo.toml", "index.js")
+	assert.True(t, ok, "Any of the names matches")
+	assert.Equal(t, start, dir)
+
+	_, ok = fsutil.FindUp(start, "sub")
+	assert.False(t, ok, "Directories do not match")
+}
+
+func TestIsFileIsDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{"dir/file": "x"})
+
+	assert.True(t, fsutil.IsFile(filepath.Join(tmpDir, "dir", "file")))
+	assert.False(t, fsutil.IsFile(filepath.Join(tmpDir, "dir")))
+	assert.True(t, fsutil.IsDir(filepath.Join(tmpDir, "dir")))
+	assert.False(t, fsutil.IsDir(filepath.Join(tmpDir, "dir", "file")))
+	assert.False(t, fsutil.IsDir(filepath.Join(tmpDir, "missing")))
+}
@@ -0,0 +1,261 @@
+// Package generated recognizes generated source files, which are marked
+// with a "Code generated ... DO NOT EDIT." comment, and the .proto files
+// protobuf-generated code comes from.
+package generated
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Mode selects how results from generated files are treated.
+type Mode string
+
+const (
+	// Include keeps generated files among the results.
+	Include Mode = "include"
+	// Exclude drops generated files.
+	Exclude Mode = "exclude"
+	// Only keeps generated files alone.
+	Only Mode = "only"
+	// Last keeps generated files, ordered after all other results.
+	Last Mode = "last"
+)
+
+// ParseMode validates a mode, defaulting to Include when s is empty.
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(s); mode {
+	case "":
+		return Include, nil
+	case Include, Exclude, Only, Last:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid generated mode %q: expected include, exclude, only or last", s)
+	}
+}
+
+// Apply filters and orders items according to the mode. It returns the
+// items kept and the number of items dropped.
+func Apply[T any](mode Mode, items []T, isGenerated func(T) bool) ([]T, int) {
+	kept := make([]T, 0, len(items))
+	var last []T
+	for _, item := range items {
+		generated := isGenerated(item)
+		switch {
+		case mode == Exclude && generated, mode == Only && !generated:
+			continue
+		case mode == Last && generated:
+			last = append(last, item)
+		default:
+			kept = append(kept, item)
+		}
+	}
+	kept = append(kept, last...)
+	return kept, len(items) - len(kept)
+}
+
+var (
+	// goMarkerRE is the marker defined by the Go convention for generated
+	// files (https://go.dev/s/generatedcode).
+	goMarkerRE = regexp.MustCompile(`^// Code generated (.*)DO NOT EDIT\.$`)
+	// markerRE is a looser marker for other languages and comment styles.
+	markerRE = regexp.MustCompile(`Code generated (.*)DO NOT EDIT`)
+	// generatorRE extracts the generator from the marker text.
+	generatorRE = regexp.MustCompile(`^by (\S+)`)
+	// protoSourceRE matches the source comment protoc plugins write.
+	protoSourceRE = regexp.MustCompile(`^//\s*source:\s*(\S+\.proto)\s*$`)
+)
+
+// maxHeaderLines bounds the lines read looking for the marker.
+const maxHeaderLines = 50
+
+// Info describes a source file.
+type Info struct {
+	Generated bool `json:"generated,omitempty"`
+	// Generator is the tool named by the marker, e.g. protoc-gen-go.
+	Generator string `json:"generator,omitempty"`
+	// Proto is the .proto file the code was generated from, when it can be
+	// found on disk.
+	Proto string `json:"proto,omitempty"`
+}
+
+// Classifier classifies files, caching the result of each file and the
+// .proto files of each root directory.
+type Classifier struct {
+	files  map[string]Info
+	protos map[string]map[string][]string
+}
+
+func NewClassifier() *Classifier {
+	return &Classifier{
+		files:  make(map[string]Info),
+		protos: make(map[string]map[string][]string),
+	}
+}
+
+// Classify reports whether file is generated. For protobuf-generated code,
+// the .proto source is looked up below root, the directory of the module
+// holding file.
+func (c *Classifier) Classify(file, root string) (Info, error) {
+	if info, ok := c.files[file]; ok {
+		return info, nil
+	}
+
+	info, protoSource, err := readHeader(file)
+	if err != nil {
+		return Info{}, err
+	}
+	if info.Generated {
+		if protoSource == "" {
+			protoSource = protoFromName(filepath.Base(file))
+		}
+		if protoSource != "" {
+			info.Proto, err = c.findProto(root, filepath.Dir(file), protoSource)
+			if err != nil {
+				return Info{}, err
+			}
+		}
+	}
+	c.files[file] = info
+	return info, nil
+}
+
+// IsGenerated reports whether file is generated, treating unreadable files
+// as hand-written.
+func (c *Classifier) IsGenerated(file, root string) bool {
+	info, err := c.Classify(file, root)
+	return err == nil && info.Generated
+}
+
+// readHeader reads the comments before the first code line of file,
+// looking for the generated marker and the protoc source comment.
+func readHeader(file string) (Info, string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return Info{}, "", fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer f.Close()
+
+	isGo := strings.HasSuffix(file, ".go")
+	var info Info
+	var protoSource string
+	scanner := bufio.NewScanner(f)
+	for i := 0; i < maxHeaderLines && scanner.Scan(); i++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if !isComment(trimmed) {
+			break
+		}
+
+		var marker []string
+		if isGo {
+			marker = goMarkerRE.FindStringSubmatch(line)
+		} else {
+			marker = markerRE.FindStringSubmatch(trimmed)
+		}
+		if marker != nil {
+			info.Generated = true
+			if m := generatorRE.FindStringSubmatch(marker[1]); m != nil {
+				info.Generator = strings.TrimRight(m[1], ".,;:")
+			}
+		}
+		if m := protoSourceRE.FindStringSubmatch(trimmed); m != nil {
+			protoSource = m[1]
+		}
+	}
+	// A scan error, such as a line too long, ends the header.
+	return info, protoSource, nil
+}
+
+func isComment(line string) bool {
+	for _, prefix := range []string{"//", "#", "/*", "*", "--", "<!--", ";"} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// protoFromName derives the .proto name of a protoc-generated Go file,
+// e.g. api.proto from api.pb.go, api_grpc.pb.go or api.pb.gw.go.
+func protoFromName(name string) string {
+	for _, suffix := range []string{"_grpc.pb.go", ".pb.gw.go", ".pb.go"} {
+		if base, ok := strings.CutSuffix(name, suffix); ok {
+			return base + ".proto"
+		}
+	}
+	return ""
+}
+
+// findProto resolves the source path of a protoc comment, which is relative
+// to the protoc include path: first against the root and the directory of
+// the generated file, then by file name anywhere below the root.
+func (c *Classifier) findProto(root, dir, source string) (string, error) {
+	source = filepath.FromSlash(source)
+	candidates := []string{filepath.Join(dir, filepath.Base(source)), filepath.Join(dir, source)}
+	if root != "" {
+		candidates = append(candidates, filepath.Join(root, source))
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+	if root == "" {
+		return "", nil
+	}
+
+	protos, ok := c.protos[root]
+	if !ok {
+		var err error
+		if protos, err = indexProtos(root); err != nil {
+			return "", err
+		}
+		c.protos[root] = protos
+	}
+	matches := protos[filepath.Base(source)]
+	for _, match := range matches {
+		if strings.HasSuffix(match, string(filepath.Separator)+source) {
+			return match, nil
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	return "", nil
+}
+
+// indexProtos maps the names of the .proto files below root to their paths.
+func indexProtos(root string) (map[string][]string, error) {
+	protos := make(map[string][]string)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), ".proto") {
+			protos[d.Name()] = append(protos[d.Name()], path)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to search for .proto files: %w", err)
+	}
+	return protos, nil
+}
@@ -0,0 +1,120 @@
+package generated_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/generated"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func TestClassify(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		"zz_generated.deepcopy.go": "//go:build !ignore_autogenerated\n\n// Code generated by controller-gen. DO NOT EDIT.\n\npackage v1\n",
+		"mock.go":                  "// Code generated DO NOT EDIT.\npackage mock\n",
+		"late.go":                  "package late\n\n// Code generated by hand. DO NOT EDIT.\n",
+		"loose.go":                 "// Code generated by tool, do not edit.\npackage loose\n",
+		"handwritten.go":           "// Package handwritten is not generated.\npackage handwritten\n",
+		"schema.sql":               "-- Code generated by sqlc. DO NOT EDIT.\nSELECT 1;\n",
+		"openapi.yaml":             "# Code generated by oapi-codegen, DO NOT EDIT\nopenapi: 3.0.0\n",
+	})
+
+	tests := []struct {
+		file      string
+		generated bool
+		generator string
+	}{
+		{"zz_generated.deepcopy.go", true, "controller-gen"},
+		{"mock.go", true, ""},
+		{"late.go", false, ""},
+		{"loose.go", false, ""},
+		{"handwritten.go", false, ""},
+		{"schema.sql", true, "sqlc"},
+		{"openapi.yaml", true, "oapi-codegen"},
+	}
+
+	classifier := generated.NewClassifier()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			info, err := classifier.Classify(filepath.Join(root, tt.file), root)
+			require.NoError(t, err)
+			assert.Equal(t, tt.generated, info.Generated)
+			assert.Equal(t, tt.generator, info.Generator)
+		})
+	}
+
+	_, err := classifier.Classify(filepath.Join(root, "missing.go"), root)
+	assert.Error(t, err)
+	assert.False(t, classifier.IsGenerated(filepath.Join(root, "missing.go"), root))
+}
+
+func TestClassifyProto(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		"gen/go/user/v1/user.pb.go":       "// Code generated by protoc-gen-go. DO NOT EDIT.\n// versions:\n// \tprotoc v4.25.1\n// source: user/v1/user.proto\n\npackage userv1\n",
+		"gen/go/user/v1/user_grpc.pb.go":  "// Code generated by protoc-gen-go-grpc. DO NOT EDIT.\n\npackage userv1\n",
+		"proto/user/v1/user.proto":        "syntax = \"proto3\";\n",
+		"gen/go/order/v1/order.pb.go":     "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: order/v1/order.proto\n\npackage orderv1\n",
+		"gen/go/local/local.pb.go":        "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: local.proto\n\npackage local\n",
+		"gen/go/local/local.proto":        "syntax = \"proto3\";\n",
+		"third_party/other/user/v1/x.txt": "not a proto\n",
+	})
+
+	classifier := generated.NewClassifier()
+
+	info, err := classifier.Classify(filepath.Join(root, "gen/go/user/v1/user.pb.go"), root)
+	require.NoError(t, err)
+	assert.Equal(t, "protoc-gen-go", info.Generator)
+	assert.Equal(t, filepath.Join(root, "proto/user/v1/user.proto"), info.Proto, "Found by its include path below the root")
+
+	info, err = classifier.Classify(filepath.Join(root, "gen/go/user/v1/user_grpc.pb.go"), root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "proto/user/v1/user.proto"), info.Proto, "Derived from the file name")
+
+	info, err = classifier.Classify(filepath.Join(root, "gen/go/order/v1/order.pb.go"), root)
+	require.NoError(t, err)
+	assert.True(t, info.Generated)
+	assert.Empty(t, info.Proto, "The module does not ship order.proto")
+
+	info, err = classifier.Classify(filepath.Join(root, "gen/go/local/local.pb.go"), root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "gen/go/local/local.proto"), info.Proto)
+}
+
+func TestApply(t *testing.T) {
+	items := []string{"gen-a", "b", "gen-c", "d"}
+	isGenerated := func(s string) bool { return strings.HasPrefix(s, "gen-") }
+
+	kept, omitted := generated.Apply(generated.Include, items, isGenerated)
+	assert.Equal(t, items, kept)
+	assert.Zero(t, omitted)
+
+	kept, omitted = generated.Apply(generated.Exclude, items, isGenerated)
+	assert.Equal(t, []string{"b", "d"}, kept)
+	assert.Equal(t, 2, omitted)
+
+	kept, omitted = generated.Apply(generated.Only, items, isGenerated)
+	assert.Equal(t, []string{"gen-a", "gen-c"}, kept)
+	assert.Equal(t, 2, omitted)
+
+	kept, omitted = generated.Apply(generated.Last, items, isGenerated)
+	assert.Equal(t, []string{"b", "d", "gen-a", "gen-c"}, kept)
+	assert.Zero(t, omitted)
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := generated.ParseMode("")
+	require.NoError(t, err)
+	assert.Equal(t, generated.Include, mode)
+
+	mode, err = generated.ParseMode("last")
+	require.NoError(t, err)
+	assert.Equal(t, generated.Last, mode)
+
+	_, err = generated.ParseMode("skip")
+	assert.Error(t, err)
+}
@@ -0,0 +1,320 @@
+package goanalysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/svetlyi/mcp-local-context/internal/goload"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+)
+
+// deprecatedRE matches a "Deprecated:" paragraph, following the convention
+// used by the go command and staticcheck.
+var deprecatedRE = regexp.MustCompile(`(?s)(?:^|\n\n)Deprecated: *(.*?)(?:$|\n\n)`)
+
+// Deprecation is a deprecated package or symbol of a dependency.
+type Deprecation struct {
+	Symbol   string `json:"symbol"`
+	Kind     string `json:"kind"`
+	Package  string `json:"package"`
+	Message  string `json:"message"`
+	Position string `json:"position"`
+	// UsedAt lists the references to the symbol in the project.
+	UsedAt []Reference `json:"used_at,omitempty"`
+}
+
+// DeprecatedModule is a module whose go.mod carries a deprecation comment.
+type DeprecatedModule struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Message string `json:"message"`
+}
+
+// DeprecationReport lists deprecated symbols and modules.
+type DeprecationReport struct {
+	Symbols  []*Deprecation     `json:"symbols"`
+	Modules  []DeprecatedModule `json:"modules"`
+	Warnings []string           `json:"warnings,omitempty"`
+}
+
+// deprecationIndex maps objects of one package to their deprecation.
+type deprecationIndex struct {
+	pkg     *Deprecation
+	objects map[types.Object]*Deprecation
+	list    []*Deprecation
+}
+
+// ReportDeprecations builds a deprecation report. When pkgs is empty, it
+// covers every deprecated dependency symbol the project uses, and the
+// deprecation comments of all required modules. Otherwise it lists every
+// deprecated symbol of pkgs, whether used or not, and the deprecation of the
+// modules providing them.
+func ReportDeprecations(loader *goload.Loader, pkgs []*goload.Package, includeTests bool) (*DeprecationReport, error) {
+	report := &DeprecationReport{
+		Symbols: make([]*Deprecation, 0),
+		Modules: make([]DeprecatedModule, 0),
+	}
+
+	indexes := make(map[*types.Package]*deprecationIndex)
+	indexFor := func(pkg *goload.Package) *deprecationIndex {
+		index, ok := indexes[pkg.Types]
+		if !ok {
+			index = indexDeprecations(loader.Fset(), pkg)
+			indexes[pkg.Types] = index
+		}
+		return index
+	}
+	for _, pkg := range pkgs {
+		report.Symbols = append(report.Symbols, indexFor(pkg).list...)
+	}
+
+	main := loader.Project().Main()
+	refs := newReferenceBuilder(loader)
+	used := make(map[*Deprecation]bool)
+	warnings, err := walkProjectUses(loader, includeTests, func(pkg *goload.Package, file *ast.File, ident *ast.Ident, obj types.Object) {
+		var target *types.Package
+		if pkgName, ok := obj.(*types.PkgName); ok {
+			target = pkgName.Imported()
+		} else {
+			target = obj.Pkg()
+		}
+		if target == nil {
+			return
+		}
+
+		var index *deprecationIndex
+		if len(pkgs) > 0 {
+			index = indexes[target]
+		} else if dep := loader.PackageOf(target); dep != nil && dep.Module != main {
+			index = indexFor(dep)
+		}
+		if index == nil {
+			return
+		}
+
+		d := index.lookup(obj)
+		if d == nil {
+			return
+		}
+		d.UsedAt = append(d.UsedAt, refs.build(pkg, file, ident))
+		if !used[d] {
+			used[d] = true
+			if len(pkgs) == 0 {
+				report.Symbols = append(report.Symbols, d)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.Warnings = warnings
+
+	for _, d := range report.Symbols {
+		sortReferences(d.UsedAt)
+	}
+	sort.SliceStable(report.Symbols, func(i, j int) bool {
+		return report.Symbols[i].Symbol < report.Symbols[j].Symbol
+	})
+
+	modules := loader.Project().Modules()
+	if len(pkgs) > 0 {
+		modules = modules[:0:0]
+		seen := make(map[*gomod.Module]bool)
+		for _, pkg := range pkgs {
+			if pkg.Module != nil && !seen[pkg.Module] {
+				seen[pkg.Module] = true
+				modules = append(modules, pkg.Module)
+			}
+		}
+	}
+	for _, mod := range modules {
+		if mod.Main {
+			continue
+		}
+		message, err := moduleDeprecation(loader.Project(), mod)
+		if err != nil {
+			report.Warnings = append(report.Warnings, err.Error())
+			continue
+		}
+		if message != "" {
+			report.Modules = append(report.Modules, DeprecatedModule{Path: mod.Path, Version: mod.Version, Message: message})
+		}
+	}
+
+	return report, nil
+}
+
+func (index *deprecationIndex) lookup(obj types.Object) *Deprecation {
+	if _, ok := obj.(*types.PkgName); ok {
+		return index.pkg
+	}
+	switch o := obj.(type) {
+	case *types.Func:
+		obj = o.Origin()
+	case *types.Var:
+		obj = o.Origin()
+	}
+	return index.objects[obj]
+}
+
+// moduleDeprecation returns the deprecation message of a module. Like the
+// go command, it reads the go.mod of the newest version in the module cache,
+// as a module is usually deprecated in a release after the one required.
+func moduleDeprecation(project *gomod.Project, mod *gomod.Module) (string, error) {
+	goModPath, version := project.LatestGoMod(mod)
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read go.mod of %s@%s: %w", mod.Path, version, err)
+	}
+	file, err := modfile.ParseLax(goModPath, data, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse go.mod of %s@%s: %w", mod.Path, version, err)
+	}
+	if file.Module == nil {
+		return "", nil
+	}
+	return file.Module.Deprecated, nil
+}
+
+// indexDeprecations collects the deprecated exported declarations of pkg.
+func indexDeprecations(fset *token.FileSet, pkg *goload.Package) *deprecationIndex {
+	index := &deprecationIndex{objects: make(map[types.Object]*Deprecation)}
+	if pkg.Types == nil {
+		return index
+	}
+
+	add := func(obj types.Object, typeName *types.TypeName, doc *ast.CommentGroup, pos token.Pos) {
+		message := deprecationMessage(doc)
+		if obj == nil || message == "" {
+			return
+		}
+		symbol := &Symbol{Package: pkg, Type: typeName, Object: obj}
+		d := &Deprecation{
+			Symbol:   symbol.Name(),
+			Kind:     symbol.Kind(),
+			Package:  pkg.Path,
+			Message:  message,
+			Position: fset.Position(pos).String(),
+		}
+		index.objects[obj] = d
+		index.list = append(index.list, d)
+	}
+
+	scope := pkg.Types.Scope()
+	for _, file := range pkg.Files {
+		if message := deprecationMessage(file.Doc); message != "" && index.pkg == nil {
+			index.pkg = &Deprecation{
+				Symbol:   pkg.Path,
+				Kind:     "package",
+				Package:  pkg.Path,
+				Message:  message,
+				Position: fset.Position(file.Package).String(),
+			}
+			index.list = append(index.list, index.pkg)
+		}
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if !decl.Name.IsExported() {
+					continue
+				}
+				if decl.Recv == nil {
+					add(scope.Lookup(decl.Name.Name), nil, decl.Doc, decl.Name.Pos())
+					continue
+				}
+				typeName := receiverTypeName(scope, decl.Recv)
+				if typeName == nil {
+					continue
+				}
+				add(lookupMember(typeName, pkg.Types, decl.Name.Name), typeName, decl.Doc, decl.Name.Pos())
+
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						if !spec.Name.IsExported() {
+							continue
+						}
+						typeName, _ := scope.Lookup(spec.Name.Name).(*types.TypeName)
+						if typeName == nil {
+							continue
+						}
+						add(typeName, nil, specDoc(decl, spec.Doc), spec.Name.Pos())
+						for _, field := range memberFields(spec.Type) {
+							for _, name := range field.Names {
+								if name.IsExported() {
+									add(lookupMember(typeName, pkg.Types, name.Name), typeName, field.Doc, name.Pos())
+								}
+							}
+						}
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							if name.IsExported() {
+								add(scope.Lookup(name.Name), nil, specDoc(decl, spec.Doc), name.Pos())
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return index
+}
+
+// specDoc returns the documentation of a spec, falling back to the doc of
+// its declaration group.
+func specDoc(decl *ast.GenDecl, doc *ast.CommentGroup) *ast.CommentGroup {
+	if doc != nil {
+		return doc
+	}
+	return decl.Doc
+}
+
+func memberFields(expr ast.Expr) []*ast.Field {
+	switch t := expr.(type) {
+	case *ast.StructType:
+		return t.Fields.List
+	case *ast.InterfaceType:
+		return t.Methods.List
+	}
+	return nil
+}
+
+func receiverTypeName(scope *types.Scope, recv *ast.FieldList) *types.TypeName {
+	if len(recv.List) == 0 {
+		return nil
+	}
+	name := strings.TrimPrefix(receiverString(recv.List[0].Type), "*")
+	typeName, _ := scope.Lookup(name).(*types.TypeName)
+	return typeName
+}
+
+func lookupMember(typeName *types.TypeName, pkg *types.Package, name string) types.Object {
+	obj, _, _ := types.LookupFieldOrMethod(typeName.Type(), true, pkg, name)
+	return obj
+}
+
+// deprecationMessage returns the text of the "Deprecated:" paragraph of doc,
+// or "" if there is none.
+func deprecationMessage(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	match := deprecatedRE.FindStringSubmatch(doc.Text())
+	if match == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(match[1]), " ")
+}
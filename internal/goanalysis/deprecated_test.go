@@ -0,0 +1,118 @@
+package goanalysis_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/goanalysis"
+	"github.com/svetlyi/mcp-local-context/internal/goload"
+)
+
+var deprecatedFixture = map[string]string{
+	"modcache/example.com/old@v1.0.0/go.mod": `// Deprecated: use example.com/new instead.
+module example.com/old
+`,
+	"modcache/example.com/old@v1.0.0/old.go": `package old
+
+// Client talks to the service.
+type Client struct {
+	// Timeout is the request timeout.
+	//
+	// Deprecated: use Options.Timeout.
+	Timeout int
+}
+
+// Get fetches a value.
+//
+// Deprecated: Get does not support contexts.
+// Use GetContext instead.
+func (c *Client) Get() {}
+
+func (c *Client) GetContext() {}
+
+// Dial connects to the service.
+//
+// Deprecated: use NewClient.
+func Dial() *Client { return nil }
+
+// Deprecated: these modes are ignored.
+const (
+	ModeA = iota
+	ModeB
+)
+`,
+	"app/go.mod": "module example.com/app\n\nrequire example.com/old v1.0.0\n",
+	"app/main.go": `package main
+
+import "example.com/old"
+
+func main() {
+	c := old.Dial()
+	c.GetContext()
+	c.Get()
+}
+`,
+}
+
+func TestReportDeprecationsUsed(t *testing.T) {
+	loader := setupProject(t, deprecatedFixture)
+
+	report, err := goanalysis.ReportDeprecations(loader, nil, false)
+	require.NoError(t, err)
+
+	require.Len(t, report.Symbols, 2, "Only used deprecated symbols should be reported")
+	assert.Equal(t, "example.com/old.Client.Get", report.Symbols[0].Symbol)
+	assert.Equal(t, "Get does not support contexts. Use GetContext instead.", report.Symbols[0].Message)
+	require.Len(t, report.Symbols[0].UsedAt, 1)
+	assert.Equal(t, 8, report.Symbols[0].UsedAt[0].Line)
+	assert.Equal(t, "example.com/old.Dial", report.Symbols[1].Symbol)
+
+	require.Len(t, report.Modules, 1)
+	assert.Equal(t, "example.com/old", report.Modules[0].Path)
+	assert.Equal(t, "use example.com/new instead.", report.Modules[0].Message)
+}
+
+func TestReportDeprecationsPackage(t *testing.T) {
+	loader := setupProject(t, deprecatedFixture)
+
+	pkg, err := loader.Load("example.com/old")
+	require.NoError(t, err)
+
+	report, err := goanalysis.ReportDeprecations(loader, []*goload.Package{pkg}, false)
+	require.NoError(t, err)
+
+	symbols := make(map[string]*goanalysis.Deprecation)
+	for _, d := range report.Symbols {
+		symbols[d.Symbol] = d
+	}
+	assert.Len(t, symbols, 5)
+	assert.Equal(t, "field", symbols["example.com/old.Client.Timeout"].Kind)
+	assert.Equal(t, "use Options.Timeout.", symbols["example.com/old.Client.Timeout"].Message)
+	assert.Equal(t, "these modes are ignored.", symbols["example.com/old.ModeB"].Message, "Group doc should apply to every spec")
+	assert.Empty(t, symbols["example.com/old.ModeA"].UsedAt)
+	assert.Len(t, symbols["example.com/old.Dial"].UsedAt, 1)
+	assert.NotContains(t, symbols, "example.com/old.Client.GetContext")
+}
+
+func TestReportDeprecationsLatestVersion(t *testing.T) {
+	loader := setupProject(t, map[string]string{
+		"modcache/example.com/dep@v1.0.0/go.mod": "module example.com/dep\n",
+		"modcache/example.com/dep@v1.0.0/dep.go": "package dep\n\nfunc Do() {}\n",
+		// The module was deprecated after the required version; only the
+		// newer go.mod files have been downloaded.
+		"modcache/cache/download/example.com/dep/@v/v1.0.0.mod":      "module example.com/dep\n",
+		"modcache/cache/download/example.com/dep/@v/v1.2.0.mod":      "// Deprecated: moved to example.com/dep2.\nmodule example.com/dep\n",
+		"modcache/cache/download/example.com/dep/@v/v1.3.0-rc.1.mod": "module example.com/dep\n",
+		"app/go.mod":  "module example.com/app\n\nrequire example.com/dep v1.0.0\n",
+		"app/main.go": "package main\n\nimport \"example.com/dep\"\n\nfunc main() { dep.Do() }\n",
+	})
+
+	report, err := goanalysis.ReportDeprecations(loader, nil, false)
+	require.NoError(t, err)
+
+	require.Len(t, report.Modules, 1, "The newest release's go.mod should be read, not the pre-release's")
+	assert.Equal(t, "example.com/dep", report.Modules[0].Path)
+	assert.Equal(t, "v1.0.0", report.Modules[0].Version)
+	assert.Equal(t, "moved to example.com/dep2.", report.Modules[0].Message)
+}
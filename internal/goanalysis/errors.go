@@ -0,0 +1,342 @@
+package goanalysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/goload"
+)
+
+// qualifiedErrRE matches sentinel errors of other packages mentioned in
+// documentation, such as io.EOF or fs.ErrNotExist.
+var qualifiedErrRE = regexp.MustCompile(`\b[a-z][a-z0-9]*\.(?:Err[A-Z0-9]\w*|EOF|ErrUnexpectedEOF)\b`)
+
+// ErrorValue is an exported package-level variable holding an error, such
+// as io.EOF or sql.ErrNoRows, to be matched with errors.Is.
+type ErrorValue struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Doc  string `json:"doc,omitempty"`
+	// Message is the text the error was created with, when it is created
+	// with errors.New or fmt.Errorf.
+	Message string `json:"message,omitempty"`
+	// Wraps lists the errors wrapped with %w by fmt.Errorf.
+	Wraps    []string `json:"wraps,omitempty"`
+	Position string   `json:"position"`
+}
+
+// ErrorType is an exported type implementing error, to be matched with
+// errors.As.
+type ErrorType struct {
+	Name string `json:"name"`
+	// Pointer reports that only *T implements error, so errors.As needs a
+	// target of type *T.
+	Pointer bool   `json:"pointer,omitempty"`
+	Doc     string `json:"doc,omitempty"`
+	// Unwrap is the result type of the type's Unwrap method, "error" or
+	// "[]error", or empty when it wraps nothing.
+	Unwrap string `json:"unwrap,omitempty"`
+	// HasIs and HasAs report custom Is and As methods, which change how
+	// errors.Is and errors.As match the error.
+	HasIs    bool   `json:"has_is,omitempty"`
+	HasAs    bool   `json:"has_as,omitempty"`
+	Position string `json:"position"`
+}
+
+// ErrorFunc is an exported function or method returning an error whose
+// documentation names specific errors.
+type ErrorFunc struct {
+	Name      string   `json:"name"`
+	Signature string   `json:"signature"`
+	Doc       string   `json:"doc"`
+	Errors    []string `json:"errors"`
+	Position  string   `json:"position"`
+}
+
+// ErrorCatalog lists the errors a package exposes.
+type ErrorCatalog struct {
+	Package   string       `json:"package"`
+	Values    []ErrorValue `json:"values"`
+	Types     []ErrorType  `json:"types"`
+	Functions []ErrorFunc  `json:"functions"`
+}
+
+// ListErrors catalogs the exported sentinel errors, error types and
+// functions documented as returning them in pkg.
+func ListErrors(loader *goload.Loader, pkg *goload.Package) (*ErrorCatalog, error) {
+	if pkg.Types == nil {
+		return nil, fmt.Errorf("package %s could not be type-checked", pkg.Path)
+	}
+	catalog := &ErrorCatalog{
+		Package:   pkg.Path,
+		Values:    make([]ErrorValue, 0),
+		Types:     make([]ErrorType, 0),
+		Functions: make([]ErrorFunc, 0),
+	}
+	fset := loader.Fset()
+	scope := pkg.Types.Scope()
+	qualifier := types.RelativeTo(pkg.Types)
+
+	var funcs []*ast.FuncDecl
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				funcs = append(funcs, decl)
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.ValueSpec:
+						catalog.Values = append(catalog.Values, errorValues(fset, scope, qualifier, decl, spec)...)
+					case *ast.TypeSpec:
+						if errType := errorType(fset, scope, pkg.Types, decl, spec); errType != nil {
+							catalog.Types = append(catalog.Types, *errType)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Functions are matched against the names of the package's own errors.
+	var names []string
+	for _, value := range catalog.Values {
+		names = append(names, value.Name)
+	}
+	for _, errType := range catalog.Types {
+		names = append(names, errType.Name)
+	}
+	patterns := make(map[string]*regexp.Regexp, len(names))
+	for _, name := range names {
+		patterns[name] = regexp.MustCompile(`(^|[^\w.])\*?` + regexp.QuoteMeta(name) + `\b`)
+	}
+	for _, decl := range funcs {
+		if errFunc := errorFunc(fset, scope, pkg.Types, qualifier, decl, names, patterns); errFunc != nil {
+			catalog.Functions = append(catalog.Functions, *errFunc)
+		}
+	}
+
+	sort.Slice(catalog.Values, func(i, j int) bool { return catalog.Values[i].Name < catalog.Values[j].Name })
+	sort.Slice(catalog.Types, func(i, j int) bool { return catalog.Types[i].Name < catalog.Types[j].Name })
+	sort.Slice(catalog.Functions, func(i, j int) bool { return catalog.Functions[i].Name < catalog.Functions[j].Name })
+	return catalog, nil
+}
+
+func errorValues(fset *token.FileSet, scope *types.Scope, qualifier types.Qualifier, decl *ast.GenDecl, spec *ast.ValueSpec) []ErrorValue {
+	if decl.Tok != token.VAR {
+		return nil
+	}
+	var values []ErrorValue
+	for i, name := range spec.Names {
+		if !name.IsExported() {
+			continue
+		}
+		v, ok := scope.Lookup(name.Name).(*types.Var)
+		if !ok || !implementsError(v.Type()) {
+			continue
+		}
+		value := ErrorValue{
+			Name:     name.Name,
+			Type:     types.TypeString(v.Type(), qualifier),
+			Doc:      docText(specDoc(decl, spec.Doc)),
+			Position: fset.Position(name.Pos()).String(),
+		}
+		if i < len(spec.Values) {
+			value.Message, value.Wraps = errorConstructor(spec.Values[i])
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// errorConstructor extracts the message and wrapped errors of an
+// errors.New or fmt.Errorf call.
+func errorConstructor(expr ast.Expr) (string, []string) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", nil
+	}
+	fun, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", nil
+	}
+	pkgIdent, ok := fun.X.(*ast.Ident)
+	if !ok {
+		return "", nil
+	}
+	isNew := pkgIdent.Name == "errors" && fun.Sel.Name == "New"
+	isErrorf := pkgIdent.Name == "fmt" && fun.Sel.Name == "Errorf"
+	if !isNew && !isErrorf {
+		return "", nil
+	}
+
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", nil
+	}
+	message, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", nil
+	}
+	if !isErrorf || !strings.Contains(message, "%w") {
+		return message, nil
+	}
+
+	// Pair the verbs with their arguments to find the %w operands.
+	var wraps []string
+	args := call.Args[1:]
+	arg := 0
+	for i := 0; i < len(message)-1 && arg < len(args); i++ {
+		if message[i] != '%' {
+			continue
+		}
+		i++
+		if message[i] == '%' {
+			continue
+		}
+	flags:
+		for ; i < len(message); i++ {
+			switch c := message[i]; {
+			case c == '*':
+				// A * width or precision takes an operand of its own.
+				arg++
+			case c == '[':
+				// An explicit argument index such as %[2]w.
+				end := strings.IndexByte(message[i:], ']')
+				if end < 0 {
+					break flags
+				}
+				if n, err := strconv.Atoi(message[i+1 : i+end]); err == nil {
+					arg = n - 1
+				}
+				i += end
+			case strings.IndexByte("+-# 0123456789.", c) < 0:
+				break flags
+			}
+		}
+		if i < len(message) && message[i] == 'w' && arg >= 0 && arg < len(args) {
+			wraps = append(wraps, types.ExprString(args[arg]))
+		}
+		arg++
+	}
+	return message, wraps
+}
+
+func errorType(fset *token.FileSet, scope *types.Scope, pkg *types.Package, decl *ast.GenDecl, spec *ast.TypeSpec) *ErrorType {
+	if !spec.Name.IsExported() {
+		return nil
+	}
+	tn, ok := scope.Lookup(spec.Name.Name).(*types.TypeName)
+	if !ok || types.IsInterface(tn.Type()) {
+		return nil
+	}
+
+	errType := &ErrorType{
+		Name:     spec.Name.Name,
+		Doc:      docText(specDoc(decl, spec.Doc)),
+		Position: fset.Position(spec.Name.Pos()).String(),
+	}
+	switch {
+	case implementsError(tn.Type()):
+	case implementsError(types.NewPointer(tn.Type())):
+		errType.Pointer = true
+	default:
+		return nil
+	}
+
+	if unwrap, ok := lookupMember(tn, pkg, "Unwrap").(*types.Func); ok {
+		sig := unwrap.Type().(*types.Signature)
+		if sig.Params().Len() == 0 && sig.Results().Len() == 1 {
+			errType.Unwrap = types.TypeString(sig.Results().At(0).Type(), nil)
+		}
+	}
+	_, errType.HasIs = lookupMember(tn, pkg, "Is").(*types.Func)
+	_, errType.HasAs = lookupMember(tn, pkg, "As").(*types.Func)
+	return errType
+}
+
+func errorFunc(fset *token.FileSet, scope *types.Scope, pkg *types.Package, qualifier types.Qualifier, decl *ast.FuncDecl, names []string, patterns map[string]*regexp.Regexp) *ErrorFunc {
+	if !decl.Name.IsExported() || decl.Doc == nil {
+		return nil
+	}
+	name := decl.Name.Name
+	var obj types.Object
+	if decl.Recv == nil {
+		obj = scope.Lookup(name)
+	} else {
+		typeName := receiverTypeName(scope, decl.Recv)
+		if typeName == nil || !typeName.Exported() {
+			return nil
+		}
+		obj = lookupMember(typeName, pkg, name)
+		name = typeName.Name() + "." + name
+	}
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return nil
+	}
+	sig := fn.Type().(*types.Signature)
+	returnsError := false
+	for i := 0; i < sig.Results().Len(); i++ {
+		if implementsError(sig.Results().At(i).Type()) {
+			returnsError = true
+		}
+	}
+	if !returnsError {
+		return nil
+	}
+
+	doc := docText(decl.Doc)
+	errs := mentionedErrors(doc, names, patterns)
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ErrorFunc{
+		Name:      name,
+		Signature: types.TypeString(sig, qualifier)[len("func"):],
+		Doc:       doc,
+		Errors:    errs,
+		Position:  fset.Position(decl.Name.Pos()).String(),
+	}
+}
+
+// mentionedErrors returns the errors named in doc: the package's own
+// errors in names, matched by patterns, and sentinel errors of other
+// packages.
+func mentionedErrors(doc string, names []string, patterns map[string]*regexp.Regexp) []string {
+	var errs []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			errs = append(errs, name)
+		}
+	}
+	for _, name := range names {
+		if patterns[name].MatchString(doc) {
+			add(name)
+		}
+	}
+	for _, name := range qualifiedErrRE.FindAllString(doc, -1) {
+		add(name)
+	}
+	return errs
+}
+
+func implementsError(typ types.Type) bool {
+	errorType := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	return types.Implements(typ, errorType)
+}
+
+func docText(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.TrimSpace(doc.Text())
+}
@@ -0,0 +1,106 @@
+package goanalysis_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/goanalysis"
+)
+
+var errorsFixture = map[string]string{
+	"modcache/example.com/dep@v1.0.0/go.mod": "module example.com/dep\n",
+	"modcache/example.com/dep@v1.0.0/dep.go": `package dep
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrNotFound is returned when the key does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrTruncated wraps io.ErrUnexpectedEOF.
+	ErrTruncated = fmt.Errorf("record %d: %w", 3, io.ErrUnexpectedEOF)
+	// ErrPadded wraps io.EOF after a * width operand.
+	ErrPadded = fmt.Errorf("record %*d: %w", 8, 3, io.EOF)
+
+	errInternal = errors.New("internal")
+	Count       = 3
+)
+
+// TimeoutError reports an operation that took too long.
+type TimeoutError struct {
+	Op  string
+	Err error
+}
+
+func (e *TimeoutError) Error() string { return e.Op + ": timeout" }
+
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// MultiError collects several errors.
+type MultiError []error
+
+func (m MultiError) Error() string { return "multiple errors" }
+
+func (m MultiError) Unwrap() []error { return m }
+
+func (m MultiError) Is(target error) bool { return false }
+
+type Store struct{}
+
+// Get returns the value of key, or ErrNotFound if there is none. A
+// *TimeoutError is returned when the store does not answer, and io.EOF
+// when it is closed.
+func (s *Store) Get(key string) (string, error) { return "", nil }
+
+// Put stores a value.
+func (s *Store) Put(key, value string) error { return nil }
+
+// Lookup calls Get and reports ErrNotFound as false.
+func Lookup(key string) bool { return false }
+`,
+	"app/go.mod":  "module example.com/app\n\nrequire example.com/dep v1.0.0\n",
+	"app/main.go": "package main\n\nimport _ \"example.com/dep\"\n\nfunc main() {}\n",
+}
+
+func TestListErrors(t *testing.T) {
+	loader := setupProject(t, errorsFixture)
+	pkg, err := loader.Load("example.com/dep")
+	require.NoError(t, err)
+
+	catalog, err := goanalysis.ListErrors(loader, pkg)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/dep", catalog.Package)
+
+	require.Len(t, catalog.Values, 3)
+	assert.Equal(t, "ErrNotFound", catalog.Values[0].Name)
+	assert.Equal(t, "error", catalog.Values[0].Type)
+	assert.Equal(t, "not found", catalog.Values[0].Message)
+	assert.Equal(t, "ErrNotFound is returned when the key does not exist.", catalog.Values[0].Doc)
+	assert.Empty(t, catalog.Values[0].Wraps)
+	assert.Equal(t, "ErrPadded", catalog.Values[1].Name)
+	assert.Equal(t, []string{"io.EOF"}, catalog.Values[1].Wraps, "A * width takes an operand")
+	assert.Equal(t, "ErrTruncated", catalog.Values[2].Name)
+	assert.Equal(t, []string{"io.ErrUnexpectedEOF"}, catalog.Values[2].Wraps)
+
+	require.Len(t, catalog.Types, 2)
+	multi, timeout := catalog.Types[0], catalog.Types[1]
+	assert.Equal(t, "MultiError", multi.Name)
+	assert.False(t, multi.Pointer)
+	assert.Equal(t, "[]error", multi.Unwrap)
+	assert.True(t, multi.HasIs)
+	assert.False(t, multi.HasAs)
+	assert.Equal(t, "TimeoutError", timeout.Name)
+	assert.True(t, timeout.Pointer, "Only *TimeoutError implements error")
+	assert.Equal(t, "error", timeout.Unwrap)
+	assert.Equal(t, "TimeoutError reports an operation that took too long.", timeout.Doc)
+
+	require.Len(t, catalog.Functions, 1, "Put names no errors and Lookup returns none")
+	get := catalog.Functions[0]
+	assert.Equal(t, "Store.Get", get.Name)
+	assert.Equal(t, "(key string) (string, error)", get.Signature)
+	assert.Equal(t, []string{"ErrNotFound", "TimeoutError", "io.EOF"}, get.Errors)
+}
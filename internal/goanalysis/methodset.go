@@ -0,0 +1,226 @@
+package goanalysis
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"github.com/svetlyi/mcp-local-context/internal/goload"
+)
+
+// EmbedStep is an embedded field passed through to reach a promoted member.
+type EmbedStep struct {
+	Field string `json:"field"`
+	Type  string `json:"type"`
+}
+
+// Method is a member of a type's method set.
+type Method struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+	// Receiver is the type declaring the method, e.g. *net/http.Client.
+	Receiver string `json:"receiver"`
+	// Via lists the embedded fields a promoted method comes through.
+	Via []EmbedStep `json:"via,omitempty"`
+	// PointerOnly marks methods in the method set of *T but not of T.
+	PointerOnly bool   `json:"pointer_only,omitempty"`
+	Position    string `json:"position,omitempty"`
+}
+
+// Field is a field of a struct type, including promoted fields.
+type Field struct {
+	Name     string      `json:"name"`
+	Type     string      `json:"type"`
+	Embedded bool        `json:"embedded,omitempty"`
+	Tag      string      `json:"tag,omitempty"`
+	Via      []EmbedStep `json:"via,omitempty"`
+	Position string      `json:"position,omitempty"`
+}
+
+// TypeMembers is the expanded method and field set of a named type.
+type TypeMembers struct {
+	Type       string   `json:"type"`
+	Underlying string   `json:"underlying"`
+	Methods    []Method `json:"methods"`
+	Fields     []Field  `json:"fields"`
+	// Ambiguous lists fields and methods promoted from several embedded
+	// fields at the same depth, which cannot be used without qualification.
+	Ambiguous []string `json:"ambiguous,omitempty"`
+}
+
+// ExpandType returns the method sets of T and *T and the flattened fields
+// of the type named by symbol, including members promoted through embedded
+// fields. Unexported members of other packages are left out unless
+// includeUnexported is set.
+func ExpandType(loader *goload.Loader, symbol *Symbol, includeUnexported bool) (*TypeMembers, error) {
+	tn, ok := symbol.Object.(*types.TypeName)
+	if !ok || symbol.Type != nil {
+		return nil, fmt.Errorf("%s is a %s, not a type", symbol.Name(), symbol.Kind())
+	}
+	named := tn.Type()
+	pkg := tn.Pkg()
+	qualifier := types.RelativeTo(pkg)
+	fset := loader.Fset()
+
+	members := &TypeMembers{
+		Type:       symbol.Name(),
+		Underlying: types.TypeString(named.Underlying(), qualifier),
+		Methods:    make([]Method, 0),
+		Fields:     make([]Field, 0),
+	}
+	visible := func(obj types.Object) bool {
+		return includeUnexported || obj.Exported()
+	}
+
+	valueSet := types.NewMethodSet(named)
+	var pointerSet *types.MethodSet
+	if _, isInterface := named.Underlying().(*types.Interface); isInterface {
+		// Pointers to interfaces have no methods.
+		pointerSet = valueSet
+	} else {
+		pointerSet = types.NewMethodSet(types.NewPointer(named))
+	}
+	for i := 0; i < pointerSet.Len(); i++ {
+		sel := pointerSet.At(i)
+		fn := sel.Obj().(*types.Func)
+		if !visible(fn) {
+			continue
+		}
+		sig := fn.Type().(*types.Signature)
+		method := Method{
+			Name:        fn.Name(),
+			Signature:   types.TypeString(sig, qualifier)[len("func"):],
+			PointerOnly: valueSet.Lookup(fn.Pkg(), fn.Name()) == nil,
+			Position:    position(fset, fn.Pos()),
+		}
+		if recv := sig.Recv(); recv != nil {
+			method.Receiver = types.TypeString(recv.Type(), qualifier)
+		}
+		method.Via = embedChain(named, sel.Index(), qualifier)
+		members.Methods = append(members.Methods, method)
+	}
+
+	for _, name := range selectorNames(named) {
+		obj, index, _ := types.LookupFieldOrMethod(named, true, pkg, name)
+		if obj == nil {
+			// Names with several candidates at the same depth collide.
+			if token.IsExported(name) {
+				members.Ambiguous = append(members.Ambiguous, name)
+			}
+			continue
+		}
+		field, ok := obj.(*types.Var)
+		if !ok || !field.IsField() || !visible(field) {
+			continue
+		}
+		members.Fields = append(members.Fields, Field{
+			Name:     field.Name(),
+			Type:     types.TypeString(field.Type(), qualifier),
+			Embedded: field.Embedded(),
+			Tag:      fieldTag(named, index),
+			Via:      embedChain(named, index, qualifier),
+			Position: position(fset, field.Pos()),
+		})
+	}
+
+	sort.SliceStable(members.Fields, func(i, j int) bool {
+		return len(members.Fields[i].Via) < len(members.Fields[j].Via)
+	})
+	return members, nil
+}
+
+// embedChain describes the embedded fields selected by all but the last
+// element of a selection index.
+func embedChain(typ types.Type, index []int, qualifier types.Qualifier) []EmbedStep {
+	var chain []EmbedStep
+	for _, i := range index[:len(index)-1] {
+		st, ok := derefUnderlying(typ).(*types.Struct)
+		if !ok {
+			break
+		}
+		field := st.Field(i)
+		chain = append(chain, EmbedStep{Field: field.Name(), Type: types.TypeString(field.Type(), qualifier)})
+		typ = field.Type()
+	}
+	return chain
+}
+
+// fieldTag returns the struct tag of the field selected by index.
+func fieldTag(typ types.Type, index []int) string {
+	for n, i := range index {
+		st, ok := derefUnderlying(typ).(*types.Struct)
+		if !ok {
+			return ""
+		}
+		if n == len(index)-1 {
+			return st.Tag(i)
+		}
+		typ = st.Field(i).Type()
+	}
+	return ""
+}
+
+// selectorNames returns the names of the fields of typ and of the fields
+// and methods of everything embedded in it, in breadth-first order without
+// duplicates. These are the candidates for promoted selectors.
+func selectorNames(typ types.Type) []string {
+	var names []string
+	seenNames := make(map[string]bool)
+	add := func(name string) {
+		if !seenNames[name] {
+			seenNames[name] = true
+			names = append(names, name)
+		}
+	}
+
+	seenTypes := make(map[types.Type]bool)
+	queue := []types.Type{typ}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		st, ok := derefUnderlying(current).(*types.Struct)
+		if !ok {
+			continue
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			field := st.Field(i)
+			add(field.Name())
+			if !field.Embedded() {
+				continue
+			}
+			embedded := field.Type()
+			if ptr, ok := embedded.(*types.Pointer); ok {
+				embedded = ptr.Elem()
+			}
+			if seenTypes[embedded] {
+				continue
+			}
+			seenTypes[embedded] = true
+			queue = append(queue, embedded)
+
+			methodSet := types.NewMethodSet(embedded)
+			if !types.IsInterface(embedded) {
+				methodSet = types.NewMethodSet(types.NewPointer(embedded))
+			}
+			for j := 0; j < methodSet.Len(); j++ {
+				add(methodSet.At(j).Obj().Name())
+			}
+		}
+	}
+	return names
+}
+
+func derefUnderlying(typ types.Type) types.Type {
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	return typ.Underlying()
+}
+
+func position(fset *token.FileSet, pos token.Pos) string {
+	if !pos.IsValid() {
+		return ""
+	}
+	return fset.Position(pos).String()
+}
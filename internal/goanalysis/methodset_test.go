@@ -0,0 +1,134 @@
+package goanalysis_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/goanalysis"
+)
+
+var methodSetFixture = map[string]string{
+	"modcache/example.com/dep@v1.0.0/go.mod": "module example.com/dep\n",
+	"modcache/example.com/dep@v1.0.0/dep.go": `package dep
+
+type Closer interface {
+	Close() error
+}
+
+type Base struct {
+	ID    int ` + "`json:\"id\"`" + `
+	inner int
+}
+
+func (b Base) Describe() string { return "" }
+
+func (b *Base) SetID(id int) { b.ID = id }
+
+func (b *Base) reset() {}
+
+type Client struct {
+	*Base
+	Closer
+	Name string
+}
+
+func (c *Client) Do() error { return nil }
+
+type Logger struct {
+	Name string
+}
+
+func (Logger) Describe() string { return "" }
+
+type Service struct {
+	Client
+	Logger
+	ID string
+}
+`,
+	"app/go.mod":  "module example.com/app\n\nrequire example.com/dep v1.0.0\n",
+	"app/main.go": "package main\n\nimport _ \"example.com/dep\"\n\nfunc main() {}\n",
+}
+
+func methodsByName(members *goanalysis.TypeMembers) map[string]goanalysis.Method {
+	methods := make(map[string]goanalysis.Method)
+	for _, method := range members.Methods {
+		methods[method.Name] = method
+	}
+	return methods
+}
+
+func TestExpandType(t *testing.T) {
+	loader := setupProject(t, methodSetFixture)
+
+	symbol, err := goanalysis.ResolveSymbol(loader, "example.com/dep.Client")
+	require.NoError(t, err)
+	members, err := goanalysis.ExpandType(loader, symbol, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com/dep.Client", members.Type)
+	methods := methodsByName(members)
+	require.Len(t, methods, 4)
+
+	assert.Equal(t, "() error", methods["Do"].Signature)
+	assert.Equal(t, "*Client", methods["Do"].Receiver)
+	assert.True(t, methods["Do"].PointerOnly)
+	assert.Empty(t, methods["Do"].Via)
+
+	assert.Equal(t, []goanalysis.EmbedStep{{Field: "Base", Type: "*Base"}}, methods["SetID"].Via)
+	assert.False(t, methods["SetID"].PointerOnly, "Promoted through an embedded pointer, so in the method set of T")
+	assert.Equal(t, "Base", methods["Describe"].Receiver)
+	assert.Equal(t, []goanalysis.EmbedStep{{Field: "Closer", Type: "Closer"}}, methods["Close"].Via)
+	assert.NotContains(t, methods, "reset")
+
+	require.Len(t, members.Fields, 4)
+	assert.Equal(t, goanalysis.Field{Name: "Base", Type: "*Base", Embedded: true}, withoutPosition(members.Fields[0]))
+	assert.Equal(t, "Name", members.Fields[2].Name)
+	id := members.Fields[3]
+	assert.Equal(t, "ID", id.Name)
+	assert.Equal(t, `json:"id"`, id.Tag)
+	assert.Equal(t, []goanalysis.EmbedStep{{Field: "Base", Type: "*Base"}}, id.Via)
+	assert.Contains(t, id.Position, "dep.go:8")
+
+	members, err = goanalysis.ExpandType(loader, symbol, true)
+	require.NoError(t, err)
+	assert.Contains(t, methodsByName(members), "reset")
+	assert.Len(t, members.Fields, 5)
+}
+
+func TestExpandTypeShadowingAndAmbiguity(t *testing.T) {
+	loader := setupProject(t, methodSetFixture)
+
+	symbol, err := goanalysis.ResolveSymbol(loader, "example.com/dep.Service")
+	require.NoError(t, err)
+	members, err := goanalysis.ExpandType(loader, symbol, false)
+	require.NoError(t, err)
+
+	methods := methodsByName(members)
+	assert.Equal(t, "Logger", methods["Describe"].Receiver, "The shallower Logger.Describe shadows Client.Base.Describe")
+	assert.Equal(t, []goanalysis.EmbedStep{{Field: "Client", Type: "Client"}, {Field: "Base", Type: "*Base"}}, methods["SetID"].Via)
+	assert.True(t, methods["Do"].PointerOnly)
+
+	for _, field := range members.Fields {
+		if field.Name == "ID" {
+			assert.Equal(t, "string", field.Type, "The outer ID shadows Client.Base.ID")
+			assert.Empty(t, field.Via)
+		}
+	}
+	assert.Equal(t, []string{"Name"}, members.Ambiguous, "Client.Name and Logger.Name are at the same depth")
+}
+
+func TestExpandTypeNotAType(t *testing.T) {
+	loader := setupProject(t, methodSetFixture)
+
+	symbol, err := goanalysis.ResolveSymbol(loader, "example.com/dep.Client.Do")
+	require.NoError(t, err)
+	_, err = goanalysis.ExpandType(loader, symbol, false)
+	assert.ErrorContains(t, err, "is a method, not a type")
+}
+
+func withoutPosition(field goanalysis.Field) goanalysis.Field {
+	field.Position = ""
+	return field
+}
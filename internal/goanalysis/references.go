@@ -0,0 +1,235 @@
+package goanalysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/generated"
+	"github.com/svetlyi/mcp-local-context/internal/goload"
+)
+
+// Reference is a use of a symbol inside the project.
+type Reference struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Package  string `json:"package"`
+	Function string `json:"function,omitempty"`
+	Snippet  string `json:"snippet"`
+	// Generated marks references in generated files; Proto names the
+	// .proto file protobuf-generated code comes from.
+	Generated bool   `json:"generated,omitempty"`
+	Proto     string `json:"proto,omitempty"`
+}
+
+// ReferencesResult lists the references found and any packages that could
+// not be fully type-checked, which may hide references.
+type ReferencesResult struct {
+	Symbol     string      `json:"symbol"`
+	Kind       string      `json:"kind"`
+	References []Reference `json:"references"`
+	// Omitted counts the references dropped by the generated file mode.
+	Omitted  int      `json:"omitted,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// FindReferences returns every reference to symbol in the packages of the
+// project's main module. Test files are included when includeTests is set,
+// and references in generated files are filtered according to mode.
+func FindReferences(loader *goload.Loader, symbol *Symbol, includeTests bool, mode generated.Mode) (*ReferencesResult, error) {
+	result := &ReferencesResult{
+		Symbol:     symbol.Name(),
+		Kind:       symbol.Kind(),
+		References: make([]Reference, 0),
+	}
+
+	refs := newReferenceBuilder(loader)
+	warnings, err := walkProjectUses(loader, includeTests, func(pkg *goload.Package, file *ast.File, ident *ast.Ident, obj types.Object) {
+		if sameObject(obj, symbol.Object) {
+			result.References = append(result.References, refs.build(pkg, file, ident))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Warnings = warnings
+
+	sortReferences(result.References)
+	result.References, result.Omitted = generated.Apply(mode, result.References, func(ref Reference) bool {
+		return ref.Generated
+	})
+	return result, nil
+}
+
+// walkProjectUses calls fn for every identifier in the main module's
+// packages that refers to an object. It returns a warning for every package
+// that could not be fully type-checked, as its uses may be incomplete.
+func walkProjectUses(loader *goload.Loader, includeTests bool, fn func(pkg *goload.Package, file *ast.File, ident *ast.Ident, obj types.Object)) ([]string, error) {
+	dirs, err := loader.MainPackageDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	for _, dir := range dirs {
+		pkg, err := loader.LoadDir(dir)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		pkgs := []*goload.Package{pkg}
+		if includeTests {
+			internal, external, err := loader.LoadTests(pkg)
+			if err != nil {
+				warnings = append(warnings, err.Error())
+			}
+			// The in-package test variant covers the package's own files.
+			if internal != nil {
+				pkgs[0] = internal
+			}
+			if external != nil {
+				pkgs = append(pkgs, external)
+			}
+		}
+
+		for _, p := range pkgs {
+			if len(p.Errors) > 0 {
+				warnings = append(warnings, fmt.Sprintf("%s: %v", p.Path, p.Errors[0]))
+			}
+			for _, file := range p.Files {
+				ast.Inspect(file, func(n ast.Node) bool {
+					ident, ok := n.(*ast.Ident)
+					if !ok {
+						return true
+					}
+					if obj := p.Info.Uses[ident]; obj != nil {
+						fn(p, file, ident, obj)
+					}
+					return true
+				})
+			}
+		}
+	}
+	return warnings, nil
+}
+
+// referenceBuilder turns identifiers into references relative to the
+// project directory.
+type referenceBuilder struct {
+	loader     *goload.Loader
+	lines      *lineCache
+	classifier *generated.Classifier
+	projectDir string
+}
+
+func newReferenceBuilder(loader *goload.Loader) *referenceBuilder {
+	return &referenceBuilder{
+		loader:     loader,
+		lines:      newLineCache(),
+		classifier: generated.NewClassifier(),
+		projectDir: loader.Project().Main().Dir,
+	}
+}
+
+func (b *referenceBuilder) build(pkg *goload.Package, file *ast.File, ident *ast.Ident) Reference {
+	pos := b.loader.Fset().Position(ident.Pos())
+	ref := Reference{
+		File:     pos.Filename,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Package:  pkg.Path,
+		Function: enclosingFunction(file, ident),
+		Snippet:  b.lines.line(pos.Filename, pos.Line),
+	}
+	if info, err := b.classifier.Classify(pos.Filename, b.projectDir); err == nil {
+		ref.Generated = info.Generated
+		ref.Proto = b.relative(info.Proto)
+	}
+	ref.File = b.relative(ref.File)
+	return ref
+}
+
+// relative returns path relative to the project directory when it is
+// below it.
+func (b *referenceBuilder) relative(path string) string {
+	if path == "" {
+		return ""
+	}
+	if rel, err := filepath.Rel(b.projectDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+func sortReferences(refs []Reference) {
+	sort.Slice(refs, func(i, j int) bool {
+		a, b := refs[i], refs[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// enclosingFunction returns the name of the top-level function or method
+// declaring node, e.g. "Run" or "(*Server).Run". It is empty at package scope.
+func enclosingFunction(file *ast.File, node ast.Node) string {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || node.Pos() < fn.Pos() || node.End() > fn.End() {
+			continue
+		}
+		if fn.Recv == nil || len(fn.Recv.List) == 0 {
+			return fn.Name.Name
+		}
+		return fmt.Sprintf("(%s).%s", receiverString(fn.Recv.List[0].Type), fn.Name.Name)
+	}
+	return ""
+}
+
+func receiverString(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return "*" + receiverString(expr.X)
+	case *ast.IndexExpr:
+		return receiverString(expr.X)
+	case *ast.IndexListExpr:
+		return receiverString(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	default:
+		return "?"
+	}
+}
+
+// lineCache reads source files once to extract snippets.
+type lineCache struct {
+	files map[string][]string
+}
+
+func newLineCache() *lineCache {
+	return &lineCache{files: make(map[string][]string)}
+}
+
+func (c *lineCache) line(filename string, line int) string {
+	lines, ok := c.files[filename]
+	if !ok {
+		data, err := os.ReadFile(filename)
+		if err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+		c.files[filename] = lines
+	}
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[line-1])
+}
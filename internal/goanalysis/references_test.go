@@ -0,0 +1,169 @@
+package goanalysis_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/generated"
+	"github.com/svetlyi/mcp-local-context/internal/goanalysis"
+	"github.com/svetlyi/mcp-local-context/internal/goenv"
+	"github.com/svetlyi/mcp-local-context/internal/goload"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+// setupProject writes the given files below a temporary directory holding a
+// project in "app" and a module cache in "modcache", and returns a loader
+// for the project.
+func setupProject(t *testing.T, files map[string]string) *goload.Loader {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, files)
+
+	env, err := goenv.Detect()
+	require.NoError(t, err)
+
+	project, err := gomod.LoadProject(filepath.Join(tmpDir, "app"), filepath.Join(tmpDir, "modcache"))
+	require.NoError(t, err)
+	return goload.New(project, env.GOROOT, goload.BuildContext{})
+}
+
+var referencesFixture = map[string]string{
+	"modcache/example.com/dep@v1.0.0/go.mod": "module example.com/dep\n",
+	"modcache/example.com/dep@v1.0.0/dep.go": `package dep
+
+type Client struct {
+	Name string
+}
+
+func New() *Client { return &Client{} }
+
+func (c *Client) Do() error { return nil }
+`,
+	"app/go.mod": "module example.com/app\n\nrequire example.com/dep v1.0.0\n",
+	"app/main.go": `package main
+
+import "example.com/dep"
+
+var defaultClient = dep.New()
+
+func main() {
+	c := dep.New()
+	_ = c.Do()
+}
+`,
+	"app/internal/runner/runner.go": `package runner
+
+import "example.com/dep"
+
+type Runner struct{}
+
+func (r *Runner) Run(c *dep.Client) {
+	c.Do()
+	_ = c.Name
+}
+`,
+	"app/internal/runner/runner_test.go": `package runner
+
+import "example.com/dep"
+
+func helper() { dep.New().Do() }
+`,
+}
+
+func TestFindReferences(t *testing.T) {
+	loader := setupProject(t, referencesFixture)
+
+	symbol, err := goanalysis.ResolveSymbol(loader, "example.com/dep.Client.Do")
+	require.NoError(t, err)
+	assert.Equal(t, "method", symbol.Kind())
+
+	result, err := goanalysis.FindReferences(loader, symbol, false, generated.Include)
+	require.NoError(t, err)
+	require.Len(t, result.References, 2)
+
+	ref := result.References[0]
+	assert.Equal(t, filepath.Join("internal", "runner", "runner.go"), ref.File)
+	assert.Equal(t, 8, ref.Line)
+	assert.Equal(t, "(*Runner).Run", ref.Function)
+	assert.Equal(t, "c.Do()", ref.Snippet)
+
+	ref = result.References[1]
+	assert.Equal(t, "main.go", ref.File)
+	assert.Equal(t, "main", ref.Function)
+}
+
+func TestFindReferencesIncludeTests(t *testing.T) {
+	loader := setupProject(t, referencesFixture)
+
+	symbol, err := goanalysis.ResolveSymbol(loader, "example.com/dep.New")
+	require.NoError(t, err)
+
+	result, err := goanalysis.FindReferences(loader, symbol, true, generated.Include)
+	require.NoError(t, err)
+	require.Len(t, result.References, 3)
+
+	assert.Equal(t, filepath.Join("internal", "runner", "runner_test.go"), result.References[0].File)
+	assert.Equal(t, "helper", result.References[0].Function)
+	assert.Equal(t, "", result.References[1].Function, "Package-level initializer has no enclosing function")
+	assert.Equal(t, "main", result.References[2].Function)
+}
+
+func TestFindReferencesGenerated(t *testing.T) {
+	files := map[string]string{
+		"app/api/v1/api.pb.go": `// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: proto/api/v1/api.proto
+
+package api
+
+import "example.com/dep"
+
+func NewClient() *dep.Client { return dep.New() }
+`,
+		"app/proto/api/v1/api.proto": "syntax = \"proto3\";\n",
+	}
+	for name, content := range referencesFixture {
+		files[name] = content
+	}
+	loader := setupProject(t, files)
+
+	symbol, err := goanalysis.ResolveSymbol(loader, "example.com/dep.New")
+	require.NoError(t, err)
+
+	result, err := goanalysis.FindReferences(loader, symbol, false, generated.Include)
+	require.NoError(t, err)
+	require.Len(t, result.References, 3)
+	ref := result.References[0]
+	assert.Equal(t, filepath.Join("api", "v1", "api.pb.go"), ref.File)
+	assert.True(t, ref.Generated)
+	assert.Equal(t, filepath.Join("proto", "api", "v1", "api.proto"), ref.Proto)
+	assert.False(t, result.References[1].Generated)
+
+	result, err = goanalysis.FindReferences(loader, symbol, false, generated.Last)
+	require.NoError(t, err)
+	require.Len(t, result.References, 3)
+	assert.Equal(t, "main.go", result.References[0].File)
+	assert.True(t, result.References[2].Generated)
+
+	result, err = goanalysis.FindReferences(loader, symbol, false, generated.Exclude)
+	require.NoError(t, err)
+	assert.Len(t, result.References, 2)
+	assert.Equal(t, 1, result.Omitted)
+
+	result, err = goanalysis.FindReferences(loader, symbol, false, generated.Only)
+	require.NoError(t, err)
+	require.Len(t, result.References, 1)
+	assert.True(t, result.References[0].Generated)
+	assert.Equal(t, 2, result.Omitted)
+}
+
+func TestResolveSymbolNotFound(t *testing.T) {
+	loader := setupProject(t, referencesFixture)
+
+	_, err := goanalysis.ResolveSymbol(loader, "example.com/dep.Missing")
+	assert.Error(t, err)
+
+	_, err = goanalysis.ResolveSymbol(loader, "example.com/dep.Client.Missing")
+	assert.Error(t, err)
+}
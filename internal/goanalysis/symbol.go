@@ -0,0 +1,121 @@
+package goanalysis
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/goload"
+)
+
+// Symbol is a package-level object, or a field or method of a package-level
+// type, resolved from a fully qualified name.
+type Symbol struct {
+	Package *goload.Package
+	// Type is set when the symbol is a field or method of a named type.
+	Type   *types.TypeName
+	Object types.Object
+}
+
+// Name returns the fully qualified name of the symbol.
+func (s *Symbol) Name() string {
+	if s.Type != nil {
+		return fmt.Sprintf("%s.%s.%s", s.Package.Path, s.Type.Name(), s.Object.Name())
+	}
+	return fmt.Sprintf("%s.%s", s.Package.Path, s.Object.Name())
+}
+
+// Kind describes the symbol, e.g. "func", "method", "type", "field".
+func (s *Symbol) Kind() string {
+	return objectKind(s.Object)
+}
+
+// ResolveSymbol resolves a fully qualified symbol such as
+// "github.com/nats-io/nats.go.Conn.Subscribe" or "net/http.Get".
+// Because the last element of an import path may itself contain dots, every
+// split point after the last slash is tried until one names an existing
+// package and object.
+func ResolveSymbol(loader *goload.Loader, qualified string) (*Symbol, error) {
+	lastSlash := strings.LastIndex(qualified, "/")
+	var loadErr error
+	for i := lastSlash + 1; i < len(qualified); i++ {
+		if qualified[i] != '.' {
+			continue
+		}
+		pkgPath, rest := qualified[:i], qualified[i+1:]
+		pkg, err := loader.Load(pkgPath)
+		if err != nil {
+			if loadErr == nil {
+				loadErr = err
+			}
+			continue
+		}
+		if symbol := lookupSymbol(pkg, rest); symbol != nil {
+			return symbol, nil
+		}
+	}
+	if loadErr != nil {
+		return nil, fmt.Errorf("symbol %s not found: %w", qualified, loadErr)
+	}
+	return nil, fmt.Errorf("symbol %s not found", qualified)
+}
+
+func lookupSymbol(pkg *goload.Package, name string) *Symbol {
+	if pkg.Types == nil {
+		return nil
+	}
+	typeName, member, hasMember := strings.Cut(name, ".")
+	obj := pkg.Types.Scope().Lookup(typeName)
+	if obj == nil {
+		return nil
+	}
+	if !hasMember {
+		return &Symbol{Package: pkg, Object: obj}
+	}
+
+	tn, ok := obj.(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	memberObj, _, _ := types.LookupFieldOrMethod(tn.Type(), true, pkg.Types, member)
+	if memberObj == nil {
+		return nil
+	}
+	return &Symbol{Package: pkg, Type: tn, Object: memberObj}
+}
+
+func objectKind(obj types.Object) string {
+	switch obj := obj.(type) {
+	case *types.Func:
+		if sig, ok := obj.Type().(*types.Signature); ok && sig.Recv() != nil {
+			return "method"
+		}
+		return "func"
+	case *types.Var:
+		if obj.IsField() {
+			return "field"
+		}
+		return "var"
+	case *types.Const:
+		return "const"
+	case *types.TypeName:
+		return "type"
+	default:
+		return "object"
+	}
+}
+
+// sameObject reports whether obj refers to target, looking through
+// instantiations of generic functions, methods and fields.
+func sameObject(obj, target types.Object) bool {
+	if obj == target {
+		return true
+	}
+	switch obj := obj.(type) {
+	case *types.Func:
+		return obj.Origin() == target
+	case *types.Var:
+		return obj.Origin() == target
+	}
+	return false
+}
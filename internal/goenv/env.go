@@ -0,0 +1,197 @@
+package goenv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// Source tells where the value of a variable came from.
+type Source string
+
+const (
+	SourceConfig  Source = "config"
+	SourceEnv     Source = "environment"
+	SourceEnvFile Source = "go/env file"
+	SourceDefault Source = "default"
+)
+
+// Variables lists the variables Detect resolves, in resolution order.
+var Variables = []string{"GOROOT", "GOPATH", "GOMODCACHE", "GOFLAGS", "GOPRIVATE"}
+
+// Env holds the Go environment values needed to locate sources on disk.
+type Env struct {
+	GOROOT     string `json:"goroot"`
+	GOPATH     string `json:"gopath"`
+	GOMODCACHE string `json:"gomodcache"`
+	GOFLAGS    string `json:"goflags,omitempty"`
+	GOPRIVATE  string `json:"goprivate,omitempty"`
+	// EnvFile is the go/env file consulted, written by `go env -w`. It is
+	// empty when GOENV=off or the user config directory is unknown.
+	EnvFile string `json:"env_file,omitempty"`
+	// Sources maps each variable to where its value came from.
+	Sources map[string]Source `json:"sources"`
+}
+
+type detectOptions struct {
+	overrides map[string]string
+}
+
+// Option configures Detect.
+type Option func(*detectOptions)
+
+// WithOverrides sets variables that take precedence over the environment,
+// such as the go_env section of the configuration file.
+func WithOverrides(overrides map[string]string) Option {
+	return func(o *detectOptions) {
+		o.overrides = overrides
+	}
+}
+
+// Detect resolves the Go environment without running the go binary. Like
+// the go command, it reads the process environment first, then the go/env
+// file under os.UserConfigDir, then falls back to the defaults. Overrides
+// take precedence over all of them.
+func Detect(opts ...Option) (*Env, error) {
+	var o detectOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	for name := range o.overrides {
+		if !slices.Contains(Variables, name) {
+			return nil, fmt.Errorf("unsupported Go environment override %q: expected one of %s", name, strings.Join(Variables, ", "))
+		}
+	}
+
+	env := &Env{Sources: make(map[string]Source)}
+	envFile, err := readEnvFile(&env.EnvFile)
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := func(name string) string {
+		if value := o.overrides[name]; value != "" {
+			env.Sources[name] = SourceConfig
+			return value
+		}
+		if value := os.Getenv(name); value != "" {
+			env.Sources[name] = SourceEnv
+			return value
+		}
+		if value := envFile[name]; value != "" {
+			env.Sources[name] = SourceEnvFile
+			return value
+		}
+		env.Sources[name] = SourceDefault
+		return ""
+	}
+
+	if env.GOROOT = lookup("GOROOT"); env.GOROOT == "" {
+		env.GOROOT = defaultGOROOT()
+	}
+
+	if env.GOPATH = lookup("GOPATH"); env.GOPATH == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+		env.GOPATH = filepath.Join(homeDir, "go")
+	}
+
+	if env.GOMODCACHE = lookup("GOMODCACHE"); env.GOMODCACHE == "" {
+		env.GOMODCACHE = filepath.Join(filepath.SplitList(env.GOPATH)[0], "pkg", "mod")
+	}
+
+	env.GOFLAGS = lookup("GOFLAGS")
+	env.GOPRIVATE = lookup("GOPRIVATE")
+
+	return env, nil
+}
+
+// readEnvFile reads the go/env file the way the go command locates it: the
+// GOENV variable names it, "off" disables it, and it defaults to go/env
+// under the user config directory. A missing file is not an error. The
+// path consulted is stored in path.
+func readEnvFile(path *string) (map[string]string, error) {
+	file := os.Getenv("GOENV")
+	if file == "off" {
+		return nil, nil
+	}
+	if file == "" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, nil
+		}
+		file = filepath.Join(configDir, "go", "env")
+	}
+	*path = file
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read Go environment file: %w", err)
+	}
+	return parseEnvFile(data), nil
+}
+
+// parseEnvFile parses the NAME=VALUE lines of a go/env file. Values are
+// taken verbatim, as the go command does.
+func parseEnvFile(data []byte) map[string]string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		values[strings.TrimSpace(name)] = value
+	}
+	return values
+}
+
+// defaultGOROOT derives GOROOT from the go binary on PATH, which needs no
+// permission to run it, and falls back to the GOROOT this program was
+// built with.
+func defaultGOROOT() string {
+	if goBin, err := exec.LookPath("go"); err == nil {
+		if resolved, err := filepath.EvalSymlinks(goBin); err == nil {
+			root := filepath.Dir(filepath.Dir(resolved))
+			if info, err := os.Stat(filepath.Join(root, "src", "runtime")); err == nil && info.IsDir() {
+				return root
+			}
+		}
+	}
+	return runtime.GOROOT()
+}
+
+// ModFlag returns the value of the -mod flag set in GOFLAGS, or "".
+func (e *Env) ModFlag() string {
+	var value string
+	for _, flag := range strings.Fields(e.GOFLAGS) {
+		flag = strings.TrimPrefix(strings.TrimPrefix(flag, "-"), "-")
+		if v, ok := strings.CutPrefix(flag, "mod="); ok {
+			value = v
+		}
+	}
+	return value
+}
+
+// IsPrivate reports whether GOPRIVATE matches a module path, meaning the
+// module is fetched directly from its repository rather than a proxy.
+func (e *Env) IsPrivate(modulePath string) bool {
+	return module.MatchPrefixPatterns(e.GOPRIVATE, modulePath)
+}
@@ -0,0 +1,85 @@
+package goenv_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/goenv"
+)
+
+// clearGoEnv unsets the variables Detect reads for the duration of a test.
+func clearGoEnv(t *testing.T) {
+	for _, name := range append([]string{"GOENV"}, goenv.Variables...) {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+func TestDetectPrecedence(t *testing.T) {
+	clearGoEnv(t)
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, "env")
+	require.NoError(t, os.WriteFile(envFile, []byte("# written by go env -w\nGOPATH=/file/gopath\nGOPRIVATE=example.com/private,*.corp.example\nGOFLAGS=-mod=mod\n"), 0644))
+	t.Setenv("GOENV", envFile)
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("GOFLAGS", "-mod=vendor")
+	t.Setenv("GOROOT", "/env/goroot")
+
+	env, err := goenv.Detect(goenv.WithOverrides(map[string]string{"GOROOT": "/config/goroot"}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "/config/goroot", env.GOROOT)
+	assert.Equal(t, "-mod=vendor", env.GOFLAGS)
+	assert.Equal(t, "vendor", env.ModFlag())
+	assert.Equal(t, "/file/gopath", env.GOPATH)
+	assert.Equal(t, filepath.Join("/file/gopath", "pkg", "mod"), env.GOMODCACHE)
+	assert.Equal(t, envFile, env.EnvFile)
+	assert.Equal(t, map[string]goenv.Source{
+		"GOROOT":     goenv.SourceConfig,
+		"GOPATH":     goenv.SourceEnvFile,
+		"GOMODCACHE": goenv.SourceDefault,
+		"GOFLAGS":    goenv.SourceEnv,
+		"GOPRIVATE":  goenv.SourceEnvFile,
+	}, env.Sources)
+
+	assert.True(t, env.IsPrivate("example.com/private/repo"))
+	assert.True(t, env.IsPrivate("git.corp.example/team/mod"))
+	assert.False(t, env.IsPrivate("example.com/public"))
+}
+
+func TestDetectDefaults(t *testing.T) {
+	clearGoEnv(t)
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+
+	env, err := goenv.Detect()
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, env.GOROOT)
+	assert.Equal(t, filepath.Join(tmpDir, "go"), env.GOPATH)
+	assert.Equal(t, filepath.Join(tmpDir, "go", "pkg", "mod"), env.GOMODCACHE)
+	assert.Empty(t, env.GOFLAGS)
+	assert.Empty(t, env.GOPRIVATE)
+	assert.Equal(t, goenv.SourceDefault, env.Sources["GOPATH"])
+}
+
+func TestDetectEnvFileOff(t *testing.T) {
+	clearGoEnv(t)
+	t.Setenv("GOENV", "off")
+	t.Setenv("GOMODCACHE", "/env/modcache")
+
+	env, err := goenv.Detect()
+	require.NoError(t, err)
+	assert.Empty(t, env.EnvFile)
+	assert.Equal(t, "/env/modcache", env.GOMODCACHE)
+	assert.Equal(t, goenv.SourceEnv, env.Sources["GOMODCACHE"])
+}
+
+func TestDetectUnsupportedOverride(t *testing.T) {
+	_, err := goenv.Detect(goenv.WithOverrides(map[string]string{"GOPROXY": "off"}))
+	assert.ErrorContains(t, err, `unsupported Go environment override "GOPROXY"`)
+}
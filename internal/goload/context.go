@@ -0,0 +1,97 @@
+package goload
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// knownOS and knownArch mirror the go command's list of recognized GOOS and
+// GOARCH values, including the ones only used in file name suffixes.
+var (
+	knownOS = []string{
+		"aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios", "js",
+		"linux", "nacl", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows", "zos",
+	}
+	knownArch = []string{
+		"386", "amd64", "amd64p32", "arm", "armbe", "arm64", "arm64be", "loong64", "mips",
+		"mipsle", "mips64", "mips64le", "mips64p32", "mips64p32le", "ppc", "ppc64", "ppc64le",
+		"riscv", "riscv64", "s390", "s390x", "sparc", "sparc64", "wasm",
+	}
+)
+
+// BuildContext selects which files take part in a build, the way GOOS,
+// GOARCH, CGO_ENABLED and -tags do for the go command. Empty fields default
+// to the host.
+type BuildContext struct {
+	GOOS       string   `json:"goos,omitempty"`
+	GOARCH     string   `json:"goarch,omitempty"`
+	CgoEnabled *bool    `json:"cgo_enabled,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// Override returns bc with every non-empty field of other applied.
+func (bc BuildContext) Override(other BuildContext) BuildContext {
+	if other.GOOS != "" {
+		bc.GOOS = other.GOOS
+	}
+	if other.GOARCH != "" {
+		bc.GOARCH = other.GOARCH
+	}
+	if other.CgoEnabled != nil {
+		bc.CgoEnabled = other.CgoEnabled
+	}
+	if other.Tags != nil {
+		bc.Tags = other.Tags
+	}
+	return bc
+}
+
+// Validate reports unknown GOOS or GOARCH values.
+func (bc BuildContext) Validate() error {
+	if bc.GOOS != "" && !slices.Contains(knownOS, bc.GOOS) {
+		return fmt.Errorf("unknown GOOS %q, expected one of: %s", bc.GOOS, strings.Join(knownOS, ", "))
+	}
+	if bc.GOARCH != "" && !slices.Contains(knownArch, bc.GOARCH) {
+		return fmt.Errorf("unknown GOARCH %q, expected one of: %s", bc.GOARCH, strings.Join(knownArch, ", "))
+	}
+	return nil
+}
+
+// String formats the context as "goos/goarch" followed by whether cgo is
+// enabled and the tags.
+func (bc BuildContext) String() string {
+	ctxt := bc.buildContext("")
+	s := ctxt.GOOS + "/" + ctxt.GOARCH
+	if !ctxt.CgoEnabled {
+		s += " cgo=off"
+	}
+	if len(ctxt.BuildTags) > 0 {
+		s += " tags=" + strings.Join(ctxt.BuildTags, ",")
+	}
+	return s
+}
+
+func (bc BuildContext) buildContext(goroot string) build.Context {
+	ctxt := build.Default
+	ctxt.GOROOT = goroot
+	if bc.GOOS != "" {
+		ctxt.GOOS = bc.GOOS
+	}
+	if bc.GOARCH != "" {
+		ctxt.GOARCH = bc.GOARCH
+	}
+	switch {
+	case bc.CgoEnabled != nil:
+		ctxt.CgoEnabled = *bc.CgoEnabled
+	case ctxt.GOOS != runtime.GOOS || ctxt.GOARCH != runtime.GOARCH:
+		// Like the go command, cgo is off when cross-compiling unless
+		// CGO_ENABLED turns it on.
+		ctxt.CgoEnabled = os.Getenv("CGO_ENABLED") == "1"
+	}
+	ctxt.BuildTags = bc.Tags
+	return ctxt
+}
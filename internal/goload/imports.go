@@ -0,0 +1,310 @@
+package goload
+
+import (
+	"errors"
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+	"golang.org/x/mod/module"
+)
+
+// maxNearestPackages caps the existing packages suggested for a missing one.
+const maxNearestPackages = 5
+
+// ImportStatus is the outcome of checking an import path.
+type ImportStatus string
+
+const (
+	// ImportOK means the package exists in the module the project resolves.
+	ImportOK ImportStatus = "ok"
+	// ImportWrongMajor means the path names another major version of a
+	// required module than the one the project requires.
+	ImportWrongMajor ImportStatus = "wrong_major_version"
+	// ImportInternal means the package exists but is an internal package
+	// the project is not allowed to import.
+	ImportInternal ImportStatus = "internal_package"
+	// ImportMissingPackage means the providing module has no such package.
+	ImportMissingPackage ImportStatus = "missing_package"
+	// ImportNotDependency means no required module provides the path.
+	ImportNotDependency ImportStatus = "not_a_dependency"
+	// ImportNotDownloaded means the providing module is not in the module
+	// cache, so the package could not be checked.
+	ImportNotDownloaded ImportStatus = "not_downloaded"
+	// ImportInvalid means the path is not a valid import path.
+	ImportInvalid ImportStatus = "invalid"
+)
+
+// ImportCheck is the result of checking one import path.
+type ImportCheck struct {
+	ImportPath string       `json:"import_path"`
+	Status     ImportStatus `json:"status"`
+	// Module and Version identify the module providing the package, or the
+	// module of the wrong major version.
+	Module   string `json:"module,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Standard bool   `json:"standard,omitempty"`
+	// Suggestion is the import path to use instead, for wrong major versions.
+	Suggestion string `json:"suggestion,omitempty"`
+	// Nearest lists existing packages close to a missing one.
+	Nearest []string `json:"nearest,omitempty"`
+	Message string   `json:"message,omitempty"`
+}
+
+// CheckImport checks that importPath names a package the project can import
+// at the module versions it resolves.
+func (l *Loader) CheckImport(importPath string) ImportCheck {
+	check := ImportCheck{ImportPath: importPath}
+	if err := module.CheckImportPath(importPath); err != nil {
+		check.Status = ImportInvalid
+		check.Message = err.Error()
+		return check
+	}
+
+	mod := l.project.ModuleForImport(importPath)
+	if mod == nil && isStandardImportPath(importPath) {
+		check.Standard = true
+		l.checkPackage(&check, "std", filepath.Join(l.goroot, "src"), importPath)
+		l.checkInternal(&check)
+		return check
+	}
+	if mod == nil {
+		if major := l.otherMajor(importPath); major != nil {
+			check.Status = ImportWrongMajor
+			check.Module = major.mod.Path
+			check.Version = major.mod.Version
+			check.Suggestion = major.suggestion
+			check.Message = fmt.Sprintf("the project requires %s@%s; import %s instead", major.mod.Path, major.mod.Version, major.suggestion)
+			return check
+		}
+		check.Status = ImportNotDependency
+		check.Message = fmt.Sprintf("no module required by %s provides %s; add it with go get", l.project.Path, importPath)
+		return check
+	}
+
+	check.Module = mod.Path
+	check.Version = mod.Version
+	if !isDir(mod.Dir) {
+		check.Status = ImportNotDownloaded
+		check.Message = fmt.Sprintf("sources of %s@%s not found in %s; run `go mod download %s@%s`", mod.Path, mod.Version, mod.Dir, mod.Path, mod.Version)
+		return check
+	}
+	l.checkPackage(&check, mod.Path, mod.Dir, importPath)
+	if check.Status == ImportMissingPackage {
+		// A path such as example.com/foo/v2/bar falls into the v0/v1 module
+		// example.com/foo when v2 is not required.
+		prefix, _, _ := module.SplitPathVersion(mod.Path)
+		if rest, ok := trimMajor(importPath, prefix); ok && mod.Path+rest != importPath {
+			check.Status = ImportWrongMajor
+			check.Suggestion = mod.Path + rest
+			check.Nearest = nil
+			check.Message = fmt.Sprintf("the project requires %s@%s; import %s instead", mod.Path, mod.Version, check.Suggestion)
+		}
+	}
+	l.checkInternal(&check)
+	return check
+}
+
+// checkInternal reports an existing package below an internal directory
+// as not importable unless the project lives below the internal
+// directory's parent, as the go command requires.
+func (l *Loader) checkInternal(check *ImportCheck) {
+	if check.Status != ImportOK {
+		return
+	}
+	parent, ok := internalParent(check.ImportPath)
+	if !ok {
+		return
+	}
+	if !check.Standard && (l.project.Path == parent || strings.HasPrefix(l.project.Path, parent+"/")) {
+		return
+	}
+	check.Status = ImportInternal
+	if check.Standard {
+		check.Message = fmt.Sprintf("%s is internal to the standard library and cannot be imported", check.ImportPath)
+	} else {
+		check.Message = fmt.Sprintf("%s is internal to %s and cannot be imported from %s", check.ImportPath, parent, l.project.Path)
+	}
+}
+
+// internalParent returns the path above the last internal element of
+// importPath, and whether it has one.
+func internalParent(importPath string) (string, bool) {
+	elems := strings.Split(importPath, "/")
+	for i := len(elems) - 1; i >= 0; i-- {
+		if elems[i] == "internal" {
+			return strings.Join(elems[:i], "/"), true
+		}
+	}
+	return "", false
+}
+
+// checkPackage sets the status of an import path provided by the module
+// (or the standard library) with the given path prefix and root directory.
+func (l *Loader) checkPackage(check *ImportCheck, modPath, root, importPath string) {
+	rel := importPath
+	if modPath != "std" {
+		rel = strings.TrimPrefix(strings.TrimPrefix(importPath, modPath), "/")
+	}
+	dir := filepath.Join(root, filepath.FromSlash(rel))
+
+	if isDir(dir) {
+		// Directories with only test files are not importable.
+		pkg, err := l.ctxt.ImportDir(dir, 0)
+		var noGo *build.NoGoError
+		switch {
+		case err == nil && len(pkg.GoFiles)+len(pkg.CgoFiles) > 0:
+			check.Status = ImportOK
+			return
+		case (err == nil || errors.As(err, &noGo)) && hasGoFiles(dir):
+			check.Status = ImportOK
+			check.Message = fmt.Sprintf("every file is excluded by build constraints for %s", l.bc)
+			return
+		}
+	}
+
+	check.Status = ImportMissingPackage
+	if check.Standard {
+		check.Message = fmt.Sprintf("package %s is not in the standard library", importPath)
+	} else {
+		check.Message = fmt.Sprintf("package %s not found in %s@%s", importPath, check.Module, check.Version)
+	}
+	check.Nearest = l.nearestPackages(modPath, root, rel)
+}
+
+// nearestPackages suggests the packages below the deepest existing
+// directory on the way to the missing package rel, closest first.
+func (l *Loader) nearestPackages(modPath, root, rel string) []string {
+	base := rel
+	for base != "." && base != "" && !isDir(filepath.Join(root, filepath.FromSlash(base))) {
+		base = filepath.ToSlash(filepath.Dir(base))
+	}
+	if base == "." {
+		base = ""
+	}
+	dirs, err := l.packageDirs(filepath.Join(root, filepath.FromSlash(base)))
+	if err != nil {
+		return nil
+	}
+
+	type candidate struct {
+		path     string
+		distance int
+	}
+	var candidates []candidate
+	for _, dir := range dirs {
+		if !hasGoFiles(dir) {
+			continue
+		}
+		candidateRel, err := filepath.Rel(root, dir)
+		if err != nil {
+			continue
+		}
+		candidateRel = filepath.ToSlash(candidateRel)
+		path := candidateRel
+		if modPath != "std" {
+			path = modPath
+			if candidateRel != "." {
+				path += "/" + candidateRel
+			}
+		}
+		candidates = append(candidates, candidate{path: path, distance: editDistance(rel, candidateRel)})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	nearest := make([]string, 0, maxNearestPackages)
+	for _, c := range candidates[:min(len(candidates), maxNearestPackages)] {
+		nearest = append(nearest, c.path)
+	}
+	return nearest
+}
+
+type majorMatch struct {
+	mod        *gomod.Module
+	suggestion string
+}
+
+// otherMajor finds a required module whose path differs from importPath
+// only in its major version suffix, and the import path to use with it.
+func (l *Loader) otherMajor(importPath string) *majorMatch {
+	var best *majorMatch
+	var bestPrefix string
+	for _, mod := range l.project.Modules() {
+		prefix, _, ok := module.SplitPathVersion(mod.Path)
+		if !ok || len(prefix) <= len(bestPrefix) {
+			continue
+		}
+		rest, ok := trimMajor(importPath, prefix)
+		if !ok {
+			continue
+		}
+		best = &majorMatch{mod: mod, suggestion: mod.Path + rest}
+		bestPrefix = prefix
+	}
+	return best
+}
+
+// trimMajor strips prefix and an optional major version suffix (/vN or, for
+// gopkg.in, .vN) from importPath, returning the package path below it.
+func trimMajor(importPath, prefix string) (string, bool) {
+	rest, ok := strings.CutPrefix(importPath, prefix)
+	if !ok {
+		return "", false
+	}
+	if len(rest) > 2 && (strings.HasPrefix(rest, "/v") || strings.HasPrefix(rest, ".v")) {
+		end := 2
+		for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+			end++
+		}
+		if end > 2 && (end == len(rest) || rest[end] == '/') {
+			rest = rest[end:]
+		}
+	}
+	if rest != "" && rest[0] != '/' {
+		return "", false
+	}
+	return rest, true
+}
+
+// hasGoFiles reports whether dir holds non-test Go files, whatever their
+// build constraints.
+func hasGoFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+			return true
+		}
+	}
+	return false
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,97 @@
+package goload_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/goenv"
+	"github.com/svetlyi/mcp-local-context/internal/goload"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func TestCheckImport(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"modcache/example.com/bar/v3@v3.1.0/go.mod":             "module example.com/bar/v3\n",
+		"modcache/example.com/bar/v3@v3.1.0/bar.go":             "package bar\n",
+		"modcache/example.com/bar/v3@v3.1.0/client/client.go":   "package client\n",
+		"modcache/example.com/bar/v3@v3.1.0/clients/pool.go":    "package clients\n",
+		"modcache/example.com/bar/v3@v3.1.0/server/server.go":   "package server\n",
+		"modcache/example.com/bar/v3@v3.1.0/windows/only.go":    "//go:build windows\n\npackage windows\n",
+		"modcache/example.com/bar/v3@v3.1.0/testonly/x_test.go": "package testonly\n",
+		"modcache/example.com/baz@v1.2.0/go.mod":                "module example.com/baz\n",
+		"modcache/example.com/baz@v1.2.0/util/util.go":          "package util\n",
+		"modcache/example.com/baz@v1.2.0/internal/x/x.go":       "package x\n",
+		"modcache/gopkg.in/yaml.v3@v3.0.1/go.mod":               "module gopkg.in/yaml.v3\n",
+		"modcache/gopkg.in/yaml.v3@v3.0.1/yaml.go":              "package yaml\n",
+		"app/go.mod":  "module example.com/app\n\nrequire (\n\texample.com/bar/v3 v3.1.0\n\tgopkg.in/yaml.v3 v3.0.1\n\texample.com/baz v1.2.0\n\texample.com/gone v1.0.0\n)\n",
+		"app/main.go": "package main\n",
+	}
+	testutil.WriteFiles(t, tmpDir, files)
+	env, err := goenv.Detect()
+	require.NoError(t, err)
+	project, err := gomod.LoadProject(filepath.Join(tmpDir, "app"), filepath.Join(tmpDir, "modcache"))
+	require.NoError(t, err)
+	loader := goload.New(project, env.GOROOT, goload.BuildContext{GOOS: "linux", GOARCH: "amd64"})
+
+	check := loader.CheckImport("example.com/bar/v3/client")
+	assert.Equal(t, goload.ImportOK, check.Status)
+	assert.Equal(t, "example.com/bar/v3", check.Module)
+	assert.Equal(t, "v3.1.0", check.Version)
+
+	check = loader.CheckImport("example.com/bar/v3/windows")
+	assert.Equal(t, goload.ImportOK, check.Status, "Packages excluded by build constraints still exist")
+	assert.NotEmpty(t, check.Message)
+
+	check = loader.CheckImport("example.com/bar/client")
+	assert.Equal(t, goload.ImportWrongMajor, check.Status)
+	assert.Equal(t, "example.com/bar/v3/client", check.Suggestion)
+
+	check = loader.CheckImport("example.com/bar/v2")
+	assert.Equal(t, goload.ImportWrongMajor, check.Status)
+	assert.Equal(t, "example.com/bar/v3", check.Suggestion)
+
+	check = loader.CheckImport("gopkg.in/yaml.v2")
+	assert.Equal(t, goload.ImportWrongMajor, check.Status)
+	assert.Equal(t, "gopkg.in/yaml.v3", check.Suggestion)
+
+	check = loader.CheckImport("example.com/baz/v2/util")
+	assert.Equal(t, goload.ImportWrongMajor, check.Status, "The path falls into the v1 module but names v2")
+	assert.Equal(t, "example.com/baz/util", check.Suggestion)
+
+	check = loader.CheckImport("example.com/bar/v3/clent")
+	assert.Equal(t, goload.ImportMissingPackage, check.Status)
+	require.NotEmpty(t, check.Nearest)
+	assert.Equal(t, "example.com/bar/v3/client", check.Nearest[0])
+
+	check = loader.CheckImport("example.com/bar/v3/testonly")
+	assert.Equal(t, goload.ImportMissingPackage, check.Status, "Test files alone do not make an importable package")
+
+	check = loader.CheckImport("example.com/baz/internal/x")
+	assert.Equal(t, goload.ImportInternal, check.Status, "Internal packages of other modules cannot be imported")
+	assert.Equal(t, "example.com/baz", check.Module)
+
+	check = loader.CheckImport("internal/poll")
+	assert.Equal(t, goload.ImportInternal, check.Status)
+	assert.True(t, check.Standard)
+
+	check = loader.CheckImport("example.com/gone/pkg")
+	assert.Equal(t, goload.ImportNotDownloaded, check.Status)
+
+	check = loader.CheckImport("example.com/barbaz")
+	assert.Equal(t, goload.ImportNotDependency, check.Status)
+
+	check = loader.CheckImport("net/http")
+	assert.Equal(t, goload.ImportOK, check.Status)
+	assert.True(t, check.Standard)
+
+	check = loader.CheckImport("net/htp")
+	assert.Equal(t, goload.ImportMissingPackage, check.Status)
+	assert.Contains(t, check.Nearest, "net/http")
+
+	check = loader.CheckImport("example.com/bar/../x")
+	assert.Equal(t, goload.ImportInvalid, check.Status)
+}
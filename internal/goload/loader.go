@@ -0,0 +1,350 @@
+package goload
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+)
+
+// maxErrors caps the number of type errors kept per package.
+const maxErrors = 10
+
+// Package is a parsed and type-checked Go package.
+type Package struct {
+	Path   string
+	Dir    string
+	Module *gomod.Module
+	Files  []*ast.File
+	Types  *types.Package
+	// Info is only populated for packages of the main module.
+	Info   *types.Info
+	Errors []error
+}
+
+// Loader parses and type-checks packages from source, resolving imports
+// through the standard library and the project's module requirements.
+// Packages are cached, so objects from the same package are identical across
+// loads.
+type Loader struct {
+	project *gomod.Project
+	goroot  string
+	bc      BuildContext
+	ctxt    build.Context
+	fset    *token.FileSet
+
+	packages map[string]*Package
+	byTypes  map[*types.Package]*Package
+	loading  map[string]bool
+}
+
+// New creates a loader for the given project. GOROOT locates the standard
+// library sources, and bc decides which files are part of each package.
+func New(project *gomod.Project, goroot string, bc BuildContext) *Loader {
+	return &Loader{
+		project:  project,
+		goroot:   goroot,
+		bc:       bc,
+		ctxt:     bc.buildContext(goroot),
+		fset:     token.NewFileSet(),
+		packages: make(map[string]*Package),
+		byTypes:  make(map[*types.Package]*Package),
+		loading:  make(map[string]bool),
+	}
+}
+
+// Fset returns the file set positions of loaded packages refer to.
+func (l *Loader) Fset() *token.FileSet {
+	return l.fset
+}
+
+// BuildContext returns the build context packages are loaded for.
+func (l *Loader) BuildContext() BuildContext {
+	return l.bc
+}
+
+// Project returns the project the loader resolves imports against.
+func (l *Loader) Project() *gomod.Project {
+	return l.project
+}
+
+// PackageOf returns the loaded package a type-checked package belongs to, or
+// nil if it was not loaded by l.
+func (l *Loader) PackageOf(pkg *types.Package) *Package {
+	return l.byTypes[pkg]
+}
+
+// Load returns the package with the given import path.
+func (l *Loader) Load(importPath string) (*Package, error) {
+	dir, mod, err := l.resolve(importPath, "")
+	if err != nil {
+		return nil, err
+	}
+	return l.loadDir(importPath, dir, mod)
+}
+
+// LoadDir returns the main module package in dir.
+func (l *Loader) LoadDir(dir string) (*Package, error) {
+	main := l.project.Main()
+	rel, err := filepath.Rel(main.Dir, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil, fmt.Errorf("directory %s is outside the main module", dir)
+	}
+	importPath := main.Path
+	if rel != "." {
+		importPath += "/" + filepath.ToSlash(rel)
+	}
+	return l.loadDir(importPath, dir, main)
+}
+
+// LoadTests returns the test variants of a main module package: the package
+// augmented with its in-package test files, and the external _test package.
+// Either is nil when the package has no such test files.
+func (l *Loader) LoadTests(pkg *Package) (*Package, *Package, error) {
+	bp, err := l.ctxt.ImportDir(pkg.Dir, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read package %s: %w", pkg.Path, err)
+	}
+
+	var internal, external *Package
+	if len(bp.TestGoFiles) > 0 {
+		files := append(append(append([]string{}, bp.GoFiles...), bp.CgoFiles...), bp.TestGoFiles...)
+		internal = l.check(pkg.Path, pkg.Dir, pkg.Module, files, true)
+	}
+	if len(bp.XTestGoFiles) > 0 {
+		external = l.check(pkg.Path+"_test", pkg.Dir, pkg.Module, bp.XTestGoFiles, true)
+	}
+	return internal, external, nil
+}
+
+// MainPackageDirs lists the directories of the main module containing Go
+// files.
+func (l *Loader) MainPackageDirs() ([]string, error) {
+	return l.packageDirs(l.project.Main().Dir)
+}
+
+// LoadModule loads every package of a module taking part in the project.
+// Packages that fail to load are reported in the returned errors.
+func (l *Loader) LoadModule(modulePath string) ([]*Package, []error, error) {
+	mod := l.project.Module(modulePath)
+	if mod == nil {
+		return nil, nil, fmt.Errorf("module %s is not required by %s", modulePath, l.project.Path)
+	}
+
+	dirs, err := l.packageDirs(mod.Dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pkgs []*Package
+	var loadErrs []error
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(mod.Dir, dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		importPath := mod.Path
+		if rel != "." {
+			importPath += "/" + filepath.ToSlash(rel)
+		}
+		pkg, err := l.loadDir(importPath, dir, mod)
+		if err != nil {
+			loadErrs = append(loadErrs, err)
+			continue
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, loadErrs, nil
+}
+
+// packageDirs lists the directories below root containing Go files, skipping
+// vendor, testdata, hidden directories and nested modules.
+func (l *Loader) packageDirs(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root {
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+		if _, err := l.ctxt.ImportDir(path, 0); err == nil {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk module directory: %w", err)
+	}
+	return dirs, nil
+}
+
+// resolve maps an import path to its source directory. fromDir is the
+// directory of the importing package and is used for the standard library's
+// vendored packages.
+func (l *Loader) resolve(importPath, fromDir string) (string, *gomod.Module, error) {
+	stdSrc := filepath.Join(l.goroot, "src")
+	if fromDir != "" && isWithin(stdSrc, fromDir) {
+		vendored := filepath.Join(stdSrc, "vendor", filepath.FromSlash(importPath))
+		if isDir(vendored) {
+			return vendored, nil, nil
+		}
+	}
+
+	if l.project.ModuleForImport(importPath) == nil && isStandardImportPath(importPath) {
+		dir := filepath.Join(stdSrc, filepath.FromSlash(importPath))
+		if !isDir(dir) {
+			return "", nil, fmt.Errorf("package %s is not in the standard library", importPath)
+		}
+		return dir, nil, nil
+	}
+
+	mod, dir, err := l.project.PackageDir(importPath)
+	if err != nil {
+		return "", nil, err
+	}
+	if !isDir(dir) {
+		if mod.Main {
+			return "", nil, fmt.Errorf("package %s not found in main module", importPath)
+		}
+		if mod.Vendored {
+			return "", nil, fmt.Errorf("package %s not found in vendor directory (run go mod vendor?)", importPath)
+		}
+		return "", nil, fmt.Errorf("package %s not found in %s@%s (is the module downloaded?)", importPath, mod.Path, mod.Version)
+	}
+	return dir, mod, nil
+}
+
+func (l *Loader) loadDir(importPath, dir string, mod *gomod.Module) (*Package, error) {
+	if pkg, ok := l.packages[dir]; ok {
+		return pkg, nil
+	}
+	if l.loading[dir] {
+		return nil, fmt.Errorf("import cycle through %s", importPath)
+	}
+	l.loading[dir] = true
+	defer delete(l.loading, dir)
+
+	bp, err := l.ctxt.ImportDir(dir, 0)
+	if err != nil {
+		var noGo *build.NoGoError
+		if !errors.As(err, &noGo) {
+			return nil, fmt.Errorf("failed to read package %s: %w", importPath, err)
+		}
+	}
+
+	full := mod != nil && mod.Main
+	// cgo files are type-checked with a fake "C" package.
+	files := append(append([]string{}, bp.GoFiles...), bp.CgoFiles...)
+	pkg := l.check(importPath, dir, mod, files, full)
+	l.packages[dir] = pkg
+	l.byTypes[pkg.Types] = pkg
+	return pkg, nil
+}
+
+// check parses the named files of dir and type-checks them. Function bodies
+// and type information are only kept when full is set.
+func (l *Loader) check(importPath, dir string, mod *gomod.Module, fileNames []string, full bool) *Package {
+	pkg := &Package{
+		Path:   importPath,
+		Dir:    dir,
+		Module: mod,
+	}
+
+	mode := parser.ParseComments | parser.SkipObjectResolution
+	for _, name := range fileNames {
+		file, err := parser.ParseFile(l.fset, filepath.Join(dir, name), nil, mode)
+		if err != nil {
+			pkg.addError(err)
+			if file == nil {
+				continue
+			}
+		}
+		pkg.Files = append(pkg.Files, file)
+	}
+
+	conf := types.Config{
+		Importer:         &importer{loader: l, fromDir: dir},
+		IgnoreFuncBodies: !full,
+		FakeImportC:      true,
+		Error:            pkg.addError,
+	}
+	if full {
+		pkg.Info = &types.Info{
+			Types:      make(map[ast.Expr]types.TypeAndValue),
+			Defs:       make(map[*ast.Ident]types.Object),
+			Uses:       make(map[*ast.Ident]types.Object),
+			Selections: make(map[*ast.SelectorExpr]*types.Selection),
+			Implicits:  make(map[ast.Node]types.Object),
+		}
+	}
+
+	// Errors are collected on the package; a partially checked package is
+	// still useful for navigation.
+	pkg.Types, _ = conf.Check(importPath, l.fset, pkg.Files, pkg.Info)
+	return pkg
+}
+
+func (p *Package) addError(err error) {
+	if len(p.Errors) < maxErrors {
+		p.Errors = append(p.Errors, err)
+	}
+}
+
+type importer struct {
+	loader  *Loader
+	fromDir string
+}
+
+func (i *importer) Import(path string) (*types.Package, error) {
+	return i.ImportFrom(path, i.fromDir, 0)
+}
+
+func (i *importer) ImportFrom(path, _ string, _ types.ImportMode) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	dir, mod, err := i.loader.resolve(path, i.fromDir)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := i.loader.loadDir(path, dir, mod)
+	if err != nil {
+		return nil, err
+	}
+	return pkg.Types, nil
+}
+
+// isStandardImportPath reports whether the first path element has no dot,
+// which is how the go command tells standard library packages apart.
+func isStandardImportPath(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
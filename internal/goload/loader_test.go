@@ -0,0 +1,79 @@
+package goload_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/goenv"
+	"github.com/svetlyi/mcp-local-context/internal/goload"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func setupLoader(t *testing.T, bc goload.BuildContext) *goload.Loader {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"modcache/example.com/sys@v1.0.0/go.mod":          "module example.com/sys\n",
+		"modcache/example.com/sys@v1.0.0/sys.go":          "package sys\n\nfunc Common() {}\n",
+		"modcache/example.com/sys@v1.0.0/sys_linux.go":    "package sys\n\nfunc Epoll() {}\n",
+		"modcache/example.com/sys@v1.0.0/sys_windows.go":  "package sys\n\nfunc IOCP() {}\n",
+		"modcache/example.com/sys@v1.0.0/sys_arm64.go":    "package sys\n\nfunc ARM() {}\n",
+		"modcache/example.com/sys@v1.0.0/sys_tagged.go":   "//go:build experimental\n\npackage sys\n\nfunc Experimental() {}\n",
+		"modcache/example.com/sys@v1.0.0/sys_untagged.go": "//go:build !experimental\n\npackage sys\n\nfunc Stable() {}\n",
+		"modcache/example.com/sys@v1.0.0/sys_cgo.go":      "package sys\n\n// #include <stdlib.h>\nimport \"C\"\n\nfunc Cgo() {}\n",
+		"modcache/example.com/sys@v1.0.0/sys_nocgo.go":    "//go:build !cgo\n\npackage sys\n\nfunc NoCgo() {}\n",
+		"app/go.mod": "module example.com/app\n\nrequire example.com/sys v1.0.0\n",
+	}
+	testutil.WriteFiles(t, tmpDir, files)
+
+	env, err := goenv.Detect()
+	require.NoError(t, err)
+	project, err := gomod.LoadProject(filepath.Join(tmpDir, "app"), filepath.Join(tmpDir, "modcache"))
+	require.NoError(t, err)
+	return goload.New(project, env.GOROOT, bc)
+}
+
+func scopeNames(t *testing.T, loader *goload.Loader) []string {
+	pkg, err := loader.Load("example.com/sys")
+	require.NoError(t, err)
+	require.Empty(t, pkg.Errors)
+	return pkg.Types.Scope().Names()
+}
+
+func TestLoaderBuildContext(t *testing.T) {
+	enabled, disabled := true, false
+
+	names := scopeNames(t, setupLoader(t, goload.BuildContext{GOOS: "linux", GOARCH: "amd64", CgoEnabled: &disabled}))
+	assert.ElementsMatch(t, []string{"Common", "Epoll", "Stable", "NoCgo"}, names)
+
+	names = scopeNames(t, setupLoader(t, goload.BuildContext{GOOS: "linux", GOARCH: "amd64", CgoEnabled: &enabled}))
+	assert.ElementsMatch(t, []string{"Common", "Epoll", "Stable", "Cgo"}, names)
+
+	names = scopeNames(t, setupLoader(t, goload.BuildContext{GOOS: "windows", GOARCH: "arm64", CgoEnabled: &disabled, Tags: []string{"experimental"}}))
+	assert.ElementsMatch(t, []string{"Common", "IOCP", "ARM", "Experimental", "NoCgo"}, names)
+}
+
+func TestBuildContextOverride(t *testing.T) {
+	base := goload.BuildContext{GOOS: "linux", GOARCH: "amd64", Tags: []string{"a"}}
+
+	bc := base.Override(goload.BuildContext{GOARCH: "arm64"})
+	assert.Equal(t, goload.BuildContext{GOOS: "linux", GOARCH: "arm64", Tags: []string{"a"}}, bc)
+
+	bc = base.Override(goload.BuildContext{Tags: []string{}})
+	assert.Empty(t, bc.Tags, "An explicit empty tag list should clear the tags")
+
+	disabled := false
+	bc = base.Override(goload.BuildContext{CgoEnabled: &disabled})
+	require.NotNil(t, bc.CgoEnabled)
+	assert.False(t, *bc.CgoEnabled)
+	assert.Equal(t, base.Tags, bc.Tags)
+}
+
+func TestBuildContextValidate(t *testing.T) {
+	assert.NoError(t, goload.BuildContext{}.Validate())
+	assert.NoError(t, goload.BuildContext{GOOS: "darwin", GOARCH: "arm64"}.Validate())
+	assert.Error(t, goload.BuildContext{GOOS: "darwn"}.Validate())
+	assert.Error(t, goload.BuildContext{GOARCH: "x86"}.Validate())
+}
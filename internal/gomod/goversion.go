@@ -0,0 +1,293 @@
+package gomod
+
+import (
+	"fmt"
+	"go/version"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ModuleGoVersion is the go and toolchain directives of a module's go.mod.
+type ModuleGoVersion struct {
+	Path      string `json:"path"`
+	Version   string `json:"version,omitempty"`
+	Go        string `json:"go,omitempty"`
+	Toolchain string `json:"toolchain,omitempty"`
+	// GoMod is the go.mod file the directives were read from, or
+	// vendor/modules.txt for vendored modules.
+	GoMod string `json:"go_mod,omitempty"`
+	// NewerThanMain reports that the module requires a newer Go than the
+	// main module declares.
+	NewerThanMain bool   `json:"newer_than_main,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// GoVersionReport compares the Go versions the modules of a build require.
+type GoVersionReport struct {
+	Go        string `json:"go"`
+	Toolchain string `json:"toolchain,omitempty"`
+	// MinimumGo is the newest go directive in the build list, the oldest Go
+	// release able to build the whole module graph, and MinimumGoModule the
+	// module declaring it.
+	MinimumGo       string            `json:"minimum_go"`
+	MinimumGoModule string            `json:"minimum_go_module"`
+	Modules         []ModuleGoVersion `json:"modules"`
+}
+
+// defaultGoVersion is the go version the go command assumes for a main
+// module whose go.mod has no go directive.
+const defaultGoVersion = "1.16"
+
+// GoVersions reads the go and toolchain directives of every module in the
+// project's build list. The go.mod files are read from the module cache's
+// download directory, which holds them even when the module sources have not
+// been extracted.
+func (p *Project) GoVersions() *GoVersionReport {
+	mainGo := p.GoVersion
+	if mainGo == "" {
+		mainGo = defaultGoVersion
+	}
+	report := &GoVersionReport{
+		Go:              mainGo,
+		MinimumGo:       mainGo,
+		MinimumGoModule: p.Path,
+		Modules:         make([]ModuleGoVersion, 0),
+	}
+	if p.File.Toolchain != nil {
+		report.Toolchain = p.File.Toolchain.Name
+	}
+
+	for _, mod := range p.BuildList() {
+		if mod.Main {
+			continue
+		}
+		info := p.moduleGoVersion(mod)
+		if info.Go != "" {
+			info.NewerThanMain = compareGo(info.Go, mainGo) > 0
+			if compareGo(info.Go, report.MinimumGo) > 0 {
+				report.MinimumGo = info.Go
+				report.MinimumGoModule = mod.Path
+			}
+		}
+		report.Modules = append(report.Modules, info)
+	}
+	return report
+}
+
+// BuildList returns the main module followed by every module of the build
+// list: the modules the main module requires and, by minimal version
+// selection over the go.mod files found in the module cache, the modules
+// they require in turn, each at the highest version required. The walk
+// stops at modules whose go.mod is not in the module cache. With vendoring
+// in effect, vendor/modules.txt already lists the build list.
+func (p *Project) BuildList() []*Module {
+	if p.Vendor != nil && p.Vendor.Enabled {
+		return p.Modules()
+	}
+
+	excluded := make(map[module.Version]bool)
+	for _, exclude := range p.File.Exclude {
+		excluded[exclude.Mod] = true
+	}
+	selected := make(map[string]string)
+	visited := make(map[module.Version]bool)
+	var queue []module.Version
+	require := func(req module.Version) {
+		if excluded[req] || req.Path == p.Path {
+			return
+		}
+		if current, ok := selected[req.Path]; !ok || semver.Compare(req.Version, current) > 0 {
+			selected[req.Path] = req.Version
+		}
+		if !visited[req] {
+			visited[req] = true
+			queue = append(queue, req)
+		}
+	}
+	for _, req := range p.File.Require {
+		require(req.Mod)
+	}
+	for len(queue) > 0 {
+		req := queue[0]
+		queue = queue[1:]
+		file, _, err := p.readGoMod(p.buildListModule(req))
+		if err != nil || file == nil {
+			continue
+		}
+		for _, dep := range file.Require {
+			require(dep.Mod)
+		}
+	}
+
+	modules := []*Module{p.Main()}
+	for _, req := range p.File.Require {
+		if mod := p.modules[req.Mod.Path]; mod != nil && mod.Version == selected[req.Mod.Path] {
+			modules = append(modules, mod)
+			delete(selected, req.Mod.Path)
+		}
+	}
+	paths := make([]string, 0, len(selected))
+	for path := range selected {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		modules = append(modules, p.buildListModule(module.Version{Path: path, Version: selected[path]}))
+	}
+	return modules
+}
+
+// buildListModule returns the module of the build list at version,
+// resolved like the required modules.
+func (p *Project) buildListModule(version module.Version) *Module {
+	if mod := p.modules[version.Path]; mod != nil && mod.Version == version.Version {
+		return mod
+	}
+	mod := &Module{Path: version.Path, Version: version.Version}
+	if err := p.resolveDir(mod); err != nil {
+		// An invalid path or version leaves the module without sources.
+		mod.Dir = ""
+	}
+	return mod
+}
+
+func (p *Project) moduleGoVersion(mod *Module) ModuleGoVersion {
+	info := ModuleGoVersion{Path: mod.Path, Version: mod.Version}
+
+	if mod.Vendored {
+		info.GoMod = filepath.Join(p.Vendor.Dir, "modules.txt")
+		for _, vendored := range p.Vendor.modules {
+			if vendored.Path == mod.Path {
+				info.Go = vendored.GoVersion
+			}
+		}
+		if info.Go == "" {
+			info.Error = "vendor/modules.txt does not record the module's go version"
+		}
+		return info
+	}
+
+	file, goMod, err := p.readGoMod(mod)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.GoMod = goMod
+	if file.Go != nil {
+		info.Go = file.Go.Version
+	}
+	if file.Toolchain != nil {
+		info.Toolchain = file.Toolchain.Name
+	}
+	return info
+}
+
+// readGoMod reads the go.mod of a module from the module cache's download
+// directory, else from the module's source directory, and returns it with
+// its path.
+func (p *Project) readGoMod(mod *Module) (*modfile.File, string, error) {
+	target := module.Version{Path: mod.Path, Version: mod.Version}
+	if mod.Replace != nil {
+		target = *mod.Replace
+	}
+	var candidates []string
+	if target.Version != "" {
+		if dir, err := downloadDir(p.modCache, target.Path); err == nil {
+			if escapedVersion, err := module.EscapeVersion(target.Version); err == nil {
+				candidates = append(candidates, filepath.Join(dir, escapedVersion+".mod"))
+			}
+		}
+	}
+	if mod.Dir != "" {
+		candidates = append(candidates, filepath.Join(mod.Dir, "go.mod"))
+	}
+
+	for _, goMod := range candidates {
+		data, err := os.ReadFile(goMod)
+		if err != nil {
+			continue
+		}
+		// ParseLax skips toolchain directives, so it is only a fallback for
+		// files using syntax this version of x/mod does not know.
+		file, err := modfile.Parse(goMod, data, nil)
+		if err != nil {
+			file, err = modfile.ParseLax(goMod, data, nil)
+		}
+		if err != nil {
+			return nil, goMod, fmt.Errorf("failed to parse %s: %v", goMod, err)
+		}
+		return file, goMod, nil
+	}
+	if target.Version == "" {
+		return nil, "", fmt.Errorf("go.mod not found in %s", mod.Dir)
+	}
+	return nil, "", fmt.Errorf("go.mod of %s@%s not found in the module cache; run `go mod download %s@%s`", target.Path, target.Version, target.Path, target.Version)
+}
+
+// downloadDir returns the module cache download directory of path, which
+// holds the .mod, .info and .zip files of its versions.
+func downloadDir(modCache, path string) (string, error) {
+	escapedPath, err := module.EscapePath(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(modCache, "cache", "download", filepath.FromSlash(escapedPath), "@v"), nil
+}
+
+// LatestGoMod returns the go.mod of the newest version of mod found in the
+// module cache's download directory and that version. Releases are preferred
+// over pre-releases, as in the go command's "latest" query. When no version
+// newer than the required one has been downloaded, the go.mod in mod.Dir is
+// returned with mod.Version.
+func (p *Project) LatestGoMod(mod *Module) (goMod, version string) {
+	goMod, version = filepath.Join(mod.Dir, "go.mod"), mod.Version
+	dir, err := downloadDir(p.modCache, mod.Path)
+	if err != nil {
+		return goMod, version
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return goMod, version
+	}
+	var latest string
+	for _, entry := range entries {
+		escaped, ok := strings.CutSuffix(entry.Name(), ".mod")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		v, err := module.UnescapeVersion(escaped)
+		if err != nil || !semver.IsValid(v) {
+			continue
+		}
+		if latest == "" || newerVersion(v, latest) {
+			latest = v
+		}
+	}
+	if latest == "" || (mod.Version != "" && semver.Compare(latest, mod.Version) <= 0) {
+		return goMod, version
+	}
+	escaped, _ := module.EscapeVersion(latest)
+	return filepath.Join(dir, escaped+".mod"), latest
+}
+
+// newerVersion reports whether v is preferred over latest: a release over a
+// pre-release, else the higher version.
+func newerVersion(v, latest string) bool {
+	vRelease, latestRelease := semver.Prerelease(v) == "", semver.Prerelease(latest) == ""
+	if vRelease != latestRelease {
+		return vRelease
+	}
+	return semver.Compare(v, latest) > 0
+}
+
+// compareGo compares go directive versions such as 1.21 and 1.22.3.
+// Missing versions sort first.
+func compareGo(a, b string) int {
+	return version.Compare("go"+a, "go"+b)
+}
@@ -0,0 +1,107 @@
+package gomod_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func TestGoVersions(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "app")
+	modCache := filepath.Join(tmpDir, "modcache")
+
+	testutil.WriteFile(t, filepath.Join(projectDir, "go.mod"), `module example.com/app
+
+go 1.22
+
+toolchain go1.22.4
+
+require (
+	example.com/old v1.0.0
+	example.com/new v1.3.0
+	example.com/local v0.0.0
+	example.com/missing v1.0.0
+)
+
+replace example.com/local => ../local
+`)
+	// Only the download directory is populated for example.com/new, as
+	// after `go mod download -json` without extraction.
+	testutil.WriteFile(t, filepath.Join(modCache, "example.com", "old@v1.0.0", "go.mod"), "module example.com/old\n\ngo 1.21\n")
+	testutil.WriteFile(t, filepath.Join(modCache, "cache", "download", "example.com", "new", "@v", "v1.3.0.mod"), "module example.com/new\n\ngo 1.24.1\n\ntoolchain go1.24.3\n")
+	testutil.WriteFile(t, filepath.Join(tmpDir, "local", "go.mod"), "module example.com/local\n\ngo 1.23\n")
+
+	project, err := gomod.LoadProject(projectDir, modCache)
+	require.NoError(t, err)
+	report := project.GoVersions()
+
+	assert.Equal(t, "1.22", report.Go)
+	assert.Equal(t, "go1.22.4", report.Toolchain)
+	assert.Equal(t, "1.24.1", report.MinimumGo)
+	assert.Equal(t, "example.com/new", report.MinimumGoModule)
+
+	byPath := make(map[string]gomod.ModuleGoVersion)
+	for _, mod := range report.Modules {
+		byPath[mod.Path] = mod
+	}
+	require.Len(t, byPath, 4)
+
+	assert.Equal(t, "1.21", byPath["example.com/old"].Go)
+	assert.False(t, byPath["example.com/old"].NewerThanMain)
+
+	assert.Equal(t, "1.24.1", byPath["example.com/new"].Go)
+	assert.Equal(t, "go1.24.3", byPath["example.com/new"].Toolchain)
+	assert.True(t, byPath["example.com/new"].NewerThanMain)
+
+	assert.Equal(t, "1.23", byPath["example.com/local"].Go)
+	assert.True(t, byPath["example.com/local"].NewerThanMain)
+
+	assert.Empty(t, byPath["example.com/missing"].Go)
+	assert.Contains(t, byPath["example.com/missing"].Error, "go mod download")
+}
+
+func TestGoVersionsBuildList(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "app")
+	modCache := filepath.Join(tmpDir, "modcache")
+	download := filepath.Join(modCache, "cache", "download", "example.com")
+
+	// Without a go directive the main module is go 1.16, whose go.mod
+	// lists only the direct requirements.
+	testutil.WriteFile(t, filepath.Join(projectDir, "go.mod"), `module example.com/app
+
+require example.com/direct v1.0.0
+
+exclude example.com/excluded v1.0.0
+`)
+	testutil.WriteFiles(t, download, map[string]string{
+		"direct/@v/v1.0.0.mod":   "module example.com/direct\n\ngo 1.16\n\nrequire (\n\texample.com/shared v1.1.0\n\texample.com/other v1.0.0\n\texample.com/excluded v1.0.0\n)\n",
+		"other/@v/v1.0.0.mod":    "module example.com/other\n\ngo 1.16\n\nrequire example.com/shared v1.2.0\n",
+		"shared/@v/v1.1.0.mod":   "module example.com/shared\n\ngo 1.16\n",
+		"shared/@v/v1.2.0.mod":   "module example.com/shared\n\ngo 1.21\n",
+		"excluded/@v/v1.0.0.mod": "module example.com/excluded\n\ngo 1.23\n",
+	})
+
+	project, err := gomod.LoadProject(projectDir, modCache)
+	require.NoError(t, err)
+	report := project.GoVersions()
+
+	assert.Equal(t, "1.16", report.Go)
+	assert.Equal(t, "1.21", report.MinimumGo)
+	assert.Equal(t, "example.com/shared", report.MinimumGoModule)
+
+	versions := make(map[string]string)
+	for _, mod := range report.Modules {
+		versions[mod.Path] = mod.Version
+	}
+	assert.Equal(t, map[string]string{
+		"example.com/direct": "v1.0.0",
+		"example.com/other":  "v1.0.0",
+		"example.com/shared": "v1.2.0",
+	}, versions)
+}
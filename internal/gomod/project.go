@@ -0,0 +1,271 @@
+package gomod
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// ErrNoModule is returned when no go.mod file can be found for a directory.
+var ErrNoModule = errors.New("go.mod not found")
+
+// Module is a module taking part in a project's build, together with the
+// directory holding its sources.
+type Module struct {
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+	Dir     string `json:"dir"`
+	Main    bool   `json:"main,omitempty"`
+	// Replace is set when a replace directive redirects the module.
+	Replace *module.Version `json:"replace,omitempty"`
+	// Vendored is set when the sources are served from the vendor directory.
+	Vendored bool `json:"vendored,omitempty"`
+}
+
+// Project is a Go module on disk and the module versions its go.mod requires.
+type Project struct {
+	Dir       string
+	Path      string
+	GoVersion string
+	File      *modfile.File
+	// Vendor is nil when the project has no vendor directory.
+	Vendor *Vendor
+
+	modCache string
+	modules  map[string]*Module
+}
+
+// Option configures how a project is loaded.
+type Option func(*loadOptions)
+
+type loadOptions struct {
+	modFlag string
+}
+
+// WithModFlag applies the go command's -mod flag ("mod", "readonly" or
+// "vendor"), which decides whether the vendor directory is used.
+func WithModFlag(flag string) Option {
+	return func(o *loadOptions) {
+		o.modFlag = flag
+	}
+}
+
+// LoadProject finds the go.mod governing dir, walking up the directory tree,
+// and resolves every required module against the module cache, or against
+// the vendor directory when vendoring is in effect.
+func LoadProject(dir, modCache string, opts ...Option) (*Project, error) {
+	var options loadOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+
+	rootDir, err := findModuleRoot(absDir)
+	if err != nil {
+		return nil, err
+	}
+
+	goModPath := filepath.Join(rootDir, "go.mod")
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+
+	file, err := modfile.Parse(goModPath, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod: %w", err)
+	}
+	if file.Module == nil {
+		return nil, fmt.Errorf("go.mod has no module directive: %s", goModPath)
+	}
+
+	p := &Project{
+		Dir:      rootDir,
+		Path:     file.Module.Mod.Path,
+		File:     file,
+		modCache: modCache,
+		modules:  make(map[string]*Module),
+	}
+	if file.Go != nil {
+		p.GoVersion = file.Go.Version
+	}
+
+	p.modules[p.Path] = &Module{Path: p.Path, Dir: rootDir, Main: true}
+
+	p.Vendor, err = p.loadVendor(options.modFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, req := range file.Require {
+		mod := &Module{Path: req.Mod.Path, Version: req.Mod.Version}
+		if err := p.resolveDir(mod); err != nil {
+			return nil, err
+		}
+		p.modules[mod.Path] = mod
+	}
+
+	// Before go 1.17, go.mod did not list every module of the build, but
+	// vendor/modules.txt always does.
+	if p.Vendor != nil && p.Vendor.Enabled {
+		for _, vendored := range p.Vendor.modules {
+			if _, ok := p.modules[vendored.Path]; ok || vendored.Version == "" {
+				continue
+			}
+			mod := &Module{Path: vendored.Path, Version: vendored.Version, Replace: vendored.Replace}
+			if err := p.resolveDir(mod); err != nil {
+				return nil, err
+			}
+			p.modules[mod.Path] = mod
+		}
+	}
+
+	return p, nil
+}
+
+func findModuleRoot(dir string) (string, error) {
+	for current := dir; ; {
+		if info, err := os.Stat(filepath.Join(current, "go.mod")); err == nil && !info.IsDir() {
+			return current, nil
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", fmt.Errorf("%w in %s or any parent directory", ErrNoModule, dir)
+		}
+		current = parent
+	}
+}
+
+// resolveDir applies replace directives and sets the module source directory.
+func (p *Project) resolveDir(mod *Module) error {
+	target := module.Version{Path: mod.Path, Version: mod.Version}
+	for _, rep := range p.File.Replace {
+		if rep.Old.Path != mod.Path {
+			continue
+		}
+		// A versioned replacement takes precedence over a wildcard one.
+		if rep.Old.Version != "" && rep.Old.Version != mod.Version {
+			continue
+		}
+		target = rep.New
+		mod.Replace = &rep.New
+		if rep.Old.Version != "" {
+			break
+		}
+	}
+
+	// Vendored modules, replaced or not, live under their original path.
+	if p.Vendor != nil && p.Vendor.Enabled {
+		mod.Dir = filepath.Join(p.Vendor.Dir, filepath.FromSlash(mod.Path))
+		mod.Vendored = true
+		return nil
+	}
+
+	if target.Version == "" {
+		dir := target.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(p.Dir, dir)
+		}
+		mod.Dir = filepath.Clean(dir)
+		return nil
+	}
+
+	dir, err := CacheDir(p.modCache, target.Path, target.Version)
+	if err != nil {
+		return err
+	}
+	mod.Dir = dir
+	return nil
+}
+
+// CacheDir returns the module cache directory for path@version.
+func CacheDir(modCache, path, version string) (string, error) {
+	escapedPath, err := module.EscapePath(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid module path %q: %w", path, err)
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("invalid module version %q: %w", version, err)
+	}
+	return filepath.Join(modCache, filepath.FromSlash(escapedPath)+"@"+escapedVersion), nil
+}
+
+// ModCache returns the module cache directory the project resolves against.
+func (p *Project) ModCache() string {
+	return p.modCache
+}
+
+// Main returns the project's main module.
+func (p *Project) Main() *Module {
+	return p.modules[p.Path]
+}
+
+// Module returns the module with the given path, or nil if it is not part of
+// the project's requirements.
+func (p *Project) Module(path string) *Module {
+	return p.modules[path]
+}
+
+// Modules returns the main module followed by every required module.
+func (p *Project) Modules() []*Module {
+	modules := []*Module{p.Main()}
+	for _, req := range p.File.Require {
+		modules = append(modules, p.modules[req.Mod.Path])
+	}
+	if p.Vendor != nil && p.Vendor.Enabled {
+		for _, vendored := range p.Vendor.modules {
+			if mod := p.modules[vendored.Path]; mod != nil && !slices.Contains(modules, mod) {
+				modules = append(modules, mod)
+			}
+		}
+	}
+	return modules
+}
+
+// Warnings describes problems with the project that may make results
+// differ from what the go command would build.
+func (p *Project) Warnings() []string {
+	var warnings []string
+	if p.Vendor != nil {
+		for _, problem := range p.Vendor.Inconsistencies {
+			warnings = append(warnings, "inconsistent vendoring: "+problem)
+		}
+	}
+	return warnings
+}
+
+// ModuleForImport returns the module providing importPath, chosen by the
+// longest module path prefix, or nil if no module matches.
+func (p *Project) ModuleForImport(importPath string) *Module {
+	var best *Module
+	for path, mod := range p.modules {
+		if importPath != path && !strings.HasPrefix(importPath, path+"/") {
+			continue
+		}
+		if best == nil || len(path) > len(best.Path) {
+			best = mod
+		}
+	}
+	return best
+}
+
+// PackageDir returns the directory of importPath inside its providing module.
+func (p *Project) PackageDir(importPath string) (*Module, string, error) {
+	mod := p.ModuleForImport(importPath)
+	if mod == nil {
+		return nil, "", fmt.Errorf("no required module provides package %s", importPath)
+	}
+	rel := strings.TrimPrefix(strings.TrimPrefix(importPath, mod.Path), "/")
+	return mod, filepath.Join(mod.Dir, filepath.FromSlash(rel)), nil
+}
@@ -0,0 +1,89 @@
+package gomod_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func TestLoadProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "app")
+	modCache := filepath.Join(tmpDir, "modcache")
+
+	testutil.WriteFile(t, filepath.Join(projectDir, "go.mod"), `module example.com/app
+
+go 1.22
+
+require (
+	example.com/dep v1.2.0
+	github.com/Azure/sdk v0.1.0
+	example.com/local v0.0.0
+)
+
+replace example.com/local => ../local
+`)
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, "internal", "sub"), 0755))
+
+	project, err := gomod.LoadProject(filepath.Join(projectDir, "internal", "sub"), modCache)
+	require.NoError(t, err)
+
+	assert.Equal(t, projectDir, project.Dir)
+	assert.Equal(t, "example.com/app", project.Path)
+	assert.Equal(t, "1.22", project.GoVersion)
+	assert.True(t, project.Main().Main)
+	assert.Len(t, project.Modules(), 4)
+
+	dep := project.Module("example.com/dep")
+	require.NotNil(t, dep)
+	assert.Equal(t, "v1.2.0", dep.Version)
+	assert.Equal(t, filepath.Join(modCache, "example.com", "dep@v1.2.0"), dep.Dir)
+
+	azure := project.Module("github.com/Azure/sdk")
+	require.NotNil(t, azure)
+	assert.Equal(t, filepath.Join(modCache, "github.com", "!azure", "sdk@v0.1.0"), azure.Dir, "Upper case letters should be escaped")
+
+	local := project.Module("example.com/local")
+	require.NotNil(t, local)
+	require.NotNil(t, local.Replace)
+	assert.Equal(t, filepath.Join(tmpDir, "local"), local.Dir)
+}
+
+func TestLoadProjectMissingGoMod(t *testing.T) {
+	_, err := gomod.LoadProject(t.TempDir(), "")
+	assert.ErrorIs(t, err, gomod.ErrNoModule)
+}
+
+func TestProjectPackageDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	modCache := filepath.Join(tmpDir, "modcache")
+
+	testutil.WriteFile(t, filepath.Join(tmpDir, "go.mod"), `module example.com/app
+
+require (
+	example.com/dep v1.0.0
+	example.com/dep/sub v1.1.0
+)
+`)
+
+	project, err := gomod.LoadProject(tmpDir, modCache)
+	require.NoError(t, err)
+
+	mod, dir, err := project.PackageDir("example.com/dep/sub/pkg")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/dep/sub", mod.Path, "Longest module path should win")
+	assert.Equal(t, filepath.Join(modCache, "example.com", "dep", "sub@v1.1.0", "pkg"), dir)
+
+	mod, dir, err = project.PackageDir("example.com/app/internal/x")
+	require.NoError(t, err)
+	assert.True(t, mod.Main)
+	assert.Equal(t, filepath.Join(tmpDir, "internal", "x"), dir)
+
+	_, _, err = project.PackageDir("example.com/other")
+	assert.Error(t, err)
+}
@@ -0,0 +1,202 @@
+package gomod
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// vendorModule is a module entry of vendor/modules.txt.
+type vendorModule struct {
+	Path     string
+	Version  string
+	Replace  *module.Version
+	Explicit bool
+	// GoVersion is the go directive of the module's go.mod.
+	GoVersion string
+}
+
+// Vendor describes the vendor directory of a project.
+type Vendor struct {
+	Dir string `json:"dir"`
+	// Enabled reports whether the go command builds from the vendor
+	// directory, which makes it the source of truth for dependencies.
+	Enabled bool `json:"enabled"`
+	// Inconsistencies lists the disagreements between go.mod and
+	// vendor/modules.txt. The go command refuses to build until
+	// `go mod vendor` is run again.
+	Inconsistencies []string `json:"inconsistencies,omitempty"`
+
+	modules []vendorModule
+}
+
+// vendorEnabled decides whether the go command uses the vendor directory,
+// following its rules: -mod=vendor forces it, -mod=mod and -mod=readonly
+// disable it, and otherwise it is on when the vendor directory exists and
+// go.mod declares go 1.14 or later.
+func vendorEnabled(vendorDir, goVersion, modFlag string) bool {
+	switch modFlag {
+	case "vendor":
+		return true
+	case "mod", "readonly":
+		return false
+	}
+	if info, err := os.Stat(vendorDir); err != nil || !info.IsDir() {
+		return false
+	}
+	return goVersion != "" && compareGo(goVersion, "1.14") >= 0
+}
+
+// loadVendor reads vendor/modules.txt. It returns nil when the project has
+// no vendor directory.
+func (p *Project) loadVendor(modFlag string) (*Vendor, error) {
+	vendorDir := filepath.Join(p.Dir, "vendor")
+	if !vendorEnabled(vendorDir, p.GoVersion, modFlag) {
+		if _, err := os.Stat(vendorDir); err != nil {
+			return nil, nil
+		}
+		return &Vendor{Dir: vendorDir}, nil
+	}
+
+	v := &Vendor{Dir: vendorDir, Enabled: true}
+	data, err := os.ReadFile(filepath.Join(vendorDir, "modules.txt"))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read vendor/modules.txt: %w", err)
+		}
+		if len(p.File.Require) > 0 {
+			v.Inconsistencies = append(v.Inconsistencies, "vendor/modules.txt is missing; run go mod vendor")
+		}
+		return v, nil
+	}
+
+	v.modules = parseVendorModules(data)
+	v.Inconsistencies = p.checkVendorConsistency(v.modules)
+	return v, nil
+}
+
+// parseVendorModules parses the module lines of vendor/modules.txt:
+//
+//	# example.com/mod v1.2.3 => ../mod
+//	## explicit; go 1.21
+//	example.com/mod/pkg
+func parseVendorModules(data []byte) []vendorModule {
+	var modules []vendorModule
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if rest, ok := strings.CutPrefix(line, "## "); ok {
+			if len(modules) == 0 {
+				continue
+			}
+			for _, annotation := range strings.Split(rest, ";") {
+				annotation = strings.TrimSpace(annotation)
+				if annotation == "explicit" {
+					modules[len(modules)-1].Explicit = true
+				}
+				if goVersion, ok := strings.CutPrefix(annotation, "go "); ok {
+					modules[len(modules)-1].GoVersion = goVersion
+				}
+			}
+			continue
+		}
+		rest, ok := strings.CutPrefix(line, "# ")
+		if !ok {
+			continue
+		}
+
+		old, replacement, replaced := strings.Cut(rest, " => ")
+		fields := strings.Fields(old)
+		if len(fields) == 0 {
+			continue
+		}
+		mod := vendorModule{Path: fields[0]}
+		if len(fields) > 1 {
+			mod.Version = fields[1]
+		}
+		if replaced {
+			newFields := strings.Fields(replacement)
+			if len(newFields) > 0 {
+				mod.Replace = &module.Version{Path: newFields[0]}
+				if len(newFields) > 1 {
+					mod.Replace.Version = newFields[1]
+				}
+			}
+		}
+		modules = append(modules, mod)
+	}
+	return modules
+}
+
+// checkVendorConsistency compares go.mod with vendor/modules.txt the way the
+// go command does before building in vendor mode.
+func (p *Project) checkVendorConsistency(vendored []vendorModule) []string {
+	var problems []string
+
+	byPath := make(map[string]vendorModule)
+	for _, mod := range vendored {
+		// Wildcard replacements are listed without a version.
+		if mod.Version == "" && mod.Replace != nil {
+			continue
+		}
+		byPath[mod.Path] = mod
+	}
+
+	required := make(map[string]bool)
+	for _, req := range p.File.Require {
+		required[req.Mod.Path] = true
+		mod, ok := byPath[req.Mod.Path]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s@%s is required in go.mod, but not listed in vendor/modules.txt", req.Mod.Path, req.Mod.Version))
+		case mod.Version != req.Mod.Version:
+			problems = append(problems, fmt.Sprintf("%s@%s is required in go.mod, but vendor/modules.txt has %s", req.Mod.Path, req.Mod.Version, mod.Version))
+		case !mod.Explicit:
+			problems = append(problems, fmt.Sprintf("%s@%s is required in go.mod, but not marked as explicit in vendor/modules.txt", req.Mod.Path, req.Mod.Version))
+		}
+	}
+
+	for _, mod := range vendored {
+		if mod.Explicit && !required[mod.Path] {
+			problems = append(problems, fmt.Sprintf("%s@%s is marked as explicit in vendor/modules.txt, but not required in go.mod", mod.Path, mod.Version))
+		}
+	}
+
+	for _, rep := range p.File.Replace {
+		if !vendorHasReplacement(vendored, rep.Old, rep.New) {
+			problems = append(problems, fmt.Sprintf("go.mod replaces %s with %s, but vendor/modules.txt does not", formatVersion(rep.Old), formatVersion(rep.New)))
+		}
+	}
+
+	return problems
+}
+
+func vendorHasReplacement(vendored []vendorModule, old, replacement module.Version) bool {
+	for _, mod := range vendored {
+		if mod.Path != old.Path || mod.Replace == nil || *mod.Replace != replacement {
+			continue
+		}
+		if old.Version == "" || mod.Version == old.Version {
+			return true
+		}
+	}
+	// A replacement of a module the build does not use is not vendored.
+	for _, mod := range vendored {
+		if mod.Path == old.Path {
+			return false
+		}
+	}
+	return true
+}
+
+func formatVersion(v module.Version) string {
+	if v.Version == "" {
+		return v.Path
+	}
+	return v.Path + "@" + v.Version
+}
@@ -0,0 +1,115 @@
+package gomod_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+const vendoredGoMod = `module example.com/app
+
+go 1.21
+
+require (
+	example.com/dep v1.2.0
+	example.com/local v0.0.0
+)
+
+replace example.com/local => ../local
+`
+
+func TestLoadProjectVendor(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(tmpDir, "go.mod"), vendoredGoMod)
+	testutil.WriteFile(t, filepath.Join(tmpDir, "vendor", "modules.txt"), `# example.com/dep v1.2.0
+## explicit; go 1.20
+example.com/dep
+# example.com/indirect v0.3.0
+example.com/indirect
+# example.com/local v0.0.0 => ../local
+## explicit
+example.com/local
+# example.com/local => ../local
+`)
+
+	project, err := gomod.LoadProject(tmpDir, filepath.Join(tmpDir, "modcache"))
+	require.NoError(t, err)
+
+	require.NotNil(t, project.Vendor)
+	assert.True(t, project.Vendor.Enabled)
+	assert.Empty(t, project.Vendor.Inconsistencies)
+	assert.Empty(t, project.Warnings())
+
+	dep := project.Module("example.com/dep")
+	require.NotNil(t, dep)
+	assert.True(t, dep.Vendored)
+	assert.Equal(t, filepath.Join(tmpDir, "vendor", "example.com", "dep"), dep.Dir)
+
+	local := project.Module("example.com/local")
+	require.NotNil(t, local)
+	assert.Equal(t, filepath.Join(tmpDir, "vendor", "example.com", "local"), local.Dir, "Replaced modules are vendored under their original path")
+
+	indirect := project.Module("example.com/indirect")
+	require.NotNil(t, indirect, "Modules only listed in vendor/modules.txt are part of the build")
+	assert.Len(t, project.Modules(), 4)
+}
+
+func TestLoadProjectVendorInconsistent(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(tmpDir, "go.mod"), vendoredGoMod)
+	testutil.WriteFile(t, filepath.Join(tmpDir, "vendor", "modules.txt"), `# example.com/dep v1.1.0
+## explicit
+example.com/dep
+# example.com/extra v1.0.0
+## explicit
+example.com/extra
+`)
+
+	project, err := gomod.LoadProject(tmpDir, filepath.Join(tmpDir, "modcache"))
+	require.NoError(t, err)
+
+	assert.True(t, project.Vendor.Enabled)
+	assert.ElementsMatch(t, []string{
+		"example.com/dep@v1.2.0 is required in go.mod, but vendor/modules.txt has v1.1.0",
+		"example.com/local@v0.0.0 is required in go.mod, but not listed in vendor/modules.txt",
+		"example.com/extra@v1.0.0 is marked as explicit in vendor/modules.txt, but not required in go.mod",
+	}, project.Vendor.Inconsistencies)
+	assert.Len(t, project.Warnings(), 3)
+}
+
+func TestLoadProjectVendorDisabled(t *testing.T) {
+	tmpDir := t.TempDir()
+	modCache := filepath.Join(tmpDir, "modcache")
+	testutil.WriteFile(t, filepath.Join(tmpDir, "go.mod"), vendoredGoMod)
+	testutil.WriteFile(t, filepath.Join(tmpDir, "vendor", "modules.txt"), "")
+
+	project, err := gomod.LoadProject(tmpDir, modCache, gomod.WithModFlag("mod"))
+	require.NoError(t, err)
+	require.NotNil(t, project.Vendor)
+	assert.False(t, project.Vendor.Enabled)
+	assert.Equal(t, filepath.Join(modCache, "example.com", "dep@v1.2.0"), project.Module("example.com/dep").Dir)
+
+	testutil.WriteFile(t, filepath.Join(tmpDir, "go.mod"), "module example.com/app\n\ngo 1.13\n")
+	project, err = gomod.LoadProject(tmpDir, modCache)
+	require.NoError(t, err)
+	assert.False(t, project.Vendor.Enabled, "Vendoring is only automatic from go 1.14")
+
+	project, err = gomod.LoadProject(tmpDir, modCache, gomod.WithModFlag("vendor"))
+	require.NoError(t, err)
+	assert.True(t, project.Vendor.Enabled)
+}
+
+func TestLoadProjectVendorReleaseCandidate(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(tmpDir, "go.mod"), "module example.com/app\n\ngo 1.21rc1\n")
+	testutil.WriteFile(t, filepath.Join(tmpDir, "vendor", "modules.txt"), "")
+
+	project, err := gomod.LoadProject(tmpDir, filepath.Join(tmpDir, "modcache"))
+	require.NoError(t, err)
+	require.NotNil(t, project.Vendor)
+	assert.True(t, project.Vendor.Enabled, "go 1.21rc1 is newer than go 1.14")
+}
@@ -0,0 +1,248 @@
+package jvm
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxEntryBytes bounds the size of a jar entry read into memory.
+const maxEntryBytes = 8 << 20
+
+// sourceExtensions are the extensions of the source files of a sources
+// jar, in order of preference.
+var sourceExtensions = []string{".java", ".kt", ".groovy", ".scala"}
+
+// Package is a package of a jar and the classes, or source files, in it.
+type Package struct {
+	Name    string   `json:"name"`
+	Classes []string `json:"classes"`
+}
+
+// ListPackages lists the packages of a jar whose names start with prefix.
+// In a sources jar, the classes are the names of the source files, which
+// for Kotlin may declare several classes; in a binary jar they are the
+// top-level classes.
+func ListPackages(jarPath string, sources bool, prefix string) ([]Package, error) {
+	r, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", jarPath, err)
+	}
+	defer r.Close()
+
+	byName := make(map[string]*Package)
+	for _, f := range r.File {
+		dir, file := path.Split(f.Name)
+		class, ok := className(file, sources)
+		if !ok || strings.HasPrefix(dir, "META-INF/") {
+			continue
+		}
+		name := strings.ReplaceAll(strings.TrimSuffix(dir, "/"), "/", ".")
+		if prefix != "" && name != prefix && !strings.HasPrefix(name, prefix+".") {
+			continue
+		}
+		pkg, ok := byName[name]
+		if !ok {
+			pkg = &Package{Name: name, Classes: make([]string, 0)}
+			byName[name] = pkg
+		}
+		pkg.Classes = append(pkg.Classes, class)
+	}
+
+	packages := make([]Package, 0, len(byName))
+	for _, pkg := range byName {
+		sort.Strings(pkg.Classes)
+		packages = append(packages, *pkg)
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	return packages, nil
+}
+
+// className returns the class a jar entry file holds, leaving out nested
+// and synthetic classes, package-info and module-info.
+func className(file string, sources bool) (string, bool) {
+	if file == "" || strings.HasPrefix(file, "package-info.") || strings.HasPrefix(file, "module-info.") {
+		return "", false
+	}
+	if !sources {
+		class, ok := strings.CutSuffix(file, ".class")
+		return class, ok && !strings.Contains(class, "$")
+	}
+	for _, extension := range sourceExtensions {
+		if class, ok := strings.CutSuffix(file, extension); ok {
+			return class, true
+		}
+	}
+	return "", false
+}
+
+// kotlinDeclarationRE matches the declaration of a class, interface or
+// object in Kotlin sources, whose file names need not match them.
+var kotlinDeclarationRE = regexp.MustCompile(`(?m)^\s*(?:(?:public|internal|private|sealed|data|enum|annotation|abstract|open|inline|value|fun|expect|actual)\s+)*(?:class|interface|object|typealias)\s+([A-Za-z_][A-Za-z0-9_]*)`)
+
+// ReadSource returns the entry of a sources jar declaring the class, given
+// its qualified name such as com.google.common.collect.ImmutableList. The
+// source file of a nested class is its outermost class's, and FooKt is
+// found in Foo.kt.
+func ReadSource(jarPath, class string) (string, []byte, error) {
+	r, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to open %s: %w", jarPath, err)
+	}
+	defer r.Close()
+	files := make(map[string]*zip.File, len(r.File))
+	for _, f := range r.File {
+		files[f.Name] = f
+	}
+
+	segments := strings.Split(strings.TrimSuffix(class, ".java"), ".")
+	// Try the longest prefix naming a file, so that nested classes resolve
+	// to their outer class.
+	for i := len(segments); i >= 1; i-- {
+		base := strings.Join(segments[:i], "/")
+		candidates := make([]string, 0, len(sourceExtensions)+1)
+		for _, extension := range sourceExtensions {
+			candidates = append(candidates, base+extension)
+		}
+		if trimmed, ok := strings.CutSuffix(base, "Kt"); ok {
+			candidates = append(candidates, trimmed+".kt")
+		}
+		for _, candidate := range candidates {
+			if f, ok := files[candidate]; ok {
+				data, err := readEntry(f)
+				return candidate, data, err
+			}
+		}
+	}
+
+	// Kotlin classes may live in a file of another name in their package.
+	for i := len(segments) - 1; i >= 1; i-- {
+		dir := strings.Join(segments[:i], "/") + "/"
+		name := segments[i]
+		for _, f := range r.File {
+			if !strings.HasPrefix(f.Name, dir) || strings.Contains(f.Name[len(dir):], "/") || !strings.HasSuffix(f.Name, ".kt") {
+				continue
+			}
+			data, err := readEntry(f)
+			if err != nil {
+				return "", nil, err
+			}
+			for _, m := range kotlinDeclarationRE.FindAllSubmatch(data, -1) {
+				if string(m[1]) == name {
+					return f.Name, data, nil
+				}
+			}
+		}
+	}
+	return "", nil, fmt.Errorf("no source of %s in %s", class, jarPath)
+}
+
+// HasClass reports whether a binary jar contains the class.
+func HasClass(jarPath, class string) (bool, error) {
+	r, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to open %s: %w", jarPath, err)
+	}
+	defer r.Close()
+	names := make(map[string]bool, len(r.File))
+	for _, f := range r.File {
+		names[f.Name] = true
+	}
+	// Nested classes are compiled to Outer$Inner.class.
+	segments := strings.Split(class, ".")
+	for i := len(segments); i >= 1; i-- {
+		name := strings.Join(segments[:i], "/")
+		if i < len(segments) {
+			name += "$" + strings.Join(segments[i:], "$")
+		}
+		if names[name+".class"] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func readEntry(f *zip.File) ([]byte, error) {
+	if f.UncompressedSize64 > maxEntryBytes {
+		return nil, fmt.Errorf("%s is too large (%d bytes)", f.Name, f.UncompressedSize64)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxEntryBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	return data, nil
+}
+
+// Entry is a file or directory of a jar.
+type Entry struct {
+	Name string
+	Dir  bool
+	Size int64
+}
+
+// ListEntries lists the files and directories directly inside dir, a
+// slash-separated path within the jar ("" for its root).
+func ListEntries(jarPath, dir string) ([]Entry, error) {
+	r, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", jarPath, err)
+	}
+	defer r.Close()
+
+	prefix := strings.Trim(dir, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	seen := make(map[string]bool)
+	entries := make([]Entry, 0)
+	for _, f := range r.File {
+		rest, ok := strings.CutPrefix(f.Name, prefix)
+		if !ok || rest == "" {
+			continue
+		}
+		name, _, isDir := strings.Cut(rest, "/")
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		entry := Entry{Name: name, Dir: isDir}
+		if !isDir {
+			entry.Size = int64(f.UncompressedSize64)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 && prefix != "" {
+		return nil, fmt.Errorf("%s has no directory %s", jarPath, dir)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// ReadEntry returns the content of a file of a jar.
+func ReadEntry(jarPath, name string) ([]byte, error) {
+	r, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", jarPath, err)
+	}
+	defer r.Close()
+	name = strings.TrimPrefix(name, "/")
+	for _, f := range r.File {
+		if f.Name == name {
+			return readEntry(f)
+		}
+	}
+	return nil, fmt.Errorf("%s has no file %s", jarPath, name)
+}
@@ -0,0 +1,95 @@
+package jvm_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/jvm"
+)
+
+func writeJar(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	w := zip.NewWriter(f)
+	for name, content := range entries {
+		entry, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = entry.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, f.Close())
+}
+
+func TestSourcesJar(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "okhttp-4.12.0-sources.jar")
+	writeJar(t, jar, map[string]string{
+		"META-INF/MANIFEST.MF":                  "Manifest-Version: 1.0\n",
+		"okhttp3/OkHttpClient.kt":               "package okhttp3\n\nopen class OkHttpClient {\n  class Builder\n}\n",
+		"okhttp3/Calls.kt":                      "package okhttp3\n\ninterface Call\n\nfun interface Callback\n",
+		"okhttp3/internal/Util.kt":              "@file:JvmName(\"Util\")\npackage okhttp3.internal\n",
+		"okhttp3/package-info.java":             "package okhttp3;\n",
+		"okhttp3/internal/http/HttpMethod.java": "package okhttp3.internal.http;\n\npublic final class HttpMethod {\n}\n",
+	})
+
+	packages, err := jvm.ListPackages(jar, true, "")
+	require.NoError(t, err)
+	assert.Equal(t, []jvm.Package{
+		{Name: "okhttp3", Classes: []string{"Calls", "OkHttpClient"}},
+		{Name: "okhttp3.internal", Classes: []string{"Util"}},
+		{Name: "okhttp3.internal.http", Classes: []string{"HttpMethod"}},
+	}, packages)
+
+	packages, err = jvm.ListPackages(jar, true, "okhttp3.internal")
+	require.NoError(t, err)
+	assert.Len(t, packages, 2)
+
+	entry, data, err := jvm.ReadSource(jar, "okhttp3.OkHttpClient.Builder")
+	require.NoError(t, err, "Nested classes resolve to their outer class")
+	assert.Equal(t, "okhttp3/OkHttpClient.kt", entry)
+	assert.Contains(t, string(data), "class Builder")
+
+	entry, _, err = jvm.ReadSource(jar, "okhttp3.Call")
+	require.NoError(t, err, "Kotlin classes are found in files of another name")
+	assert.Equal(t, "okhttp3/Calls.kt", entry)
+
+	entry, _, err = jvm.ReadSource(jar, "okhttp3.internal.UtilKt")
+	require.NoError(t, err)
+	assert.Equal(t, "okhttp3/internal/Util.kt", entry)
+
+	entry, _, err = jvm.ReadSource(jar, "okhttp3.internal.http.HttpMethod")
+	require.NoError(t, err)
+	assert.Equal(t, "okhttp3/internal/http/HttpMethod.java", entry)
+
+	_, _, err = jvm.ReadSource(jar, "okhttp3.Request")
+	assert.ErrorContains(t, err, "no source of okhttp3.Request")
+}
+
+func TestBinaryJar(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "guava-33.2.1-jre.jar")
+	writeJar(t, jar, map[string]string{
+		"module-info.class":                                     "",
+		"com/google/common/collect/ImmutableList.class":         "",
+		"com/google/common/collect/ImmutableList$Builder.class": "",
+		"com/google/common/collect/Lists$1.class":               "",
+		"com/google/common/collect/Lists.class":                 "",
+	})
+
+	packages, err := jvm.ListPackages(jar, false, "")
+	require.NoError(t, err)
+	assert.Equal(t, []jvm.Package{
+		{Name: "com.google.common.collect", Classes: []string{"ImmutableList", "Lists"}},
+	}, packages)
+
+	found, err := jvm.HasClass(jar, "com.google.common.collect.ImmutableList.Builder")
+	require.NoError(t, err)
+	assert.True(t, found)
+	found, err = jvm.HasClass(jar, "com.google.common.collect.Sets")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
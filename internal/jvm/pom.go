@@ -0,0 +1,226 @@
+package jvm
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxParentDepth bounds the parent and BOM chains a pom.xml is resolved
+// through.
+const maxParentDepth = 8
+
+type pomFile struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Parent     struct {
+		GroupID      string  `xml:"groupId"`
+		ArtifactID   string  `xml:"artifactId"`
+		Version      string  `xml:"version"`
+		RelativePath *string `xml:"relativePath"`
+	} `xml:"parent"`
+	Properties struct {
+		Entries []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"properties"`
+	Dependencies []pomDependency `xml:"dependencies>dependency"`
+	Managed      []pomDependency `xml:"dependencyManagement>dependencies>dependency"`
+}
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+	Type       string `xml:"type"`
+}
+
+// effectivePOM is a pom.xml merged with its parents: the properties and
+// managed versions that apply to its dependencies.
+type effectivePOM struct {
+	properties map[string]string
+	managed    map[string]pomDependency
+}
+
+var propertyRE = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+func (p *Project) loadMaven() error {
+	path := filepath.Join(p.Dir, "pom.xml")
+	p.BuildFiles = []string{path}
+	pom, err := readPOM(path)
+	if err != nil {
+		return err
+	}
+	effective := &effectivePOM{properties: make(map[string]string), managed: make(map[string]pomDependency)}
+	p.mergePOM(effective, pom, path, 0)
+
+	for _, declared := range pom.Dependencies {
+		dep := Dependency{
+			Group:    effective.interpolate(declared.GroupID),
+			Artifact: effective.interpolate(declared.ArtifactID),
+			Version:  effective.interpolate(declared.Version),
+			Scope:    declared.Scope,
+		}
+		if managed, ok := effective.managed[dep.Group+":"+dep.Artifact]; ok {
+			if dep.Version == "" {
+				dep.Version = effective.interpolate(managed.Version)
+			}
+			if dep.Scope == "" {
+				dep.Scope = managed.Scope
+			}
+		}
+		if dep.Scope == "" {
+			dep.Scope = "compile"
+		}
+		if dep.Version == "" || strings.Contains(dep.Version, "${") {
+			p.warnings = append(p.warnings, fmt.Sprintf("the version of %s is managed by a parent or BOM that is not on disk; run mvn dependency:resolve", dep.Coordinates()))
+			dep.Version = ""
+		}
+		p.dependencies = append(p.dependencies, dep)
+	}
+	return nil
+}
+
+// mergePOM adds the properties and managed dependencies of pom and its
+// parents to effective, the nearest declaration winning. Parents are read
+// from their relative path or from the Maven repository, and imported BOMs
+// from the repository.
+func (p *Project) mergePOM(effective *effectivePOM, pom *pomFile, path string, depth int) {
+	version := pom.Version
+	if version == "" {
+		version = pom.Parent.Version
+	}
+	group := pom.GroupID
+	if group == "" {
+		group = pom.Parent.GroupID
+	}
+	setDefault(effective.properties, "project.version", version)
+	setDefault(effective.properties, "project.groupId", group)
+	setDefault(effective.properties, "project.artifactId", pom.ArtifactID)
+	setDefault(effective.properties, "project.parent.version", pom.Parent.Version)
+	for _, entry := range pom.Properties.Entries {
+		setDefault(effective.properties, entry.XMLName.Local, strings.TrimSpace(entry.Value))
+	}
+	var imports []pomDependency
+	for _, managed := range pom.Managed {
+		if managed.Scope == "import" && managed.Type == "pom" {
+			imports = append(imports, managed)
+			continue
+		}
+		// Keys are interpolated with the properties known so far, which
+		// include the project coordinates and those declared by this POM
+		// and the POMs inheriting from it.
+		key := effective.managedKey(managed)
+		if _, ok := effective.managed[key]; !ok {
+			effective.managed[key] = managed
+		}
+	}
+	if depth == maxParentDepth {
+		return
+	}
+
+	if pom.Parent.ArtifactID != "" {
+		if parent, parentPath := p.readParent(pom, path); parent != nil {
+			p.mergePOM(effective, parent, parentPath, depth+1)
+		}
+	}
+	// BOMs come after the parent chain, as the properties their versions use
+	// may be declared there.
+	for _, bom := range imports {
+		groupID, artifactID, version := effective.interpolate(bom.GroupID), effective.interpolate(bom.ArtifactID), effective.interpolate(bom.Version)
+		bomPath := p.repositoryPOM(groupID, artifactID, version)
+		imported, err := readPOM(bomPath)
+		if err != nil {
+			continue
+		}
+		// A BOM's own properties only apply to its own versions.
+		scoped := &effectivePOM{properties: make(map[string]string), managed: make(map[string]pomDependency)}
+		p.mergePOM(scoped, imported, bomPath, depth+1)
+		for key, managed := range scoped.managed {
+			if _, ok := effective.managed[key]; !ok {
+				managed.GroupID = scoped.interpolate(managed.GroupID)
+				managed.ArtifactID = scoped.interpolate(managed.ArtifactID)
+				managed.Version = scoped.interpolate(managed.Version)
+				effective.managed[key] = managed
+			}
+		}
+	}
+}
+
+// readParent reads the parent of pom from its relative path, by default
+// ../pom.xml, when it declares the expected coordinates, or else from the
+// Maven repository.
+func (p *Project) readParent(pom *pomFile, path string) (*pomFile, string) {
+	relative := "../pom.xml"
+	if pom.Parent.RelativePath != nil {
+		relative = strings.TrimSpace(*pom.Parent.RelativePath)
+	}
+	if relative != "" {
+		parentPath := filepath.Join(filepath.Dir(path), filepath.FromSlash(relative))
+		if !strings.HasSuffix(parentPath, ".xml") {
+			parentPath = filepath.Join(parentPath, "pom.xml")
+		}
+		if parent, err := readPOM(parentPath); err == nil && parent.ArtifactID == pom.Parent.ArtifactID {
+			return parent, parentPath
+		}
+	}
+	parentPath := p.repositoryPOM(pom.Parent.GroupID, pom.Parent.ArtifactID, pom.Parent.Version)
+	if parent, err := readPOM(parentPath); err == nil {
+		return parent, parentPath
+	}
+	return nil, ""
+}
+
+func (p *Project) repositoryPOM(group, artifact, version string) string {
+	return filepath.Join(p.MavenRepository, filepath.FromSlash(strings.ReplaceAll(group, ".", "/")), artifact, version, artifact+"-"+version+".pom")
+}
+
+func readPOM(path string) (*pomFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var pom pomFile
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &pom, nil
+}
+
+// managedKey returns the group:artifact key of a managed dependency.
+func (e *effectivePOM) managedKey(managed pomDependency) string {
+	return e.interpolate(managed.GroupID) + ":" + e.interpolate(managed.ArtifactID)
+}
+
+// interpolate replaces ${property} references, leaving unknown ones.
+func (e *effectivePOM) interpolate(value string) string {
+	for range maxParentDepth {
+		replaced := propertyRE.ReplaceAllStringFunc(value, func(reference string) string {
+			name := reference[2 : len(reference)-1]
+			if resolved, ok := e.properties[name]; ok {
+				return resolved
+			}
+			if resolved, ok := e.properties["project."+strings.TrimPrefix(name, "pom.")]; ok && strings.HasPrefix(name, "pom.") {
+				return resolved
+			}
+			return reference
+		})
+		if replaced == value {
+			break
+		}
+		value = replaced
+	}
+	return strings.TrimSpace(value)
+}
+
+func setDefault(values map[string]string, key, value string) {
+	if _, ok := values[key]; !ok && value != "" {
+		values[key] = value
+	}
+}
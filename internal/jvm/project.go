@@ -0,0 +1,273 @@
+// Package jvm resolves the dependencies of Java and Kotlin projects through
+// pom.xml or Gradle lockfiles, and reads the -sources.jar files of the
+// Maven and Gradle caches.
+package jvm
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+)
+
+// ErrNoBuildFile is returned when neither a pom.xml nor a Gradle build can
+// be found for a directory.
+var ErrNoBuildFile = errors.New("pom.xml or Gradle build not found")
+
+// Build tools a project's dependencies are read for.
+const (
+	BuildMaven  = "maven"
+	BuildGradle = "gradle"
+)
+
+// gradleBuildFiles mark the directory of a Gradle project.
+var gradleBuildFiles = []string{"build.gradle.kts", "build.gradle", "settings.gradle.kts", "settings.gradle"}
+
+// Dependency is an artifact the project depends on, with the jars found
+// for it in the Maven and Gradle caches.
+type Dependency struct {
+	Group    string `json:"group"`
+	Artifact string `json:"artifact"`
+	Version  string `json:"version,omitempty"`
+	// Scope is the Maven scope, or the Gradle configurations locking the
+	// artifact.
+	Scope string `json:"scope,omitempty"`
+	// SourcesJar and Jar are the paths of the -sources.jar and of the
+	// binary jar, when found.
+	SourcesJar string `json:"sources_jar,omitempty"`
+	Jar        string `json:"jar,omitempty"`
+}
+
+// Coordinates returns group:artifact:version.
+func (d *Dependency) Coordinates() string {
+	if d.Version == "" {
+		return d.Group + ":" + d.Artifact
+	}
+	return d.Group + ":" + d.Artifact + ":" + d.Version
+}
+
+// Project is a Maven or Gradle project on disk.
+type Project struct {
+	Dir   string
+	Build string
+	// BuildFiles are the pom.xml or the Gradle lockfiles the dependencies
+	// come from.
+	BuildFiles []string
+	// MavenRepository and GradleCache are the cache directories searched
+	// for jars.
+	MavenRepository string
+	GradleCache     string
+
+	dependencies []Dependency
+	warnings     []string
+}
+
+// LoadProject finds the pom.xml or Gradle build governing dir, walking up
+// the directory tree, and reads its dependencies: from the pom.xml, or
+// from the lockfiles Gradle writes with dependency locking enabled.
+func LoadProject(dir string) (*Project, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	p := &Project{MavenRepository: mavenRepository(), GradleCache: gradleCache()}
+	for current := absDir; ; {
+		if fsutil.IsFile(filepath.Join(current, "pom.xml")) {
+			p.Dir, p.Build = current, BuildMaven
+			if err := p.loadMaven(); err != nil {
+				return nil, err
+			}
+			break
+		}
+		if isGradleProject(current) {
+			p.Dir, p.Build = current, BuildGradle
+			if err := p.loadGradle(); err != nil {
+				return nil, err
+			}
+			break
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return nil, fmt.Errorf("%w in %s or any parent directory", ErrNoBuildFile, absDir)
+		}
+		current = parent
+	}
+
+	for i := range p.dependencies {
+		p.locate(&p.dependencies[i])
+	}
+	sort.Slice(p.dependencies, func(i, j int) bool {
+		return p.dependencies[i].Coordinates() < p.dependencies[j].Coordinates()
+	})
+	return p, nil
+}
+
+func isGradleProject(dir string) bool {
+	for _, name := range gradleBuildFiles {
+		if fsutil.IsFile(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Warnings reports problems found while loading the project.
+func (p *Project) Warnings() []string {
+	return p.warnings
+}
+
+// Dependencies lists the dependencies, sorted by coordinates.
+func (p *Project) Dependencies() []Dependency {
+	return p.dependencies
+}
+
+// Dependency returns the dependency named group:artifact, or artifact
+// alone when that is unambiguous.
+func (p *Project) Dependency(name string) (*Dependency, error) {
+	var found []*Dependency
+	for i := range p.dependencies {
+		dep := &p.dependencies[i]
+		if name == dep.Group+":"+dep.Artifact || name == dep.Coordinates() || name == dep.Artifact {
+			found = append(found, dep)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("%s is not a dependency of %s", name, p.Dir)
+	case 1:
+		return found[0], nil
+	}
+	names := make([]string, len(found))
+	for i, dep := range found {
+		names[i] = dep.Coordinates()
+	}
+	return nil, fmt.Errorf("%s is ambiguous: %s", name, strings.Join(names, ", "))
+}
+
+// loadGradle reads gradle.lockfile, or the per-configuration lockfiles in
+// gradle/dependency-locks written by Gradle before 6.4.
+func (p *Project) loadGradle() error {
+	lockfiles := []string{filepath.Join(p.Dir, "gradle.lockfile")}
+	legacy, _ := filepath.Glob(filepath.Join(p.Dir, "gradle", "dependency-locks", "*.lockfile"))
+	lockfiles = append(lockfiles, legacy...)
+
+	byCoordinates := make(map[string]*Dependency)
+	for _, lockfile := range lockfiles {
+		f, err := os.Open(lockfile)
+		if err != nil {
+			continue
+		}
+		p.BuildFiles = append(p.BuildFiles, lockfile)
+		// Legacy lockfiles are named after their configuration.
+		configuration := strings.TrimSuffix(filepath.Base(lockfile), ".lockfile")
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "empty=") {
+				continue
+			}
+			coordinates, configurations, ok := strings.Cut(line, "=")
+			if !ok {
+				configurations = configuration
+			}
+			parts := strings.Split(coordinates, ":")
+			if len(parts) != 3 {
+				continue
+			}
+			key := coordinates
+			if dep, ok := byCoordinates[key]; ok {
+				dep.Scope = mergeScopes(dep.Scope, configurations)
+				continue
+			}
+			byCoordinates[key] = &Dependency{Group: parts[0], Artifact: parts[1], Version: parts[2], Scope: configurations}
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read %s: %w", lockfile, err)
+		}
+	}
+	if len(p.BuildFiles) == 0 {
+		return fmt.Errorf("%s has no gradle.lockfile: enable dependency locking (dependencyLocking { lockAllConfigurations() }) and run ./gradlew dependencies --write-locks", p.Dir)
+	}
+	for _, dep := range byCoordinates {
+		p.dependencies = append(p.dependencies, *dep)
+	}
+	return nil
+}
+
+func mergeScopes(a, b string) string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, scope := range strings.Split(a+","+b, ",") {
+		if scope != "" && !seen[scope] {
+			seen[scope] = true
+			merged = append(merged, scope)
+		}
+	}
+	sort.Strings(merged)
+	return strings.Join(merged, ",")
+}
+
+// locate finds the jars of dep in the Maven repository, then in the Gradle
+// cache, which keeps every file in a directory named after its SHA-1.
+func (p *Project) locate(dep *Dependency) {
+	if dep.Version == "" {
+		return
+	}
+	base := dep.Artifact + "-" + dep.Version
+	mavenDir := filepath.Join(p.MavenRepository, filepath.FromSlash(strings.ReplaceAll(dep.Group, ".", "/")), dep.Artifact, dep.Version)
+	gradleDir := filepath.Join(p.GradleCache, dep.Group, dep.Artifact, dep.Version)
+	find := func(name string) string {
+		if path := filepath.Join(mavenDir, name); fsutil.IsFile(path) {
+			return path
+		}
+		matches, _ := filepath.Glob(filepath.Join(gradleDir, "*", name))
+		if len(matches) > 0 {
+			return matches[0]
+		}
+		return ""
+	}
+	dep.SourcesJar = find(base + "-sources.jar")
+	dep.Jar = find(base + ".jar")
+}
+
+// mavenRepository returns the local repository configured in
+// ~/.m2/settings.xml, defaulting to ~/.m2/repository.
+func mavenRepository() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	m2 := filepath.Join(homeDir, ".m2")
+	if data, err := os.ReadFile(filepath.Join(m2, "settings.xml")); err == nil {
+		var settings struct {
+			LocalRepository string `xml:"localRepository"`
+		}
+		if xml.Unmarshal(data, &settings) == nil && settings.LocalRepository != "" {
+			repository := strings.TrimSpace(settings.LocalRepository)
+			repository = strings.ReplaceAll(repository, "${user.home}", homeDir)
+			return repository
+		}
+	}
+	return filepath.Join(m2, "repository")
+}
+
+// gradleCache returns the modules cache of GRADLE_USER_HOME, defaulting
+// to ~/.gradle.
+func gradleCache() string {
+	home := os.Getenv("GRADLE_USER_HOME")
+	if home == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		home = filepath.Join(homeDir, ".gradle")
+	}
+	return filepath.Join(home, "caches", "modules-2", "files-2.1")
+}
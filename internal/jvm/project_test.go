@@ -0,0 +1,199 @@
+package jvm_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/jvm"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+// setHome points the Maven repository and the Gradle cache into dir.
+func setHome(t *testing.T, dir string) {
+	t.Helper()
+	t.Setenv("HOME", dir)
+	t.Setenv("GRADLE_USER_HOME", filepath.Join(dir, "gradle-home"))
+}
+
+func TestLoadProjectMaven(t *testing.T) {
+	tmpDir := t.TempDir()
+	setHome(t, tmpDir)
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"app/pom.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>service</artifactId>
+  <properties>
+    <guava.version>33.2.1-jre</guava.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>${guava.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>org.junit.jupiter</groupId>
+      <artifactId>junit-jupiter</artifactId>
+      <scope>test</scope>
+    </dependency>
+    <dependency>
+      <groupId>com.example</groupId>
+      <artifactId>common</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>com.example</groupId>
+      <artifactId>util</artifactId>
+    </dependency>
+  </dependencies>
+</project>
+`,
+		"pom.xml": `<project>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <packaging>pom</packaging>
+  <properties>
+    <slf4j.version>2.0.13</slf4j.version>
+    <guava.version>32.0.0-jre</guava.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.slf4j</groupId>
+        <artifactId>slf4j-api</artifactId>
+        <version>${slf4j.version}</version>
+      </dependency>
+      <dependency>
+        <groupId>${project.groupId}</groupId>
+        <artifactId>util</artifactId>
+        <version>2.0.0</version>
+      </dependency>
+      <dependency>
+        <groupId>com.fasterxml.jackson</groupId>
+        <artifactId>jackson-bom</artifactId>
+        <version>2.17.1</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>
+`,
+		".m2/repository/com/fasterxml/jackson/jackson-bom/2.17.1/jackson-bom-2.17.1.pom": `<project>
+  <groupId>com.fasterxml.jackson</groupId>
+  <artifactId>jackson-bom</artifactId>
+  <version>2.17.1</version>
+  <properties>
+    <jackson.version.databind>${project.version}</jackson.version.databind>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>${project.groupId}.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>${jackson.version.databind}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>
+`,
+		".m2/repository/com/google/guava/guava/33.2.1-jre/guava-33.2.1-jre.jar":         "",
+		".m2/repository/com/google/guava/guava/33.2.1-jre/guava-33.2.1-jre-sources.jar": "",
+		".m2/repository/org/slf4j/slf4j-api/2.0.13/slf4j-api-2.0.13.jar":                "",
+	})
+
+	project, err := jvm.LoadProject(filepath.Join(tmpDir, "app"))
+	require.NoError(t, err)
+	assert.Equal(t, jvm.BuildMaven, project.Build)
+	assert.Equal(t, []string{filepath.Join(tmpDir, "app", "pom.xml")}, project.BuildFiles)
+	assert.Equal(t, filepath.Join(tmpDir, ".m2", "repository"), project.MavenRepository)
+
+	byArtifact := make(map[string]jvm.Dependency)
+	for _, dep := range project.Dependencies() {
+		byArtifact[dep.Artifact] = dep
+	}
+	require.Len(t, byArtifact, 6)
+
+	guava := byArtifact["guava"]
+	assert.Equal(t, "33.2.1-jre", guava.Version, "The project's properties override its parent's")
+	assert.Equal(t, "compile", guava.Scope)
+	repository := filepath.Join(tmpDir, ".m2", "repository", "com", "google", "guava", "guava", "33.2.1-jre")
+	assert.Equal(t, filepath.Join(repository, "guava-33.2.1-jre-sources.jar"), guava.SourcesJar)
+	assert.Equal(t, filepath.Join(repository, "guava-33.2.1-jre.jar"), guava.Jar)
+
+	assert.Equal(t, "2.0.13", byArtifact["slf4j-api"].Version, "Versions are managed by the parent")
+	assert.Empty(t, byArtifact["slf4j-api"].SourcesJar)
+	assert.NotEmpty(t, byArtifact["slf4j-api"].Jar)
+	assert.Equal(t, "2.17.1", byArtifact["jackson-databind"].Version, "Versions are managed by imported BOMs")
+	assert.Equal(t, "1.0.0", byArtifact["common"].Version)
+	assert.Equal(t, "2.0.0", byArtifact["util"].Version, "Managed coordinates are interpolated")
+	assert.Equal(t, "test", byArtifact["junit-jupiter"].Scope)
+	assert.Empty(t, byArtifact["junit-jupiter"].Version)
+	require.Len(t, project.Warnings(), 1)
+	assert.Contains(t, project.Warnings()[0], "org.junit.jupiter:junit-jupiter")
+
+	dep, err := project.Dependency("com.google.guava:guava")
+	require.NoError(t, err)
+	assert.Equal(t, "33.2.1-jre", dep.Version)
+	_, err = project.Dependency("slf4j-api")
+	assert.NoError(t, err)
+	_, err = project.Dependency("commons-lang3")
+	assert.ErrorContains(t, err, "not a dependency")
+}
+
+func TestLoadProjectGradle(t *testing.T) {
+	tmpDir := t.TempDir()
+	setHome(t, tmpDir)
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"app/settings.gradle.kts":    `rootProject.name = "app"`,
+		"app/build.gradle.kts":       `dependencies { implementation("com.squareup.okhttp3:okhttp:4.+") }`,
+		"app/src/main/kotlin/App.kt": "fun main() {}\n",
+		"app/gradle.lockfile": `# This is a Gradle generated file for dependency locking.
+# Manual edits can break the build and are not advised.
+# This file is expected to be part of source control.
+com.squareup.okhttp3:okhttp:4.12.0=compileClasspath,runtimeClasspath
+com.squareup.okio:okio:3.6.0=runtimeClasspath
+empty=annotationProcessor
+`,
+		"gradle-home/caches/modules-2/files-2.1/com.squareup.okhttp3/okhttp/4.12.0/0a1b2c/okhttp-4.12.0-sources.jar": "",
+		"gradle-home/caches/modules-2/files-2.1/com.squareup.okhttp3/okhttp/4.12.0/3d4e5f/okhttp-4.12.0.jar":         "",
+	})
+
+	project, err := jvm.LoadProject(filepath.Join(tmpDir, "app", "src", "main", "kotlin"))
+	require.NoError(t, err)
+	assert.Equal(t, jvm.BuildGradle, project.Build)
+	assert.Equal(t, filepath.Join(tmpDir, "app"), project.Dir)
+
+	deps := project.Dependencies()
+	require.Len(t, deps, 2)
+	assert.Equal(t, "com.squareup.okhttp3:okhttp:4.12.0", deps[0].Coordinates())
+	assert.Equal(t, "compileClasspath,runtimeClasspath", deps[0].Scope)
+	cache := filepath.Join(tmpDir, "gradle-home", "caches", "modules-2", "files-2.1", "com.squareup.okhttp3", "okhttp", "4.12.0")
+	assert.Equal(t, filepath.Join(cache, "0a1b2c", "okhttp-4.12.0-sources.jar"), deps[0].SourcesJar)
+	assert.Equal(t, filepath.Join(cache, "3d4e5f", "okhttp-4.12.0.jar"), deps[0].Jar)
+	assert.Empty(t, deps[1].Jar)
+
+	testutil.WriteFiles(t, tmpDir, map[string]string{"unlocked/build.gradle": ""})
+	_, err = jvm.LoadProject(filepath.Join(tmpDir, "unlocked"))
+	assert.ErrorContains(t, err, "--write-locks")
+
+	_, err = jvm.LoadProject(t.TempDir())
+	assert.ErrorIs(t, err, jvm.ErrNoBuildFile)
+}
@@ -0,0 +1,124 @@
+package licenses
+
+import (
+	"embed"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:embed data/*.txt
+var licenseData embed.FS
+
+const (
+	// ngramSize is the number of consecutive words compared between texts.
+	ngramSize = 3
+	// minConfidence is the share of a license's text that must be present
+	// in a file for the license to be reported.
+	minConfidence = 0.8
+)
+
+var (
+	// copyrightRE matches copyright lines, which differ between projects.
+	copyrightRE = regexp.MustCompile(`(?m)^\s*(copyright\b|\(c\)|©).*$|all rights reserved`)
+	wordRE      = regexp.MustCompile(`[a-z0-9]+`)
+)
+
+// Match is a license detected in a text.
+type Match struct {
+	SPDX string `json:"spdx"`
+	// Confidence is the share of the license text found, from 0 to 1.
+	Confidence float64 `json:"confidence"`
+}
+
+type license struct {
+	spdx   string
+	ngrams map[string]bool
+}
+
+var knownLicenses = loadLicenses()
+
+func loadLicenses() []license {
+	entries, err := licenseData.ReadDir("data")
+	if err != nil {
+		panic(err)
+	}
+	licenses := make([]license, 0, len(entries))
+	for _, entry := range entries {
+		data, err := licenseData.ReadFile(path.Join("data", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		licenses = append(licenses, license{
+			spdx:   strings.TrimSuffix(entry.Name(), ".txt"),
+			ngrams: ngrams(string(data)),
+		})
+	}
+	return licenses
+}
+
+// KnownLicenses returns the SPDX identifiers of the bundled license texts.
+func KnownLicenses() []string {
+	ids := make([]string, 0, len(knownLicenses))
+	for _, l := range knownLicenses {
+		ids = append(ids, l.spdx)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Classify returns the bundled licenses whose text appears in text, best
+// match first. A file holding several licenses yields several matches; the
+// text a match explains is not used again, so a license contained in another
+// (BSD-2-Clause in BSD-3-Clause) is not reported twice.
+func Classify(text string) []Match {
+	remaining := ngrams(text)
+	matches := make([]Match, 0)
+
+	for len(remaining) > 0 {
+		var best *license
+		var bestScore, bestRecall float64
+		for i := range knownLicenses {
+			l := &knownLicenses[i]
+			common := 0
+			for gram := range l.ngrams {
+				if remaining[gram] {
+					common++
+				}
+			}
+			recall := float64(common) / float64(len(l.ngrams))
+			precision := float64(common) / float64(len(remaining))
+			if recall < minConfidence {
+				continue
+			}
+			// Prefer the license that also explains most of the file.
+			score := 2 * recall * precision / (recall + precision)
+			if score > bestScore {
+				best, bestScore, bestRecall = l, score, recall
+			}
+		}
+		if best == nil {
+			break
+		}
+
+		matches = append(matches, Match{SPDX: best.spdx, Confidence: float64(int(bestRecall*100)) / 100})
+		for gram := range best.ngrams {
+			delete(remaining, gram)
+		}
+	}
+	return matches
+}
+
+// ngrams returns the set of word n-grams of text, ignoring case,
+// punctuation and copyright lines. The bundled templates keep their
+// placeholders, such as "<year>", on copyright lines only.
+func ngrams(text string) map[string]bool {
+	text = copyrightRE.ReplaceAllString(strings.ToLower(text), " ")
+	words := wordRE.FindAllString(text, -1)
+	grams := make(map[string]bool, len(words))
+	for i := 0; i+ngramSize <= len(words); i++ {
+		grams[strings.Join(words[i:i+ngramSize], " ")] = true
+	}
+	return grams
+}
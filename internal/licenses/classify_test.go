@@ -0,0 +1,46 @@
+package licenses
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bundledText(t *testing.T, spdx string) string {
+	data, err := licenseData.ReadFile("data/" + spdx + ".txt")
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestClassifyBundledLicenses(t *testing.T) {
+	for _, spdx := range KnownLicenses() {
+		matches := Classify(bundledText(t, spdx))
+		require.NotEmpty(t, matches, spdx)
+		assert.Equal(t, spdx, matches[0].SPDX)
+		assert.Equal(t, 1.0, matches[0].Confidence, spdx)
+	}
+}
+
+func TestClassifyFilledTemplate(t *testing.T) {
+	text := strings.ReplaceAll(bundledText(t, "BSD-3-Clause"), "Copyright (c) <year> <owner>", "Copyright (c) 2009 The Go Authors. All rights reserved.")
+	text = strings.ReplaceAll(text, "the copyright holder nor", "Google Inc. nor")
+
+	matches := Classify(text)
+	require.Len(t, matches, 1, "BSD-2-Clause is contained in BSD-3-Clause and should not be reported")
+	assert.Equal(t, "BSD-3-Clause", matches[0].SPDX)
+	assert.GreaterOrEqual(t, matches[0].Confidence, 0.9)
+}
+
+func TestClassifyMultipleLicenses(t *testing.T) {
+	matches := Classify(bundledText(t, "Apache-2.0") + "\n\n" + bundledText(t, "MIT"))
+	require.Len(t, matches, 2)
+	assert.Equal(t, "Apache-2.0", matches[0].SPDX)
+	assert.Equal(t, "MIT", matches[1].SPDX)
+}
+
+func TestClassifyUnknown(t *testing.T) {
+	assert.Empty(t, Classify("All code here is proprietary. Do not copy."))
+	assert.Empty(t, Classify(""))
+}
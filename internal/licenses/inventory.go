@@ -0,0 +1,111 @@
+package licenses
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+)
+
+const (
+	// SPDXNone marks a module without a license file.
+	SPDXNone = "NONE"
+	// SPDXUnknown marks a license file matching none of the bundled texts.
+	SPDXUnknown = "NOASSERTION"
+)
+
+// licenseFilePrefixes are the lower-cased name prefixes of license files.
+var licenseFilePrefixes = []string{"license", "licence", "copying", "unlicense"}
+
+// Entry is one detected license of a module. A module with several license
+// files, or a file with several licenses, has one entry per license.
+type Entry struct {
+	Module     string  `json:"module"`
+	Version    string  `json:"version"`
+	SPDX       string  `json:"spdx"`
+	Confidence float64 `json:"confidence"`
+	File       string  `json:"file,omitempty"`
+}
+
+// Inventory detects the licenses of every module the project requires, as
+// resolved in the module cache or vendor directory. Modules whose sources
+// are missing are reported as warnings.
+func Inventory(project *gomod.Project) ([]Entry, []string, error) {
+	entries := make([]Entry, 0)
+	var warnings []string
+
+	for _, mod := range project.Modules() {
+		if mod.Main {
+			continue
+		}
+		files, err := findLicenseFiles(mod.Dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				warnings = append(warnings, fmt.Sprintf("sources of %s@%s not found in %s", mod.Path, mod.Version, mod.Dir))
+				continue
+			}
+			return nil, nil, err
+		}
+		if len(files) == 0 {
+			entries = append(entries, Entry{Module: mod.Path, Version: mod.Version, SPDX: SPDXNone})
+			continue
+		}
+
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read license file: %w", err)
+			}
+			matches := Classify(string(data))
+			if len(matches) == 0 {
+				matches = []Match{{SPDX: SPDXUnknown}}
+			}
+			for _, match := range matches {
+				entries = append(entries, Entry{
+					Module:     mod.Path,
+					Version:    mod.Version,
+					SPDX:       match.SPDX,
+					Confidence: match.Confidence,
+					File:       file,
+				})
+			}
+		}
+	}
+	return entries, warnings, nil
+}
+
+// findLicenseFiles returns the license files in the root of a module.
+func findLicenseFiles(dir string) ([]string, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range dirEntries {
+		if entry.IsDir() {
+			continue
+		}
+		name := strings.ToLower(entry.Name())
+		for _, prefix := range licenseFilePrefixes {
+			if strings.HasPrefix(name, prefix) {
+				files = append(files, filepath.Join(dir, entry.Name()))
+				break
+			}
+		}
+	}
+	return files, nil
+}
+
+// WriteTable writes entries as an aligned text table.
+func WriteTable(w io.Writer, entries []Entry) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODULE\tVERSION\tSPDX\tCONFIDENCE\tFILE")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%.2f\t%s\n", e.Module, e.Version, e.SPDX, e.Confidence, e.File)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,77 @@
+package licenses_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+	"github.com/svetlyi/mcp-local-context/internal/licenses"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+const mitText = `Copyright (c) 2024 Someone
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+`
+
+func TestInventory(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"app/go.mod": `module example.com/app
+
+require (
+	example.com/mit v1.0.0
+	example.com/nolicense v1.0.0
+	example.com/custom v1.0.0
+	example.com/missing v1.0.0
+)
+`,
+		"modcache/example.com/mit@v1.0.0/LICENSE.md":       mitText,
+		"modcache/example.com/nolicense@v1.0.0/go.mod":     "module example.com/nolicense\n",
+		"modcache/example.com/custom@v1.0.0/COPYING":       "You may use this code on Tuesdays only.",
+		"modcache/example.com/custom@v1.0.0/license/x.txt": "not a license file",
+	}
+	testutil.WriteFiles(t, tmpDir, files)
+
+	project, err := gomod.LoadProject(filepath.Join(tmpDir, "app"), filepath.Join(tmpDir, "modcache"))
+	require.NoError(t, err)
+
+	entries, warnings, err := licenses.Inventory(project)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, "example.com/mit", entries[0].Module)
+	assert.Equal(t, "MIT", entries[0].SPDX)
+	assert.Equal(t, 1.0, entries[0].Confidence)
+	assert.Equal(t, filepath.Join(tmpDir, "modcache", "example.com", "mit@v1.0.0", "LICENSE.md"), entries[0].File)
+
+	assert.Equal(t, licenses.SPDXNone, entries[1].SPDX)
+	assert.Equal(t, licenses.SPDXUnknown, entries[2].SPDX)
+
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "example.com/missing@v1.0.0")
+
+	var table bytes.Buffer
+	require.NoError(t, licenses.WriteTable(&table, entries))
+	assert.Contains(t, table.String(), "MODULE")
+	assert.Contains(t, table.String(), "example.com/mit")
+}
@@ -0,0 +1,175 @@
+package moddocs
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// atxHeadingRE matches a Markdown heading such as "## v1.2.3", whose
+// opening #s must be followed by a space unlike in "#123 fixed".
+var atxHeadingRE = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?$`)
+
+// versionRE finds a version number such as "v1.2.3", "1.2" or
+// "2.0.0-rc.1" inside a heading.
+var versionRE = regexp.MustCompile(`(?:^|[^\w.])v?(\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z.-]+)?)(?:$|[^\w])`)
+
+// Section is the part of a changelog below one version heading.
+type Section struct {
+	Version string `json:"version"`
+	Heading string `json:"heading"`
+	Content string `json:"content"`
+}
+
+// ParseChangelog splits a changelog into sections at headings that name a
+// version. Markdown ("## v1.2.3"), setext and reStructuredText underlined
+// headings are recognized. Text before the first version heading, and
+// sections under headings without a version such as "Unreleased", are
+// dropped.
+func ParseChangelog(content string) []Section {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	p := &headingParser{underlineLevels: make(map[byte]int)}
+
+	var sections []Section
+	var current *Section
+	var body []string
+	versionLevel := 0
+
+	flush := func() {
+		if current != nil {
+			current.Content = strings.TrimSpace(strings.Join(body, "\n"))
+			sections = append(sections, *current)
+		}
+		current = nil
+		body = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		heading, level, consumed := p.headingAt(lines, i)
+		if heading == "" {
+			if current != nil {
+				body = append(body, line)
+			}
+			continue
+		}
+
+		if version := headingVersion(heading); version != "" {
+			flush()
+			if versionLevel == 0 {
+				versionLevel = level
+			}
+			current = &Section{Version: version, Heading: heading}
+		} else if current != nil {
+			if level <= versionLevel {
+				flush()
+			} else {
+				body = append(body, lines[i:i+consumed]...)
+			}
+		}
+		i += consumed - 1
+	}
+	flush()
+
+	return sections
+}
+
+// SliceChangelog returns the sections newer than from and up to and
+// including to. Either bound may be empty to leave that side open.
+func SliceChangelog(sections []Section, from, to string) []Section {
+	result := make([]Section, 0)
+	for _, section := range sections {
+		if from != "" && semver.Compare(section.Version, from) <= 0 {
+			continue
+		}
+		if to != "" && semver.Compare(section.Version, to) > 0 {
+			continue
+		}
+		result = append(result, section)
+	}
+	return result
+}
+
+// LimitSections keeps the sections whose headings and contents fit in
+// maxBytes, cutting the content of the first one that does not fit, and
+// reports whether any content was left out.
+func LimitSections(sections []Section, maxBytes int) ([]Section, bool) {
+	result := make([]Section, 0, len(sections))
+	remaining := maxBytes
+	for _, section := range sections {
+		size := len(section.Heading) + len(section.Content)
+		if size <= remaining {
+			result = append(result, section)
+			remaining -= size
+			continue
+		}
+		if keep := remaining - len(section.Heading); keep > 0 {
+			section.Content = strings.ToValidUTF8(section.Content[:keep], "")
+			result = append(result, section)
+		}
+		return result, true
+	}
+	return result, false
+}
+
+// headingParser recognizes headings and assigns them a nesting level.
+// Underlined headings get their level from the order in which underline
+// characters first appear, as in reStructuredText.
+type headingParser struct {
+	underlineLevels map[byte]int
+	inFence         bool
+}
+
+// headingAt returns the heading text starting at line i, its level and how
+// many lines it spans, or "" if line i does not start a heading. Lines
+// must be passed in order so that fenced code blocks are skipped.
+func (p *headingParser) headingAt(lines []string, i int) (string, int, int) {
+	line := strings.TrimSpace(lines[i])
+	if strings.HasPrefix(line, "```") {
+		p.inFence = !p.inFence
+		return "", 0, 1
+	}
+	if line == "" || p.inFence {
+		return "", 0, 1
+	}
+	if match := atxHeadingRE.FindStringSubmatch(line); match != nil {
+		return match[2], len(match[1]), 1
+	}
+	if strings.HasPrefix(line, "#") {
+		return "", 0, 1
+	}
+	if i+1 < len(lines) {
+		next := strings.TrimSpace(lines[i+1])
+		if isUnderline(next) {
+			level, ok := p.underlineLevels[next[0]]
+			if !ok {
+				level = len(p.underlineLevels) + 1
+				p.underlineLevels[next[0]] = level
+			}
+			return line, level, 2
+		}
+	}
+	return "", 0, 1
+}
+
+func isUnderline(line string) bool {
+	if len(line) < 3 {
+		return false
+	}
+	for i := 1; i < len(line); i++ {
+		if line[i] != line[0] {
+			return false
+		}
+	}
+	return strings.IndexByte("=-~^*+#", line[0]) >= 0
+}
+
+// headingVersion returns the canonical semantic version named by a heading.
+func headingVersion(heading string) string {
+	match := versionRE.FindStringSubmatch(heading)
+	if match == nil {
+		return ""
+	}
+	return semver.Canonical("v" + match[1])
+}
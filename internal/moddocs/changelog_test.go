@@ -0,0 +1,106 @@
+package moddocs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/moddocs"
+)
+
+const markdownChangelog = `# Changelog
+
+## [Unreleased]
+- Work in progress
+
+## [1.3.0] - 2024-03-01
+### Added
+- New API
+
+` + "```sh\n# not a heading 9.9.9\n```" + `
+
+## v1.2.1 ##
+- Fix
+#42 is fixed in 2.0.0
+####### 8.0.0 is not a heading either
+
+## 1.2
+- Initial release
+`
+
+func TestParseChangelogMarkdown(t *testing.T) {
+	sections := moddocs.ParseChangelog(markdownChangelog)
+	require.Len(t, sections, 3)
+
+	assert.Equal(t, "v1.3.0", sections[0].Version)
+	assert.Equal(t, "[1.3.0] - 2024-03-01", sections[0].Heading)
+	assert.Contains(t, sections[0].Content, "### Added")
+	assert.Contains(t, sections[0].Content, "# not a heading 9.9.9", "Code blocks should stay in the section")
+	assert.NotContains(t, sections[0].Content, "Work in progress")
+
+	assert.Equal(t, "v1.2.1", sections[1].Version)
+	assert.Equal(t, "v1.2.1", sections[1].Heading, "Closing #s are not part of the heading")
+	assert.Equal(t, "- Fix\n#42 is fixed in 2.0.0\n####### 8.0.0 is not a heading either", sections[1].Content, "Headings need one to six #s and a space")
+	assert.Equal(t, "v1.2.0", sections[2].Version)
+}
+
+func TestParseChangelogUnderlined(t *testing.T) {
+	sections := moddocs.ParseChangelog(`Release History
+===============
+
+2.0.0rc1 (2024-01-01)
+---------------------
+
+Features
+~~~~~~~~
+- Breaking change
+
+1.9.0 (2023-06-01)
+------------------
+- Older change
+`)
+	require.Len(t, sections, 2)
+	assert.Equal(t, "v1.9.0", sections[1].Version)
+	assert.Contains(t, sections[0].Content, "Features")
+	assert.Contains(t, sections[0].Content, "Breaking change")
+}
+
+func TestSliceChangelog(t *testing.T) {
+	sections := moddocs.ParseChangelog(markdownChangelog)
+
+	sliced := moddocs.SliceChangelog(sections, "v1.2.0", "v1.2.1")
+	require.Len(t, sliced, 1)
+	assert.Equal(t, "v1.2.1", sliced[0].Version)
+
+	sliced = moddocs.SliceChangelog(sections, "v1.2.0", "")
+	assert.Len(t, sliced, 2)
+
+	sliced = moddocs.SliceChangelog(sections, "v1.3.0", "v2.0.0")
+	assert.Empty(t, sliced)
+}
+
+func TestLimitSections(t *testing.T) {
+	sections := []moddocs.Section{
+		{Version: "v1.2.0", Heading: "v1.2.0", Content: "0123456789"},
+		{Version: "v1.1.0", Heading: "v1.1.0", Content: "0123456789"},
+		{Version: "v1.0.0", Heading: "v1.0.0", Content: "0123456789"},
+	}
+
+	limited, truncated := moddocs.LimitSections(sections, 100)
+	assert.False(t, truncated)
+	assert.Equal(t, sections, limited)
+
+	limited, truncated = moddocs.LimitSections(sections, 42)
+	assert.True(t, truncated)
+	require.Len(t, limited, 3)
+	assert.Equal(t, "0123", limited[2].Content, "The section that overflows the budget is cut")
+	assert.Equal(t, "0123456789", sections[2].Content)
+
+	limited, truncated = moddocs.LimitSections(sections, 20)
+	assert.True(t, truncated)
+	require.Len(t, limited, 1, "Sections whose heading does not fit are dropped")
+
+	limited, truncated = moddocs.LimitSections([]moddocs.Section{{Heading: "v1", Content: "ééé"}}, 5)
+	assert.True(t, truncated)
+	assert.Equal(t, "é", limited[0].Content, "Content is not cut inside a UTF-8 sequence")
+}
@@ -0,0 +1,117 @@
+package moddocs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Kind classifies a documentation file.
+type Kind string
+
+const (
+	KindReadme    Kind = "readme"
+	KindChangelog Kind = "changelog"
+	KindMigration Kind = "migration"
+	KindUpgrading Kind = "upgrading"
+)
+
+// Kinds lists every kind of documentation file.
+var Kinds = []Kind{KindReadme, KindChangelog, KindMigration, KindUpgrading}
+
+// ParseKind returns the kind named s.
+func ParseKind(s string) (Kind, error) {
+	for _, kind := range Kinds {
+		if string(kind) == s {
+			return kind, nil
+		}
+	}
+	names := make([]string, len(Kinds))
+	for i, kind := range Kinds {
+		names[i] = string(kind)
+	}
+	return "", fmt.Errorf("unknown document kind %q: expected one of %s", s, strings.Join(names, ", "))
+}
+
+// docNames maps lower-cased base names, without extension, to their kind.
+var docNames = map[string]Kind{
+	"readme":          KindReadme,
+	"changelog":       KindChangelog,
+	"changes":         KindChangelog,
+	"history":         KindChangelog,
+	"news":            KindChangelog,
+	"release-notes":   KindChangelog,
+	"release_notes":   KindChangelog,
+	"releasenotes":    KindChangelog,
+	"migration":       KindMigration,
+	"migrating":       KindMigration,
+	"migration-guide": KindMigration,
+	"migration_guide": KindMigration,
+	"upgrading":       KindUpgrading,
+	"upgrade":         KindUpgrading,
+}
+
+// docExtensions lists the extensions documentation files commonly use.
+var docExtensions = map[string]bool{
+	"":          true,
+	".md":       true,
+	".markdown": true,
+	".txt":      true,
+	".rst":      true,
+	".adoc":     true,
+	".asciidoc": true,
+	".org":      true,
+}
+
+// docSubdirs are searched in addition to the module root.
+var docSubdirs = []string{"", "doc", "docs"}
+
+// DocFile is a documentation file found in a module.
+type DocFile struct {
+	Kind Kind   `json:"kind"`
+	Path string `json:"path"`
+}
+
+// FindDocs returns the README, CHANGELOG, MIGRATION and UPGRADING files of
+// the module in dir, matched case-insensitively with any common extension.
+// Paths are relative to dir, and files in the module root come first.
+func FindDocs(dir string) ([]DocFile, error) {
+	docs := make([]DocFile, 0)
+	for _, subdir := range docSubdirs {
+		entries, err := os.ReadDir(filepath.Join(dir, subdir))
+		if err != nil {
+			if os.IsNotExist(err) && subdir != "" {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read module directory: %w", err)
+		}
+
+		var found []DocFile
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if kind, ok := classify(entry.Name()); ok {
+				found = append(found, DocFile{Kind: kind, Path: filepath.Join(subdir, entry.Name())})
+			}
+		}
+		sort.Slice(found, func(i, j int) bool {
+			return found[i].Path < found[j].Path
+		})
+		docs = append(docs, found...)
+	}
+	return docs, nil
+}
+
+// classify returns the kind of a documentation file name.
+func classify(name string) (Kind, bool) {
+	lower := strings.ToLower(name)
+	ext := filepath.Ext(lower)
+	if !docExtensions[ext] {
+		return "", false
+	}
+	kind, ok := docNames[strings.TrimSuffix(lower, ext)]
+	return kind, ok
+}
@@ -0,0 +1,40 @@
+package moddocs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/moddocs"
+)
+
+func TestFindDocs(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"README.md", "ChangeLog", "CHANGES.rst", "upgrading.MD", "main.go", "LICENSE", "docs/MIGRATION.markdown", "docs/readme.go"} {
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("content"), 0644))
+	}
+
+	docs, err := moddocs.FindDocs(tmpDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []moddocs.DocFile{
+		{Kind: moddocs.KindChangelog, Path: "CHANGES.rst"},
+		{Kind: moddocs.KindChangelog, Path: "ChangeLog"},
+		{Kind: moddocs.KindReadme, Path: "README.md"},
+		{Kind: moddocs.KindUpgrading, Path: "upgrading.MD"},
+		{Kind: moddocs.KindMigration, Path: filepath.Join("docs", "MIGRATION.markdown")},
+	}, docs)
+}
+
+func TestParseKind(t *testing.T) {
+	kind, err := moddocs.ParseKind("changelog")
+	require.NoError(t, err)
+	assert.Equal(t, moddocs.KindChangelog, kind)
+
+	_, err = moddocs.ParseKind("changes")
+	assert.EqualError(t, err, `unknown document kind "changes": expected one of readme, changelog, migration, upgrading`)
+}
@@ -0,0 +1,250 @@
+// Package modsearch searches the files of a single module directory.
+package modsearch
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/generated"
+)
+
+const (
+	// maxFileSize skips files too large to be source code.
+	maxFileSize = 4 << 20
+	// maxLineLength truncates long lines, such as minified data, in results.
+	maxLineLength = 500
+	// binarySniffLen is how much of a file is checked for NUL bytes.
+	binarySniffLen = 8000
+)
+
+// Options configures Grep.
+type Options struct {
+	Pattern *regexp.Regexp
+	// Subdir restricts the search to a directory of the module, such as a
+	// package directory.
+	Subdir string
+	// Glob restricts the search to files matching a pattern. Patterns with
+	// a slash match the path relative to the module root, others the base
+	// name.
+	Glob string
+	// MaxResults stops the search after this many matches.
+	MaxResults int
+	// Context is the number of lines returned around each match.
+	Context int
+	// Generated selects how generated files are searched.
+	Generated generated.Mode
+}
+
+// Match is a line matching the pattern.
+type Match struct {
+	File      string   `json:"file"`
+	Line      int      `json:"line"`
+	Column    int      `json:"column"`
+	Text      string   `json:"text"`
+	Before    []string `json:"before,omitempty"`
+	After     []string `json:"after,omitempty"`
+	Generated bool     `json:"generated,omitempty"`
+	Proto     string   `json:"proto,omitempty"`
+}
+
+// Result holds the matches of a search.
+type Result struct {
+	Matches      []Match `json:"matches"`
+	FilesScanned int     `json:"files_scanned"`
+	// Truncated reports that the search stopped at MaxResults.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ValidateGlob reports whether a file glob is well formed.
+func ValidateGlob(glob string) error {
+	if _, err := path.Match(glob, ""); err != nil {
+		return fmt.Errorf("invalid glob %q: %w", glob, err)
+	}
+	return nil
+}
+
+// Grep searches the files of the module in root line by line. Nested
+// modules, version control directories, binary files and files larger than
+// 4 MB are skipped. Paths in the result are relative to root.
+func Grep(root string, opts Options) (*Result, error) {
+	dir := root
+	if opts.Subdir != "" {
+		subdir := filepath.Clean(filepath.FromSlash(opts.Subdir))
+		if !filepath.IsLocal(subdir) {
+			return nil, fmt.Errorf("invalid directory %q: must be relative to the module root", opts.Subdir)
+		}
+		dir = filepath.Join(root, subdir)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("directory %s does not exist in the module", opts.Subdir)
+		}
+	}
+	if opts.Glob != "" {
+		if err := ValidateGlob(opts.Glob); err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := listFiles(root, dir, opts.Glob)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Matches: make([]Match, 0)}
+	classifier := generated.NewClassifier()
+	var deferred []string
+	for _, file := range files {
+		isGenerated := classifier.IsGenerated(file, root)
+		switch {
+		case opts.Generated == generated.Exclude && isGenerated, opts.Generated == generated.Only && !isGenerated:
+			continue
+		case opts.Generated == generated.Last && isGenerated:
+			deferred = append(deferred, file)
+			continue
+		}
+		if err := grepFile(root, file, classifier, opts, result); err != nil {
+			return nil, err
+		}
+		if result.Truncated {
+			return result, nil
+		}
+	}
+	for _, file := range deferred {
+		if err := grepFile(root, file, classifier, opts, result); err != nil {
+			return nil, err
+		}
+		if result.Truncated {
+			break
+		}
+	}
+	return result, nil
+}
+
+// listFiles returns the files below dir matching glob, in lexical order.
+func listFiles(root, dir, glob string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if file == dir {
+				return nil
+			}
+			if d.Name() == ".git" || d.Name() == ".hg" || d.Name() == ".svn" {
+				return filepath.SkipDir
+			}
+			// A nested module is a different module.
+			if _, err := os.Stat(filepath.Join(file, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if glob != "" {
+			rel, err := filepath.Rel(root, file)
+			if err != nil {
+				return err
+			}
+			name := d.Name()
+			if strings.Contains(glob, "/") {
+				name = filepath.ToSlash(rel)
+			}
+			if ok, _ := path.Match(glob, name); !ok {
+				return nil
+			}
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list module files: %w", err)
+	}
+	return files, nil
+}
+
+func grepFile(root, file string, classifier *generated.Classifier, opts Options, result *Result) error {
+	info, err := os.Stat(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if info.Size() > maxFileSize {
+		return nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if bytes.IndexByte(data[:min(len(data), binarySniffLen)], 0) >= 0 {
+		return nil
+	}
+	result.FilesScanned++
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+
+	rel, err := filepath.Rel(root, file)
+	if err != nil {
+		return err
+	}
+	fileInfo, _ := classifier.Classify(file, root)
+	if fileInfo.Proto != "" {
+		if protoRel, err := filepath.Rel(root, fileInfo.Proto); err == nil {
+			fileInfo.Proto = filepath.ToSlash(protoRel)
+		}
+	}
+
+	for i, line := range lines {
+		loc := opts.Pattern.FindStringIndex(line)
+		if loc == nil {
+			continue
+		}
+		if opts.MaxResults > 0 && len(result.Matches) >= opts.MaxResults {
+			result.Truncated = true
+			return nil
+		}
+		result.Matches = append(result.Matches, Match{
+			File:      filepath.ToSlash(rel),
+			Line:      i + 1,
+			Column:    loc[0] + 1,
+			Text:      truncateLine(line),
+			Before:    contextLines(lines, i-opts.Context, i),
+			After:     contextLines(lines, i+1, i+1+opts.Context),
+			Generated: fileInfo.Generated,
+			Proto:     fileInfo.Proto,
+		})
+	}
+	return nil
+}
+
+func contextLines(lines []string, from, to int) []string {
+	from = max(from, 0)
+	to = min(to, len(lines))
+	if from >= to {
+		return nil
+	}
+	context := make([]string, 0, to-from)
+	for _, line := range lines[from:to] {
+		context = append(context, truncateLine(line))
+	}
+	return context
+}
+
+func truncateLine(line string) string {
+	if len(line) <= maxLineLength {
+		return line
+	}
+	return strings.ToValidUTF8(line[:maxLineLength], "") + "..."
+}
@@ -0,0 +1,103 @@
+package modsearch_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/generated"
+	"github.com/svetlyi/mcp-local-context/internal/modsearch"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func setupModule(t *testing.T) string {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":               "module example.com/dep\n",
+		"conn.go":              "package dep\n\n// Subscribe registers a handler.\nfunc (c *Conn) Subscribe(subj string) error {\n\treturn nil\n}\n",
+		"jetstream/js.go":      "package jetstream\n\nfunc Subscribe() {}\n",
+		"jetstream/js_test.go": "package jetstream\n\nfunc TestSubscribe() { Subscribe() }\n",
+		"api/api.pb.go":        "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: api.proto\n\npackage api\n\nfunc Subscribe() {}\n",
+		"api/api.proto":        "service API { rpc Subscribe(Req) returns (Resp); }\n",
+		"nested/go.mod":        "module example.com/dep/nested\n",
+		"nested/nested.go":     "package nested\n\nfunc Subscribe() {}\n",
+		"testdata/binary.bin":  "Subscribe\x00\x01",
+		".git/config":          "Subscribe\n",
+	}
+	testutil.WriteFiles(t, root, files)
+	return root
+}
+
+func matchFiles(matches []modsearch.Match) []string {
+	files := make([]string, 0, len(matches))
+	for _, match := range matches {
+		files = append(files, match.File)
+	}
+	return files
+}
+
+func TestGrep(t *testing.T) {
+	root := setupModule(t)
+
+	result, err := modsearch.Grep(root, modsearch.Options{Pattern: regexp.MustCompile(`Subscribe\(`), Context: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api/api.pb.go", "api/api.proto", "conn.go", "jetstream/js.go", "jetstream/js_test.go"}, matchFiles(result.Matches))
+	assert.False(t, result.Truncated)
+
+	match := result.Matches[0]
+	assert.True(t, match.Generated)
+	assert.Equal(t, "api/api.proto", match.Proto)
+
+	match = result.Matches[2]
+	assert.Equal(t, 4, match.Line)
+	assert.Equal(t, 16, match.Column)
+	assert.Equal(t, []string{"// Subscribe registers a handler."}, match.Before)
+	assert.Equal(t, []string{"\treturn nil"}, match.After)
+	assert.False(t, match.Generated)
+}
+
+func TestGrepScope(t *testing.T) {
+	root := setupModule(t)
+	pattern := regexp.MustCompile(`Subscribe`)
+
+	result, err := modsearch.Grep(root, modsearch.Options{Pattern: pattern, Subdir: "jetstream", Glob: "*_test.go"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jetstream/js_test.go"}, matchFiles(result.Matches))
+	assert.Equal(t, 1, result.FilesScanned)
+
+	result, err = modsearch.Grep(root, modsearch.Options{Pattern: pattern, Glob: "api/*.go"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api/api.pb.go"}, matchFiles(result.Matches))
+
+	result, err = modsearch.Grep(root, modsearch.Options{Pattern: pattern, MaxResults: 2})
+	require.NoError(t, err)
+	assert.Len(t, result.Matches, 2)
+	assert.True(t, result.Truncated)
+
+	_, err = modsearch.Grep(root, modsearch.Options{Pattern: pattern, Subdir: "../other"})
+	assert.Error(t, err)
+
+	_, err = modsearch.Grep(root, modsearch.Options{Pattern: pattern, Subdir: "missing"})
+	assert.Error(t, err)
+
+	_, err = modsearch.Grep(root, modsearch.Options{Pattern: pattern, Glob: "[*.go"})
+	assert.Error(t, err)
+}
+
+func TestGrepGenerated(t *testing.T) {
+	root := setupModule(t)
+	pattern := regexp.MustCompile(`func Subscribe`)
+
+	result, err := modsearch.Grep(root, modsearch.Options{Pattern: pattern, Generated: generated.Exclude})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jetstream/js.go"}, matchFiles(result.Matches))
+
+	result, err = modsearch.Grep(root, modsearch.Options{Pattern: pattern, Generated: generated.Only})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api/api.pb.go"}, matchFiles(result.Matches))
+
+	result, err = modsearch.Grep(root, modsearch.Options{Pattern: pattern, Generated: generated.Last})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jetstream/js.go", "api/api.pb.go"}, matchFiles(result.Matches))
+}
@@ -0,0 +1,189 @@
+package npm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// lockfile resolves the version of a dependency of the package at importer,
+// the package directory relative to the lockfile ("." for the root).
+type lockfile interface {
+	version(importer, name, versionRange string) string
+}
+
+func parseLockfile(path, kind string) (lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+	var lock lockfile
+	switch kind {
+	case "package-lock":
+		lock, err = parsePackageLock(data)
+	case "pnpm-lock":
+		lock, err = parsePnpmLock(data)
+	case "yarn":
+		lock = parseYarnLock(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return lock, nil
+}
+
+// packageLock is package-lock.json. Version 2 and 3 lockfiles list every
+// installed package under "packages", keyed by its node_modules path;
+// version 1 nests them under "dependencies".
+type packageLock struct {
+	Packages map[string]struct {
+		Version string `json:"version"`
+	} `json:"packages"`
+	Dependencies map[string]struct {
+		Version string `json:"version"`
+	} `json:"dependencies"`
+}
+
+func parsePackageLock(data []byte) (*packageLock, error) {
+	var lock packageLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+func (l *packageLock) version(importer, name, versionRange string) string {
+	if l.Packages != nil {
+		// Follow Node resolution from the importer up to the root.
+		for dir := importer; ; dir = path.Dir(dir) {
+			key := "node_modules/" + name
+			if dir != "." {
+				key = dir + "/" + key
+			}
+			if pkg, ok := l.Packages[key]; ok {
+				return pkg.Version
+			}
+			if dir == "." || dir == "/" {
+				return ""
+			}
+		}
+	}
+	return l.Dependencies[name].Version
+}
+
+// pnpmLock is pnpm-lock.yaml. Since lockfile version 5.3 the dependencies
+// of each workspace package are listed under "importers"; older lockfiles
+// list the root package's dependencies at the top level.
+type pnpmLock struct {
+	Importers    map[string]pnpmImporter `yaml:"importers"`
+	pnpmImporter `yaml:",inline"`
+}
+
+type pnpmImporter struct {
+	Dependencies         map[string]yaml.Node `yaml:"dependencies"`
+	DevDependencies      map[string]yaml.Node `yaml:"devDependencies"`
+	OptionalDependencies map[string]yaml.Node `yaml:"optionalDependencies"`
+}
+
+func parsePnpmLock(data []byte) (*pnpmLock, error) {
+	var lock pnpmLock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+func (l *pnpmLock) version(importer, name, versionRange string) string {
+	deps := l.pnpmImporter
+	if imp, ok := l.Importers[importer]; ok {
+		deps = imp
+	}
+	for _, group := range []map[string]yaml.Node{deps.Dependencies, deps.DevDependencies, deps.OptionalDependencies} {
+		node, ok := group[name]
+		if !ok {
+			continue
+		}
+		// Entries are either a version or {specifier, version}.
+		version := node.Value
+		if node.Kind == yaml.MappingNode {
+			var entry struct {
+				Version string `yaml:"version"`
+			}
+			if err := node.Decode(&entry); err != nil {
+				return ""
+			}
+			version = entry.Version
+		}
+		return pnpmVersion(version)
+	}
+	return ""
+}
+
+// pnpmVersion strips the peer dependency suffix pnpm appends to versions,
+// "(react@18.2.0)" or, in older lockfiles, "_react@18.2.0".
+func pnpmVersion(version string) string {
+	if strings.HasPrefix(version, "link:") {
+		return version
+	}
+	version, _, _ = strings.Cut(version, "(")
+	version, _, _ = strings.Cut(version, "_")
+	return version
+}
+
+// yarnLock maps the "name@range" specifiers of yarn.lock entries to the
+// version they resolve to. Both the classic format and the YAML format of
+// Yarn 2 and later are read line by line:
+//
+//	"lodash@^4.17.0", lodash@^4.17.21:
+//	  version "4.17.21"
+//
+//	"lodash@npm:^4.17.21":
+//	  version: 4.17.21
+type yarnLock map[string]string
+
+func parseYarnLock(data []byte) yarnLock {
+	lock := make(yarnLock)
+	var specs []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line[0] != ' ' {
+			specs = nil
+			header := strings.TrimSuffix(strings.TrimSpace(line), ":")
+			for _, spec := range strings.Split(header, ",") {
+				specs = append(specs, strings.Trim(strings.TrimSpace(spec), `"`))
+			}
+			continue
+		}
+		field := strings.TrimSpace(line)
+		value, ok := strings.CutPrefix(field, "version ")
+		if !ok {
+			value, ok = strings.CutPrefix(field, "version: ")
+		}
+		// Only the entry's own version, not those of its dependencies,
+		// which are indented further.
+		if !ok || strings.HasPrefix(line, "    ") {
+			continue
+		}
+		for _, spec := range specs {
+			lock[spec] = strings.Trim(value, `"`)
+		}
+	}
+	return lock
+}
+
+func (l yarnLock) version(importer, name, versionRange string) string {
+	if version, ok := l[name+"@"+versionRange]; ok {
+		return version
+	}
+	return l[name+"@npm:"+versionRange]
+}
@@ -0,0 +1,267 @@
+// Package npm resolves the dependencies of JavaScript and TypeScript
+// projects through package.json, the lockfile and the installed copies in
+// node_modules.
+package npm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+)
+
+// ErrNoPackageJSON is returned when no package.json can be found for a
+// directory.
+var ErrNoPackageJSON = errors.New("package.json not found")
+
+// Dependency kinds, named after their package.json fields.
+const (
+	KindProd     = "dependencies"
+	KindDev      = "devDependencies"
+	KindPeer     = "peerDependencies"
+	KindOptional = "optionalDependencies"
+)
+
+// PackageJSON holds the fields of a package.json file used to resolve
+// dependencies and typings.
+type PackageJSON struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Main    string `json:"main"`
+	Module  string `json:"module"`
+	Types   string `json:"types"`
+	Typings string `json:"typings"`
+	// Exports is kept raw, as it is a string, an array or an object keyed
+	// by subpaths or conditions.
+	Exports              json.RawMessage   `json:"exports"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// ReadPackageJSON parses the package.json file in dir.
+func ReadPackageJSON(dir string) (*PackageJSON, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	var manifest PackageJSON
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, "package.json"), err)
+	}
+	return &manifest, nil
+}
+
+// Dependency is a package the project's package.json depends on.
+type Dependency struct {
+	Name string `json:"name"`
+	// Range is the version range package.json declares.
+	Range string `json:"range"`
+	Kind  string `json:"kind"`
+	// Locked is the version the lockfile resolves the range to.
+	Locked string `json:"locked,omitempty"`
+	// Installed is the version of the copy found in node_modules, and Dir
+	// its directory.
+	Installed string `json:"installed,omitempty"`
+	Dir       string `json:"dir,omitempty"`
+}
+
+// Project is a JavaScript or TypeScript package on disk.
+type Project struct {
+	Dir      string
+	Manifest *PackageJSON
+	// Lockfile is the path of the lockfile, empty when there is none.
+	Lockfile string
+	// LockfileKind is "package-lock", "pnpm-lock" or "yarn".
+	LockfileKind string
+
+	lock     lockfile
+	warnings []string
+}
+
+// LoadProject finds the package.json governing dir, walking up the
+// directory tree, and the lockfile next to it or in a parent directory, as
+// workspaces share one lockfile at their root.
+func LoadProject(dir string) (*Project, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	rootDir, ok := fsutil.FindUp(absDir, "package.json")
+	if !ok {
+		return nil, fmt.Errorf("%w in %s or any parent directory", ErrNoPackageJSON, absDir)
+	}
+	manifest, err := ReadPackageJSON(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Project{Dir: rootDir, Manifest: manifest}
+	if err := p.loadLockfile(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// lockfileNames lists the lockfiles in order of preference, with their kind.
+var lockfileNames = []struct {
+	name string
+	kind string
+}{
+	{"package-lock.json", "package-lock"},
+	{"npm-shrinkwrap.json", "package-lock"},
+	{"pnpm-lock.yaml", "pnpm-lock"},
+	{"yarn.lock", "yarn"},
+}
+
+func (p *Project) loadLockfile() error {
+	for dir := p.Dir; ; {
+		for _, candidate := range lockfileNames {
+			path := filepath.Join(dir, candidate.name)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			lock, err := parseLockfile(path, candidate.kind)
+			if err != nil {
+				return err
+			}
+			p.Lockfile = path
+			p.LockfileKind = candidate.kind
+			p.lock = lock
+			return nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			p.warnings = append(p.warnings, "no lockfile found; versions are taken from node_modules")
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// Warnings describes problems that may make the reported versions differ
+// from what the package manager installs.
+func (p *Project) Warnings() []string {
+	return p.warnings
+}
+
+// Dependencies returns the dependencies package.json declares, sorted by
+// kind and name, with their locked and installed versions.
+func (p *Project) Dependencies() []Dependency {
+	kinds := []struct {
+		kind   string
+		ranges map[string]string
+	}{
+		{KindProd, p.Manifest.Dependencies},
+		{KindDev, p.Manifest.DevDependencies},
+		{KindPeer, p.Manifest.PeerDependencies},
+		{KindOptional, p.Manifest.OptionalDependencies},
+	}
+
+	deps := make([]Dependency, 0)
+	seen := make(map[string]bool)
+	for _, k := range kinds {
+		names := make([]string, 0, len(k.ranges))
+		for name := range k.ranges {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			// A peer dependency is usually repeated as a dev dependency.
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			deps = append(deps, p.resolve(name, k.ranges[name], k.kind))
+		}
+	}
+	return deps
+}
+
+// Dependency returns the dependency with the given name, or an error when
+// package.json does not declare it.
+func (p *Project) Dependency(name string) (*Dependency, error) {
+	for _, dep := range p.Dependencies() {
+		if dep.Name == name {
+			return &dep, nil
+		}
+	}
+	return nil, fmt.Errorf("package %s is not a dependency of %s", name, p.displayName())
+}
+
+func (p *Project) resolve(name, versionRange, kind string) Dependency {
+	dep := Dependency{Name: name, Range: versionRange, Kind: kind}
+	if p.lock != nil {
+		rel, err := filepath.Rel(filepath.Dir(p.Lockfile), p.Dir)
+		if err == nil {
+			dep.Locked = p.lock.version(filepath.ToSlash(rel), name, versionRange)
+		}
+	}
+	if dir := p.findInstalled(name, dep.Locked); dir != "" {
+		dep.Dir = dir
+		if manifest, err := ReadPackageJSON(dir); err == nil {
+			dep.Installed = manifest.Version
+		}
+	}
+	return dep
+}
+
+// findInstalled locates the installed copy of a package the way Node
+// resolves it, through the node_modules directories of the project and its
+// parents, falling back to the pnpm store for packages pnpm did not link.
+func (p *Project) findInstalled(name, version string) string {
+	var rootModules []string
+	for dir := p.Dir; ; {
+		modules := filepath.Join(dir, "node_modules")
+		candidate := filepath.Join(modules, filepath.FromSlash(name))
+		if fsutil.IsFile(filepath.Join(candidate, "package.json")) {
+			return candidate
+		}
+		if fsutil.IsDir(modules) {
+			rootModules = append(rootModules, modules)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	if version == "" {
+		return ""
+	}
+	// pnpm stores packages as .pnpm/<name>@<version>[_peers]/node_modules/<name>,
+	// with the / of scoped names replaced by +.
+	prefix := strings.ReplaceAll(name, "/", "+") + "@" + version
+	for _, modules := range rootModules {
+		store := filepath.Join(modules, ".pnpm")
+		entries, err := os.ReadDir(store)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			entryName := entry.Name()
+			if entryName != prefix && !strings.HasPrefix(entryName, prefix+"_") && !strings.HasPrefix(entryName, prefix+"(") {
+				continue
+			}
+			candidate := filepath.Join(store, entryName, "node_modules", filepath.FromSlash(name))
+			if fsutil.IsFile(filepath.Join(candidate, "package.json")) {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+func (p *Project) displayName() string {
+	if p.Manifest.Name != "" {
+		return p.Manifest.Name
+	}
+	return p.Dir
+}
@@ -0,0 +1,139 @@
+package npm_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/npm"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func dependenciesByName(project *npm.Project) map[string]npm.Dependency {
+	deps := make(map[string]npm.Dependency)
+	for _, dep := range project.Dependencies() {
+		deps[dep.Name] = dep
+	}
+	return deps
+}
+
+func TestLoadProjectPackageLock(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"package.json": `{
+  "name": "monorepo",
+  "workspaces": ["packages/*"]
+}`,
+		"package-lock.json": `{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"name": "monorepo"},
+    "node_modules/axios": {"version": "1.7.2"},
+    "node_modules/typescript": {"version": "5.4.5"},
+    "packages/web/node_modules/axios": {"version": "0.27.2"}
+  }
+}`,
+		"packages/web/package.json": `{
+  "name": "web",
+  "dependencies": {"axios": "^0.27.0", "left-pad": "^1.3.0"},
+  "devDependencies": {"typescript": "^5.4.0"}
+}`,
+		"packages/web/src/.keep":                       "",
+		"packages/web/node_modules/axios/package.json": `{"name": "axios", "version": "0.27.2"}`,
+		"node_modules/typescript/package.json":         `{"name": "typescript", "version": "5.4.4"}`,
+		"node_modules/axios/package.json":              `{"name": "axios", "version": "1.7.2"}`,
+	})
+
+	project, err := npm.LoadProject(filepath.Join(tmpDir, "packages", "web", "src"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmpDir, "packages", "web"), project.Dir)
+	assert.Equal(t, filepath.Join(tmpDir, "package-lock.json"), project.Lockfile)
+	assert.Equal(t, "package-lock", project.LockfileKind)
+
+	deps := dependenciesByName(project)
+	require.Len(t, deps, 3)
+
+	axios := deps["axios"]
+	assert.Equal(t, npm.KindProd, axios.Kind)
+	assert.Equal(t, "^0.27.0", axios.Range)
+	assert.Equal(t, "0.27.2", axios.Locked, "The workspace's nested copy wins over the hoisted one")
+	assert.Equal(t, "0.27.2", axios.Installed)
+	assert.Equal(t, filepath.Join(tmpDir, "packages", "web", "node_modules", "axios"), axios.Dir)
+
+	typescript := deps["typescript"]
+	assert.Equal(t, npm.KindDev, typescript.Kind)
+	assert.Equal(t, "5.4.5", typescript.Locked)
+	assert.Equal(t, "5.4.4", typescript.Installed, "node_modules may lag behind the lockfile")
+
+	leftPad := deps["left-pad"]
+	assert.Empty(t, leftPad.Locked)
+	assert.Empty(t, leftPad.Dir)
+
+	_, err = project.Dependency("react")
+	assert.Error(t, err)
+}
+
+func TestLoadProjectPnpm(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"package.json": `{"name": "app", "dependencies": {"@tanstack/query-core": "^5.0.0", "react": "^18.2.0"}}`,
+		"pnpm-lock.yaml": `lockfileVersion: '9.0'
+
+importers:
+  .:
+    dependencies:
+      '@tanstack/query-core':
+        specifier: ^5.0.0
+        version: 5.40.0
+      react:
+        specifier: ^18.2.0
+        version: 18.3.1(loose-envify@1.4.0)
+`,
+		"node_modules/.pnpm/@tanstack+query-core@5.40.0/node_modules/@tanstack/query-core/package.json": `{"name": "@tanstack/query-core", "version": "5.40.0"}`,
+		"node_modules/.pnpm/react@18.3.1(loose-envify@1.4.0)/node_modules/react/package.json":           `{"name": "react", "version": "18.3.1"}`,
+	})
+
+	project, err := npm.LoadProject(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, "pnpm-lock", project.LockfileKind)
+
+	deps := dependenciesByName(project)
+	assert.Equal(t, "5.40.0", deps["@tanstack/query-core"].Locked)
+	assert.Equal(t, "5.40.0", deps["@tanstack/query-core"].Installed, "Found in the .pnpm store without a node_modules link")
+	assert.Equal(t, "18.3.1", deps["react"].Locked, "The peer suffix is not part of the version")
+	assert.Equal(t, "18.3.1", deps["react"].Installed)
+}
+
+func TestLoadProjectYarn(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"package.json": `{"name": "app", "dependencies": {"lodash": "^4.17.0", "@babel/core": "^7.24.0"}}`,
+		"yarn.lock": `# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+# yarn lockfile v1
+
+
+"@babel/core@^7.24.0":
+  version "7.24.5"
+  resolved "https://registry.yarnpkg.com/@babel/core/-/core-7.24.5.tgz"
+  dependencies:
+    debug "^4.1.0"
+
+lodash@^4.17.0, lodash@^4.17.21:
+  version "4.17.21"
+`,
+	})
+
+	project, err := npm.LoadProject(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, "yarn", project.LockfileKind)
+
+	deps := dependenciesByName(project)
+	assert.Equal(t, "7.24.5", deps["@babel/core"].Locked)
+	assert.Equal(t, "4.17.21", deps["lodash"].Locked)
+}
+
+func TestLoadProjectWithoutPackageJSON(t *testing.T) {
+	_, err := npm.LoadProject(t.TempDir())
+	assert.ErrorIs(t, err, npm.ErrNoPackageJSON)
+}
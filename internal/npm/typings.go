@@ -0,0 +1,321 @@
+package npm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+)
+
+// typesConditions are the export conditions tried, in order, when looking
+// for type declarations.
+var typesConditions = []string{"types", "typings", "import", "require", "node", "default"}
+
+// Typings locates the type declarations of an installed package.
+type Typings struct {
+	Package string `json:"package"`
+	Version string `json:"version,omitempty"`
+	// Dir is the directory of the package holding the declarations, which
+	// is the @types package when the package ships none.
+	Dir string `json:"dir"`
+	// Entry is the declaration file of the requested subpath, relative to
+	// Dir.
+	Entry string `json:"entry"`
+	// Source tells which field the entry comes from: "exports", "types",
+	// "typings", "main", "index.d.ts" or "@types/<name>".
+	Source string `json:"source"`
+	// Exports lists the subpaths the package exports with their declaration
+	// files.
+	Exports []TypesExport `json:"exports,omitempty"`
+}
+
+// TypesExport is a subpath of a package's exports and its declarations.
+type TypesExport struct {
+	Subpath string `json:"subpath"`
+	Types   string `json:"types"`
+}
+
+// ResolveTypings finds the declarations of subpath ("." for the package
+// root, or e.g. "./client") in the package installed in dir. When the
+// package ships none, the @types package is looked up through project.
+func ResolveTypings(project *Project, name, dir, subpath string) (*Typings, error) {
+	manifest, err := ReadPackageJSON(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package.json of %s: %w", name, err)
+	}
+	if subpath == "" {
+		subpath = "."
+	}
+	if subpath != "." && !strings.HasPrefix(subpath, "./") {
+		subpath = "./" + strings.TrimPrefix(subpath, "/")
+	}
+
+	typings := &Typings{Package: name, Version: manifest.Version, Dir: dir}
+	typings.Exports = exportedTypes(dir, manifest.Exports)
+	if entry, source := packageEntry(dir, manifest, subpath); entry != "" {
+		typings.Entry = entry
+		typings.Source = source
+		return typings, nil
+	}
+
+	typesName := TypesPackage(name)
+	if typesDir := project.findInstalled(typesName, ""); typesDir != "" {
+		typesManifest, err := ReadPackageJSON(typesDir)
+		if err == nil {
+			if entry, _ := packageEntry(typesDir, typesManifest, subpath); entry != "" {
+				typings.Dir = typesDir
+				typings.Entry = entry
+				typings.Source = typesName
+				typings.Exports = exportedTypes(typesDir, typesManifest.Exports)
+				return typings, nil
+			}
+		}
+	}
+	if subpath != "." {
+		return nil, fmt.Errorf("no type declarations found for %s%s", name, strings.TrimPrefix(subpath, "."))
+	}
+	return nil, fmt.Errorf("%s ships no type declarations and %s is not installed", name, typesName)
+}
+
+// TypesPackage returns the DefinitelyTyped package of name, e.g.
+// @types/lodash or @types/babel__core for @babel/core.
+func TypesPackage(name string) string {
+	if scope, pkg, ok := strings.Cut(strings.TrimPrefix(name, "@"), "/"); ok && strings.HasPrefix(name, "@") {
+		return "@types/" + scope + "__" + pkg
+	}
+	return "@types/" + name
+}
+
+// packageEntry returns the declaration file of subpath relative to dir and
+// the field it was found through.
+func packageEntry(dir string, manifest *PackageJSON, subpath string) (string, string) {
+	if len(manifest.Exports) > 0 {
+		var exports any
+		if json.Unmarshal(manifest.Exports, &exports) == nil {
+			if target := exportTarget(exports, subpath); target != "" {
+				if entry := declarationFor(dir, target); entry != "" {
+					return entry, "exports"
+				}
+			}
+		}
+	}
+	if subpath != "." {
+		// Without exports, subpaths are plain files of the package.
+		if entry := declarationFor(dir, subpath); entry != "" {
+			return entry, "path"
+		}
+		if entry := declarationFor(dir, subpath+"/index"); entry != "" {
+			return entry, "path"
+		}
+		return "", ""
+	}
+
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"types", manifest.Types},
+		{"typings", manifest.Typings},
+		{"main", manifest.Main},
+	} {
+		if field.value == "" {
+			continue
+		}
+		if entry := declarationFor(dir, field.value); entry != "" {
+			return entry, field.name
+		}
+	}
+	if fsutil.IsFile(filepath.Join(dir, "index.d.ts")) {
+		return "index.d.ts", "index.d.ts"
+	}
+	return "", ""
+}
+
+// exportTarget resolves subpath in an exports value, preferring the
+// declaration conditions.
+func exportTarget(exports any, subpath string) string {
+	if m, ok := exports.(map[string]any); ok && hasSubpathKeys(m) {
+		if value, ok := m[subpath]; ok {
+			return conditionTarget(value)
+		}
+		// Subpath patterns such as "./*" or "./features/*.js". Like Node,
+		// the matching pattern first in PATTERN_KEY_COMPARE order wins,
+		// which is the one with the longest prefix. The * must match at least
+		// one character.
+		var best string
+		for key := range m {
+			prefix, suffix, ok := strings.Cut(key, "*")
+			if !ok || strings.Contains(suffix, "*") || !strings.HasPrefix(subpath, prefix) || !strings.HasSuffix(subpath, suffix) || len(subpath) <= len(prefix)+len(suffix) {
+				continue
+			}
+			if best == "" || patternKeyCompare(key, best) < 0 {
+				best = key
+			}
+		}
+		if best == "" {
+			return ""
+		}
+		prefix, suffix, _ := strings.Cut(best, "*")
+		match := subpath[len(prefix) : len(subpath)-len(suffix)]
+		return strings.ReplaceAll(conditionTarget(m[best]), "*", match)
+	}
+	if subpath != "." {
+		return ""
+	}
+	return conditionTarget(exports)
+}
+
+// conditionTarget picks a target from a string, array or conditions object.
+func conditionTarget(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []any:
+		for _, item := range v {
+			if target := conditionTarget(item); target != "" {
+				return target
+			}
+		}
+	case map[string]any:
+		for _, condition := range typesConditions {
+			if item, ok := v[condition]; ok {
+				if target := conditionTarget(item); target != "" {
+					return target
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// patternKeyCompare orders exports keys as Node's PATTERN_KEY_COMPARE does:
+// longer prefixes before the "*" first, then keys with a "*" before those
+// without, then longer keys first.
+func patternKeyCompare(a, b string) int {
+	baseA, baseB := len(a), len(b)
+	if i := strings.IndexByte(a, '*'); i >= 0 {
+		baseA = i + 1
+	}
+	if i := strings.IndexByte(b, '*'); i >= 0 {
+		baseB = i + 1
+	}
+	switch {
+	case baseA != baseB:
+		return baseB - baseA
+	case !strings.Contains(a, "*"):
+		return 1
+	case !strings.Contains(b, "*"):
+		return -1
+	}
+	return len(b) - len(a)
+}
+
+// hasSubpathKeys reports whether an exports object maps subpaths rather
+// than conditions. Node requires all keys or none to start with ".".
+func hasSubpathKeys(m map[string]any) bool {
+	for key := range m {
+		if strings.HasPrefix(key, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// declarationFor maps a file of the package to its declaration file,
+// e.g. ./dist/index.js to dist/index.d.ts, returning "" when there is none.
+func declarationFor(dir, target string) string {
+	rel := path.Clean(strings.TrimPrefix(target, "./"))
+	if strings.HasPrefix(rel, "../") || rel == ".." {
+		return ""
+	}
+	var candidates []string
+	switch {
+	case isDeclaration(rel):
+		candidates = []string{rel}
+	case strings.HasSuffix(rel, ".mjs"):
+		candidates = []string{strings.TrimSuffix(rel, ".mjs") + ".d.mts"}
+	case strings.HasSuffix(rel, ".cjs"):
+		candidates = []string{strings.TrimSuffix(rel, ".cjs") + ".d.cts"}
+	case strings.HasSuffix(rel, ".js"):
+		candidates = []string{strings.TrimSuffix(rel, ".js") + ".d.ts"}
+	default:
+		candidates = []string{rel + ".d.ts", rel + "/index.d.ts"}
+	}
+	for _, candidate := range candidates {
+		if fsutil.IsFile(filepath.Join(dir, filepath.FromSlash(candidate))) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// exportedTypes lists the subpaths of an exports object that resolve to
+// declaration files.
+func exportedTypes(dir string, raw json.RawMessage) []TypesExport {
+	if len(raw) == 0 {
+		return nil
+	}
+	var exports any
+	if json.Unmarshal(raw, &exports) != nil {
+		return nil
+	}
+	m, ok := exports.(map[string]any)
+	if !ok || !hasSubpathKeys(m) {
+		return nil
+	}
+	var result []TypesExport
+	for subpath, value := range m {
+		if strings.Contains(subpath, "*") {
+			continue
+		}
+		if entry := declarationFor(dir, conditionTarget(value)); entry != "" {
+			result = append(result, TypesExport{Subpath: subpath, Types: entry})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Subpath < result[j].Subpath })
+	return result
+}
+
+// DeclarationFiles lists up to max declaration files of the package in
+// dir, relative to it, skipping nested node_modules.
+func DeclarationFiles(dir string, max int) ([]string, bool, error) {
+	var files []string
+	truncated := false
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isDeclaration(d.Name()) {
+			return nil
+		}
+		if len(files) == max {
+			truncated = true
+			return filepath.SkipAll
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list declaration files: %w", err)
+	}
+	return files, truncated, nil
+}
+
+func isDeclaration(name string) bool {
+	return strings.HasSuffix(name, ".d.ts") || strings.HasSuffix(name, ".d.mts") || strings.HasSuffix(name, ".d.cts")
+}
@@ -0,0 +1,104 @@
+package npm_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/npm"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func TestResolveTypings(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"package.json": `{"name": "app", "dependencies": {"zod": "^3.0.0", "legacy": "^1.0.0", "lodash": "^4.0.0", "@babel/core": "^7.0.0"}}`,
+		"node_modules/zod/package.json": `{
+  "name": "zod",
+  "version": "3.23.8",
+  "exports": {
+    ".": {
+      "import": {"types": "./lib/index.d.mts", "default": "./lib/index.mjs"},
+      "require": {"types": "./lib/index.d.ts", "default": "./lib/index.js"}
+    },
+    "./locales/*": {"types": "./lib/locales/*.d.ts"},
+    "./package.json": "./package.json"
+  }
+}`,
+		"node_modules/zod/lib/index.d.mts":        "export {};\n",
+		"node_modules/zod/lib/index.d.ts":         "export {};\n",
+		"node_modules/zod/lib/locales/en.d.ts":    "export {};\n",
+		"node_modules/legacy/package.json":        `{"name": "legacy", "version": "1.0.0", "main": "dist/legacy.js"}`,
+		"node_modules/legacy/dist/legacy.d.ts":    "export {};\n",
+		"node_modules/lodash/package.json":        `{"name": "lodash", "version": "4.17.21", "main": "lodash.js"}`,
+		"node_modules/@types/lodash/package.json": `{"name": "@types/lodash", "version": "4.17.4", "types": "index.d.ts"}`,
+		"node_modules/@types/lodash/index.d.ts":   "export {};\n",
+		"node_modules/@babel/core/package.json":   `{"name": "@babel/core", "version": "7.24.5", "main": "lib/index.js"}`,
+		"node_modules/kit/package.json": `{
+  "name": "kit",
+  "version": "2.0.0",
+  "exports": {
+    "./*": {"types": "./types/*.d.ts"},
+    "./features/*": {"types": "./types/features/*/index.d.ts"},
+    "./features/*.js": {"types": "./types/features/*.d.ts"},
+    "./features/internal/*": null
+  }
+}`,
+		"node_modules/kit/types/util.d.ts":                "export {};\n",
+		"node_modules/kit/types/features/auth.d.ts":       "export {};\n",
+		"node_modules/kit/types/features/auth/index.d.ts": "export {};\n",
+		"node_modules/kit/types/features/internal/x.d.ts": "export {};\n",
+		"node_modules/kit/types/features/.d.ts":           "export {};\n",
+		"node_modules/kit/types/features/.js/index.d.ts":  "export {};\n",
+	})
+	project, err := npm.LoadProject(tmpDir)
+	require.NoError(t, err)
+	modules := filepath.Join(tmpDir, "node_modules")
+
+	typings, err := npm.ResolveTypings(project, "zod", filepath.Join(modules, "zod"), "")
+	require.NoError(t, err)
+	assert.Equal(t, "lib/index.d.mts", typings.Entry, "The import condition comes first")
+	assert.Equal(t, "exports", typings.Source)
+	assert.Equal(t, "3.23.8", typings.Version)
+	assert.Equal(t, []npm.TypesExport{{Subpath: ".", Types: "lib/index.d.mts"}}, typings.Exports)
+
+	typings, err = npm.ResolveTypings(project, "zod", filepath.Join(modules, "zod"), "locales/en")
+	require.NoError(t, err)
+	assert.Equal(t, "lib/locales/en.d.ts", typings.Entry, "Subpath patterns are expanded")
+
+	for subpath, entry := range map[string]string{
+		"util":                "types/util.d.ts",
+		"features/auth":       "types/features/auth/index.d.ts",
+		"features/auth.js":    "types/features/auth.d.ts",
+		"features/.js":        "types/features/.js/index.d.ts",
+		"features/internal/x": "",
+	} {
+		typings, err = npm.ResolveTypings(project, "kit", filepath.Join(modules, "kit"), subpath)
+		if entry == "" {
+			assert.Error(t, err, "A null target hides the subpath even though ./features/* matches too")
+			continue
+		}
+		require.NoError(t, err, subpath)
+		assert.Equal(t, entry, typings.Entry, "The pattern with the longest prefix and a non-empty match wins for %s", subpath)
+	}
+
+	typings, err = npm.ResolveTypings(project, "legacy", filepath.Join(modules, "legacy"), ".")
+	require.NoError(t, err)
+	assert.Equal(t, "dist/legacy.d.ts", typings.Entry)
+	assert.Equal(t, "main", typings.Source)
+
+	typings, err = npm.ResolveTypings(project, "lodash", filepath.Join(modules, "lodash"), ".")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(modules, "@types", "lodash"), typings.Dir)
+	assert.Equal(t, "index.d.ts", typings.Entry)
+	assert.Equal(t, "@types/lodash", typings.Source)
+
+	_, err = npm.ResolveTypings(project, "@babel/core", filepath.Join(modules, "@babel", "core"), ".")
+	assert.ErrorContains(t, err, "@types/babel__core")
+
+	files, truncated, err := npm.DeclarationFiles(filepath.Join(modules, "zod"), 2)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.True(t, truncated)
+}
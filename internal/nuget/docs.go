@@ -0,0 +1,502 @@
+package nuget
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Param is a documented parameter, type parameter or exception, the
+// latter named by its type.
+type Param struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+}
+
+// Member is the documentation of a member in an XML documentation file.
+type Member struct {
+	// ID is the documentation ID, such as
+	// M:Newtonsoft.Json.JsonConvert.SerializeObject(System.Object).
+	ID string `json:"id"`
+	// Kind is namespace, type, method, property, field or event, and Name
+	// the ID without its kind prefix.
+	Kind       string  `json:"kind"`
+	Name       string  `json:"name"`
+	Summary    string  `json:"summary,omitempty"`
+	TypeParams []Param `json:"type_params,omitempty"`
+	Params     []Param `json:"params,omitempty"`
+	Returns    string  `json:"returns,omitempty"`
+	Value      string  `json:"value,omitempty"`
+	Exceptions []Param `json:"exceptions,omitempty"`
+	Remarks    string  `json:"remarks,omitempty"`
+	Example    string  `json:"example,omitempty"`
+	// InheritDoc is set when the member inherits its documentation from
+	// the member it overrides or implements.
+	InheritDoc bool `json:"inheritdoc,omitempty"`
+}
+
+// Docs is an XML documentation file.
+type Docs struct {
+	Assembly string   `json:"assembly"`
+	Members  []Member `json:"members"`
+}
+
+var memberKinds = map[string]string{
+	"N": "namespace",
+	"T": "type",
+	"M": "method",
+	"P": "property",
+	"F": "field",
+	"E": "event",
+}
+
+// DocFrameworks lists the target frameworks for which a package ships XML
+// documentation, under lib/ or its reference assemblies under ref/.
+func DocFrameworks(pkgDir string) []string {
+	seen := make(map[string]bool)
+	frameworks := make([]string, 0)
+	for _, group := range []string{"lib", "ref"} {
+		entries, _ := os.ReadDir(filepath.Join(pkgDir, group))
+		for _, entry := range entries {
+			if entry.IsDir() && !seen[entry.Name()] && len(docFiles(filepath.Join(pkgDir, group, entry.Name()))) > 0 {
+				seen[entry.Name()] = true
+				frameworks = append(frameworks, entry.Name())
+			}
+		}
+	}
+	sort.Slice(frameworks, func(i, j int) bool {
+		return compareFrameworks(frameworks[i], frameworks[j]) > 0
+	})
+	return frameworks
+}
+
+// DocFiles returns the XML documentation files of a package for framework
+// or, when it is empty, for the first of targets the package has docs for,
+// falling back to the newest framework. It returns the framework chosen.
+func DocFiles(pkgDir, framework string, targets []string) (string, []string) {
+	available := DocFrameworks(pkgDir)
+	if len(available) == 0 {
+		return "", nil
+	}
+	chosen := ""
+	if framework != "" {
+		for _, candidate := range available {
+			if strings.EqualFold(candidate, framework) {
+				chosen = candidate
+			}
+		}
+		if chosen == "" {
+			return "", nil
+		}
+	}
+	for _, target := range targets {
+		for _, candidate := range available {
+			if chosen == "" && strings.EqualFold(candidate, target) {
+				chosen = candidate
+			}
+		}
+	}
+	if chosen == "" {
+		chosen = available[0]
+	}
+	for _, group := range []string{"lib", "ref"} {
+		if files := docFiles(filepath.Join(pkgDir, group, chosen)); len(files) > 0 {
+			return chosen, files
+		}
+	}
+	return chosen, nil
+}
+
+// docFiles returns the XML files of dir documenting an assembly next to
+// them.
+func docFiles(dir string) []string {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.xml"))
+	files := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if _, err := os.Stat(strings.TrimSuffix(match, filepath.Ext(match)) + ".dll"); err == nil {
+			files = append(files, match)
+		}
+	}
+	return files
+}
+
+// compareFrameworks orders target framework monikers from the oldest to
+// the newest: .NET Framework (net462), .NET Standard, .NET Core and .NET 5
+// and later (net8.0).
+func compareFrameworks(a, b string) int {
+	familyA, versionA := frameworkVersion(a)
+	familyB, versionB := frameworkVersion(b)
+	if familyA != familyB {
+		return familyA - familyB
+	}
+	for i := 0; i < max(len(versionA), len(versionB)); i++ {
+		var x, y int
+		if i < len(versionA) {
+			x = versionA[i]
+		}
+		if i < len(versionB) {
+			y = versionB[i]
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+func frameworkVersion(tfm string) (int, []int) {
+	tfm = strings.ToLower(tfm)
+	// Platform suffixes, as in net8.0-windows, do not change the order.
+	tfm, _, _ = strings.Cut(tfm, "-")
+	family := 0
+	var version string
+	switch {
+	case strings.HasPrefix(tfm, "netstandard"):
+		family, version = 2, strings.TrimPrefix(tfm, "netstandard")
+	case strings.HasPrefix(tfm, "netcoreapp"):
+		family, version = 3, strings.TrimPrefix(tfm, "netcoreapp")
+	case strings.HasPrefix(tfm, "net") && strings.Contains(tfm, "."):
+		family, version = 4, strings.TrimPrefix(tfm, "net")
+	case strings.HasPrefix(tfm, "net"):
+		// net462 is .NET Framework 4.6.2.
+		family = 1
+		for _, digit := range strings.TrimPrefix(tfm, "net") {
+			version += string(digit) + "."
+		}
+	default:
+		return 0, nil
+	}
+	var parts []int
+	for _, part := range strings.Split(strings.Trim(version, "."), ".") {
+		n, _ := strconv.Atoi(part)
+		parts = append(parts, n)
+	}
+	return family, parts
+}
+
+// ReadDocs parses an XML documentation file.
+func ReadDocs(path string) (*Docs, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	docs := &Docs{Members: make([]Member, 0)}
+	d := xml.NewDecoder(f)
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "name":
+			if docs.Assembly == "" {
+				var name string
+				if err := d.DecodeElement(&name, &start); err != nil {
+					return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+				}
+				docs.Assembly = strings.TrimSpace(name)
+			}
+		case "member":
+			member, err := readMember(d, start)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			docs.Members = append(docs.Members, member)
+		}
+	}
+	return docs, nil
+}
+
+func readMember(d *xml.Decoder, start xml.StartElement) (Member, error) {
+	member := Member{ID: attr(start, "name")}
+	prefix, name, ok := strings.Cut(member.ID, ":")
+	if ok {
+		member.Kind, member.Name = memberKinds[prefix], name
+	} else {
+		member.Name = member.ID
+	}
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return member, err
+		}
+		switch token := token.(type) {
+		case xml.EndElement:
+			return member, nil
+		case xml.StartElement:
+			if token.Name.Local == "inheritdoc" {
+				member.InheritDoc = true
+				if err := d.Skip(); err != nil {
+					return member, err
+				}
+				continue
+			}
+			text, err := readText(d)
+			if err != nil {
+				return member, err
+			}
+			switch token.Name.Local {
+			case "summary":
+				member.Summary = text
+			case "typeparam":
+				member.TypeParams = append(member.TypeParams, Param{Name: attr(token, "name"), Text: text})
+			case "param":
+				member.Params = append(member.Params, Param{Name: attr(token, "name"), Text: text})
+			case "returns":
+				member.Returns = text
+			case "value":
+				member.Value = text
+			case "exception":
+				member.Exceptions = append(member.Exceptions, Param{Name: crefName(attr(token, "cref")), Text: text})
+			case "remarks":
+				member.Remarks = text
+			case "example":
+				member.Example = text
+			}
+		}
+	}
+}
+
+// readText renders the content of the current element as text, turning
+// references into names, <c> into `code` and <code> into fenced blocks.
+func readText(d *xml.Decoder) (string, error) {
+	var w docWriter
+	if err := w.render(d); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(w.b.String()), nil
+}
+
+type docWriter struct {
+	b strings.Builder
+}
+
+func (w *docWriter) render(d *xml.Decoder) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch token := token.(type) {
+		case xml.EndElement:
+			return nil
+		case xml.CharData:
+			w.text(string(token))
+		case xml.StartElement:
+			if err := w.element(d, token); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (w *docWriter) element(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name.Local {
+	case "see", "seealso":
+		var inner docWriter
+		if err := inner.render(d); err != nil {
+			return err
+		}
+		text := strings.TrimSpace(inner.b.String())
+		switch {
+		case text != "":
+		case attr(start, "cref") != "":
+			text = crefName(attr(start, "cref"))
+		case attr(start, "langword") != "":
+			text = attr(start, "langword")
+		default:
+			text = attr(start, "href")
+		}
+		w.text(text)
+	case "paramref", "typeparamref":
+		w.text(attr(start, "name"))
+		return d.Skip()
+	case "c":
+		var inner docWriter
+		if err := inner.render(d); err != nil {
+			return err
+		}
+		w.text("`" + strings.TrimSpace(inner.b.String()) + "`")
+	case "code":
+		var code strings.Builder
+		for depth := 1; depth > 0; {
+			token, err := d.Token()
+			if err != nil {
+				return err
+			}
+			switch token := token.(type) {
+			case xml.StartElement:
+				depth++
+			case xml.EndElement:
+				depth--
+			case xml.CharData:
+				code.Write(token)
+			}
+		}
+		w.breakLine(2)
+		w.b.WriteString("```\n" + dedent(code.String()) + "\n```")
+		w.breakLine(2)
+	case "para":
+		w.breakLine(2)
+		if err := w.render(d); err != nil {
+			return err
+		}
+		w.breakLine(2)
+	case "br":
+		w.breakLine(1)
+		return d.Skip()
+	case "item":
+		w.breakLine(1)
+		w.b.WriteString("- ")
+		return w.render(d)
+	case "term":
+		if err := w.render(d); err != nil {
+			return err
+		}
+		w.text(":")
+	default:
+		return w.render(d)
+	}
+	return nil
+}
+
+// text writes s with its whitespace collapsed, as XML documentation is
+// indented to its surroundings.
+func (w *docWriter) text(s string) {
+	current := w.b.String()
+	atStart := current == "" || strings.HasSuffix(current, "\n") || strings.HasSuffix(current, " ")
+	if strings.TrimLeft(s, " \t\r\n") != s && !atStart {
+		w.b.WriteString(" ")
+	}
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return
+	}
+	w.b.WriteString(strings.Join(fields, " "))
+	if strings.TrimRight(s, " \t\r\n") != s {
+		w.b.WriteString(" ")
+	}
+}
+
+// breakLine ends the current line with n newlines, unless nothing was
+// written yet.
+func (w *docWriter) breakLine(n int) {
+	current := strings.TrimRight(w.b.String(), " ")
+	if current == "" {
+		w.b.Reset()
+		return
+	}
+	current = strings.TrimRight(current, "\n")
+	w.b.Reset()
+	w.b.WriteString(current + strings.Repeat("\n", n))
+}
+
+// dedent removes the indentation common to the lines of a code block.
+func dedent(code string) string {
+	lines := strings.Split(strings.ReplaceAll(code, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		}
+		lines[i] = strings.TrimRight(lines[i], " \t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// crefName returns the name a documentation ID refers to, without its
+// kind prefix.
+func crefName(cref string) string {
+	if len(cref) > 2 && cref[1] == ':' {
+		return cref[2:]
+	}
+	return cref
+}
+
+func attr(start xml.StartElement, name string) string {
+	for _, a := range start.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// Find returns the members named name, such as a type and its members,
+// the overloads of a method or, for a namespace, everything in it. A name
+// that matches no qualified name is matched against the trailing segments
+// of the names, as in JsonConvert.SerializeObject.
+func (d *Docs) Find(name string) []Member {
+	var found []Member
+	for _, member := range d.Members {
+		if hasPrefixPath(member.Name, name) {
+			found = append(found, member)
+		}
+	}
+	if len(found) > 0 {
+		return found
+	}
+	for _, member := range d.Members {
+		path, _, _ := strings.Cut(member.Name, "(")
+		for i := strings.Index(path, "."+name); i >= 0; {
+			if hasPrefixPath(member.Name[i+1:], name) {
+				found = append(found, member)
+				break
+			}
+			next := strings.Index(path[i+1:], "."+name)
+			if next < 0 {
+				break
+			}
+			i += 1 + next
+		}
+	}
+	return found
+}
+
+// Types returns the documented types.
+func (d *Docs) Types() []Member {
+	var types []Member
+	for _, member := range d.Members {
+		if member.Kind == "type" {
+			types = append(types, member)
+		}
+	}
+	return types
+}
+
+// hasPrefixPath reports whether the member name is name or a member of it:
+// followed by a ., a parameter list or the arity of a generic type.
+func hasPrefixPath(memberName, name string) bool {
+	rest, ok := strings.CutPrefix(memberName, name)
+	return ok && (rest == "" || strings.ContainsAny(rest[:1], ".(`"))
+}
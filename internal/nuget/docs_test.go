@@ -0,0 +1,119 @@
+package nuget_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/nuget"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+const jsonDocs = `<?xml version="1.0" encoding="utf-8"?>
+<doc>
+    <assembly>
+        <name>Newtonsoft.Json</name>
+    </assembly>
+    <members>
+        <member name="T:Newtonsoft.Json.JsonConvert">
+            <summary>
+            Provides methods for converting between .NET types and JSON types.
+            </summary>
+            <example>
+              <code lang="cs">
+                string json = JsonConvert.SerializeObject(product);
+                Product p = JsonConvert.DeserializeObject&lt;Product&gt;(json);
+              </code>
+            </example>
+        </member>
+        <member name="M:Newtonsoft.Json.JsonConvert.SerializeObject(System.Object)">
+            <summary>
+            Serializes the specified <see cref="T:System.Object"/> to a JSON string.
+            </summary>
+            <param name="value">The object to serialize.</param>
+            <returns>A JSON string representation of the object.</returns>
+        </member>
+        <member name="M:Newtonsoft.Json.JsonConvert.DeserializeObject` + "``" + `1(System.String)">
+            <summary>
+            Deserializes the JSON to the specified .NET type.
+            <para>Returns <c>default</c> when <paramref name="value"/> is <see langword="null"/>.</para>
+            </summary>
+            <typeparam name="T">The type of the object to deserialize to.</typeparam>
+            <param name="value">The JSON to deserialize.</param>
+            <exception cref="T:Newtonsoft.Json.JsonReaderException">The JSON is invalid.</exception>
+        </member>
+        <member name="P:Newtonsoft.Json.JsonConvert.DefaultSettings">
+            <inheritdoc />
+        </member>
+        <member name="T:Newtonsoft.Json.JsonConverter` + "`" + `1">
+            <summary>Converts an object to and from JSON.</summary>
+        </member>
+    </members>
+</doc>
+`
+
+func TestReadDocs(t *testing.T) {
+	pkgDir := t.TempDir()
+	testutil.WriteFiles(t, pkgDir, map[string]string{
+		"lib/net45/Newtonsoft.Json.xml":           jsonDocs,
+		"lib/net45/Newtonsoft.Json.dll":           "",
+		"lib/net6.0/Newtonsoft.Json.xml":          jsonDocs,
+		"lib/net6.0/Newtonsoft.Json.dll":          "",
+		"lib/netstandard2.0/Newtonsoft.Json.xml":  jsonDocs,
+		"lib/netstandard2.0/Newtonsoft.Json.dll":  "",
+		"lib/netstandard1.0/readme.xml":           "<readme/>",
+		"lib/net6.0/de/Newtonsoft.Json.resources": "",
+	})
+
+	assert.Equal(t, []string{"net6.0", "netstandard2.0", "net45"}, nuget.DocFrameworks(pkgDir), "Newest frameworks first, XML files without an assembly left out")
+
+	framework, files := nuget.DocFiles(pkgDir, "", []string{"net8.0", "netstandard2.0"})
+	assert.Equal(t, "netstandard2.0", framework, "A framework the project targets is preferred")
+	assert.Equal(t, []string{filepath.Join(pkgDir, "lib", "netstandard2.0", "Newtonsoft.Json.xml")}, files)
+	framework, _ = nuget.DocFiles(pkgDir, "", []string{"net8.0"})
+	assert.Equal(t, "net6.0", framework)
+	framework, files = nuget.DocFiles(pkgDir, "net7.0", nil)
+	assert.Empty(t, framework)
+	assert.Empty(t, files)
+
+	docs, err := nuget.ReadDocs(net45Docs(t, pkgDir))
+	require.NoError(t, err)
+	assert.Equal(t, "Newtonsoft.Json", docs.Assembly)
+	require.Len(t, docs.Members, 5)
+
+	convert := docs.Members[0]
+	assert.Equal(t, "type", convert.Kind)
+	assert.Equal(t, "Newtonsoft.Json.JsonConvert", convert.Name)
+	assert.Equal(t, "Provides methods for converting between .NET types and JSON types.", convert.Summary)
+	assert.Equal(t, "```\nstring json = JsonConvert.SerializeObject(product);\nProduct p = JsonConvert.DeserializeObject<Product>(json);\n```", convert.Example)
+
+	assert.Equal(t, nuget.Member{
+		ID:      "M:Newtonsoft.Json.JsonConvert.SerializeObject(System.Object)",
+		Kind:    "method",
+		Name:    "Newtonsoft.Json.JsonConvert.SerializeObject(System.Object)",
+		Summary: "Serializes the specified System.Object to a JSON string.",
+		Params:  []nuget.Param{{Name: "value", Text: "The object to serialize."}},
+		Returns: "A JSON string representation of the object.",
+	}, docs.Members[1])
+
+	deserialize := docs.Members[2]
+	assert.Equal(t, "Deserializes the JSON to the specified .NET type.\n\nReturns `default` when value is null.", deserialize.Summary)
+	assert.Equal(t, []nuget.Param{{Name: "T", Text: "The type of the object to deserialize to."}}, deserialize.TypeParams)
+	assert.Equal(t, []nuget.Param{{Name: "Newtonsoft.Json.JsonReaderException", Text: "The JSON is invalid."}}, deserialize.Exceptions)
+	assert.True(t, docs.Members[3].InheritDoc)
+
+	assert.Len(t, docs.Find("Newtonsoft.Json.JsonConvert"), 4, "A type matches its members")
+	assert.Len(t, docs.Find("Newtonsoft.Json.JsonConvert.DeserializeObject"), 1, "Generic methods match without their arity")
+	assert.Len(t, docs.Find("JsonConvert.SerializeObject"), 1, "Unqualified names match trailing segments")
+	assert.Len(t, docs.Find("JsonConverter"), 1)
+	assert.Len(t, docs.Find("Json"), 5, "Namespace segments match too")
+	assert.Empty(t, docs.Find("Xml"))
+	assert.Len(t, docs.Types(), 2)
+}
+
+func net45Docs(t *testing.T, pkgDir string) string {
+	_, files := nuget.DocFiles(pkgDir, "net45", nil)
+	require.Len(t, files, 1)
+	return files[0]
+}
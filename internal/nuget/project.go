@@ -0,0 +1,443 @@
+// Package nuget resolves the package references of .NET projects through
+// their project file and packages.lock.json, locates the packages in the
+// NuGet global packages folder and reads the XML documentation they ship.
+package nuget
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrNoProjectFile is returned when no .csproj file can be found for a
+// directory.
+var ErrNoProjectFile = errors.New("no .csproj file found")
+
+// Dependency kinds, named after the types of packages.lock.json.
+const (
+	KindDirect     = "Direct"
+	KindTransitive = "Transitive"
+)
+
+// Dependency is a package the project references, directly or through
+// other packages.
+type Dependency struct {
+	// ID is the package ID as the project spells it.
+	ID string `json:"id"`
+	// Requested is the version or range the project file declares.
+	Requested string `json:"requested,omitempty"`
+	Kind      string `json:"kind"`
+	// Resolved is the version restore picks: the one packages.lock.json
+	// records or, without a lockfile, the version requested.
+	Resolved string `json:"resolved,omitempty"`
+	// Dir is the directory of the package in the global packages folder,
+	// when found.
+	Dir string `json:"dir,omitempty"`
+}
+
+// Project is a .NET project on disk.
+type Project struct {
+	Dir string
+	// File is the path of the .csproj file.
+	File string
+	// TargetFrameworks are the frameworks the project builds for, such as
+	// net8.0.
+	TargetFrameworks []string
+	// Lockfile is the path of packages.lock.json, empty when there is none.
+	Lockfile string
+	// PackagesDir is the global packages folder.
+	PackagesDir string
+
+	references []reference
+	locked     []lockedPackage
+	warnings   []string
+}
+
+type msbuildProject struct {
+	PropertyGroups []struct {
+		Properties []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"PropertyGroup"`
+	ItemGroups []struct {
+		PackageReferences []packageReference `xml:"PackageReference"`
+		PackageVersions   []packageReference `xml:"PackageVersion"`
+	} `xml:"ItemGroup"`
+}
+
+type packageReference struct {
+	Include         string `xml:"Include,attr"`
+	Version         string `xml:"Version,attr"`
+	VersionOverride string `xml:"VersionOverride,attr"`
+	VersionElement  string `xml:"Version"`
+}
+
+func (r *packageReference) version() string {
+	switch {
+	case r.VersionOverride != "":
+		return r.VersionOverride
+	case r.Version != "":
+		return r.Version
+	}
+	return strings.TrimSpace(r.VersionElement)
+}
+
+// reference is a PackageReference with its version resolved.
+type reference struct {
+	id      string
+	version string
+}
+
+type lockedPackage struct {
+	id        string
+	kind      string
+	requested string
+	resolved  string
+}
+
+var propertyRE = regexp.MustCompile(`\$\(([^)]+)\)`)
+
+// LoadProject finds the .csproj file governing dir, walking up the
+// directory tree, and the packages.lock.json next to it. Versions managed
+// centrally in Directory.Packages.props apply to references without one.
+func LoadProject(dir string) (*Project, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	file, ok := FindProjectFile(absDir)
+	if !ok {
+		return nil, fmt.Errorf("%w in %s or any parent directory", ErrNoProjectFile, absDir)
+	}
+	p := &Project{Dir: filepath.Dir(file), File: file}
+	if others, _ := filepath.Glob(filepath.Join(p.Dir, "*.csproj")); len(others) > 1 {
+		p.warnings = append(p.warnings, fmt.Sprintf("%d project files in %s; using %s", len(others), p.Dir, filepath.Base(file)))
+	}
+
+	project, err := readMSBuild(file)
+	if err != nil {
+		return nil, err
+	}
+	properties := make(map[string]string)
+	for _, group := range project.PropertyGroups {
+		for _, property := range group.Properties {
+			properties[property.XMLName.Local] = strings.TrimSpace(property.Value)
+		}
+	}
+	if frameworks := properties["TargetFrameworks"]; frameworks != "" {
+		for _, framework := range strings.Split(frameworks, ";") {
+			if framework = strings.TrimSpace(framework); framework != "" {
+				p.TargetFrameworks = append(p.TargetFrameworks, framework)
+			}
+		}
+	} else if framework := properties["TargetFramework"]; framework != "" {
+		p.TargetFrameworks = []string{framework}
+	}
+
+	central := p.centralVersions()
+	for _, group := range project.ItemGroups {
+		for _, ref := range group.PackageReferences {
+			if ref.Include == "" {
+				continue
+			}
+			version := ref.version()
+			if version == "" {
+				version = central[strings.ToLower(ref.Include)]
+			}
+			version = propertyRE.ReplaceAllStringFunc(version, func(m string) string {
+				if value, ok := properties[m[2:len(m)-1]]; ok {
+					return value
+				}
+				return m
+			})
+			p.references = append(p.references, reference{id: ref.Include, version: version})
+		}
+	}
+
+	if err := p.loadLockfile(); err != nil {
+		return nil, err
+	}
+	p.PackagesDir = packagesDir(p.Dir)
+	return p, nil
+}
+
+// FindProjectFile returns the .csproj file in dir or the nearest parent
+// directory holding one. When a directory holds several, the one named
+// after the directory wins.
+func FindProjectFile(dir string) (string, bool) {
+	for current := dir; ; {
+		files, _ := filepath.Glob(filepath.Join(current, "*.csproj"))
+		if len(files) > 0 {
+			sort.Strings(files)
+			for _, file := range files {
+				if strings.TrimSuffix(filepath.Base(file), ".csproj") == filepath.Base(current) {
+					return file, true
+				}
+			}
+			return files[0], true
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", false
+		}
+		current = parent
+	}
+}
+
+func readMSBuild(path string) (*msbuildProject, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var project msbuildProject
+	if err := xml.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &project, nil
+}
+
+// centralVersions reads the PackageVersion items of the nearest
+// Directory.Packages.props, keyed by lowercased package ID.
+func (p *Project) centralVersions() map[string]string {
+	versions := make(map[string]string)
+	for current := p.Dir; ; {
+		path := filepath.Join(current, "Directory.Packages.props")
+		if _, err := os.Stat(path); err == nil {
+			props, err := readMSBuild(path)
+			if err != nil {
+				p.warnings = append(p.warnings, err.Error())
+				return versions
+			}
+			for _, group := range props.ItemGroups {
+				for _, ref := range group.PackageVersions {
+					versions[strings.ToLower(ref.Include)] = ref.version()
+				}
+			}
+			return versions
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return versions
+		}
+		current = parent
+	}
+}
+
+// loadLockfile reads packages.lock.json, which records the packages of
+// every target framework. The first framework in the project's order
+// decides the version of a package locked differently per framework.
+func (p *Project) loadLockfile() error {
+	path := filepath.Join(p.Dir, "packages.lock.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		p.warnings = append(p.warnings, "no packages.lock.json found; versions are the ones the project file requests (enable RestorePackagesWithLockFile to pin them)")
+		return nil
+	}
+	var lock struct {
+		Dependencies map[string]map[string]struct {
+			Type      string `json:"type"`
+			Requested string `json:"requested"`
+			Resolved  string `json:"resolved"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	p.Lockfile = path
+
+	frameworks := make([]string, 0, len(lock.Dependencies))
+	for framework := range lock.Dependencies {
+		frameworks = append(frameworks, framework)
+	}
+	sort.Slice(frameworks, func(i, j int) bool {
+		return p.frameworkOrder(frameworks[i]) < p.frameworkOrder(frameworks[j])
+	})
+	seen := make(map[string]int)
+	for _, framework := range frameworks {
+		for id, entry := range lock.Dependencies[framework] {
+			// Project references are not packages.
+			if entry.Type == "Project" || entry.Resolved == "" {
+				continue
+			}
+			kind := KindTransitive
+			if entry.Type == KindDirect {
+				kind = KindDirect
+			}
+			key := strings.ToLower(id)
+			if i, ok := seen[key]; ok {
+				if kind == KindDirect {
+					p.locked[i].kind = kind
+				}
+				continue
+			}
+			seen[key] = len(p.locked)
+			p.locked = append(p.locked, lockedPackage{id: id, kind: kind, requested: entry.Requested, resolved: entry.Resolved})
+		}
+	}
+	return nil
+}
+
+// frameworkOrder ranks a target framework of the lockfile, the project's
+// own frameworks first and runtime-specific sections, such as
+// net8.0/linux-x64, last.
+func (p *Project) frameworkOrder(framework string) int {
+	for i, target := range p.TargetFrameworks {
+		if strings.EqualFold(framework, target) {
+			return i
+		}
+	}
+	if strings.Contains(framework, "/") {
+		return len(p.TargetFrameworks) + 1
+	}
+	return len(p.TargetFrameworks)
+}
+
+// Warnings describes problems that may make the reported versions differ
+// from what restore picks.
+func (p *Project) Warnings() []string {
+	return p.warnings
+}
+
+// Dependencies lists the packages the project references and, with a
+// lockfile, the transitive ones, direct ones first and sorted by ID.
+func (p *Project) Dependencies() []Dependency {
+	deps := make([]Dependency, 0)
+	seen := make(map[string]bool)
+	for _, ref := range p.references {
+		key := strings.ToLower(ref.id)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		dep := Dependency{ID: ref.id, Requested: ref.version, Kind: KindDirect}
+		if locked := p.lockedPackage(ref.id); locked != nil {
+			dep.Resolved = locked.resolved
+		} else {
+			dep.Resolved = minVersion(ref.version)
+		}
+		dep.Dir = p.packageDir(dep.ID, dep.Resolved)
+		deps = append(deps, dep)
+	}
+	for _, locked := range p.locked {
+		key := strings.ToLower(locked.id)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deps = append(deps, Dependency{ID: locked.id, Requested: locked.requested, Kind: locked.kind, Resolved: locked.resolved, Dir: p.packageDir(locked.id, locked.resolved)})
+	}
+
+	sort.SliceStable(deps, func(i, j int) bool {
+		if deps[i].Kind != deps[j].Kind {
+			return deps[i].Kind == KindDirect
+		}
+		return strings.ToLower(deps[i].ID) < strings.ToLower(deps[j].ID)
+	})
+	return deps
+}
+
+// Dependency returns the package with the given ID, compared without case
+// as NuGet does.
+func (p *Project) Dependency(id string) (*Dependency, error) {
+	for _, dep := range p.Dependencies() {
+		if strings.EqualFold(dep.ID, id) {
+			return &dep, nil
+		}
+	}
+	return nil, fmt.Errorf("package %s is not a dependency of %s", id, filepath.Base(p.File))
+}
+
+func (p *Project) lockedPackage(id string) *lockedPackage {
+	for i, locked := range p.locked {
+		if strings.EqualFold(locked.id, id) {
+			return &p.locked[i]
+		}
+	}
+	return nil
+}
+
+// packageDir returns <packages folder>/<id>/<version>, both lowercased as
+// restore extracts them.
+func (p *Project) packageDir(id, version string) string {
+	if version == "" {
+		return ""
+	}
+	dir := filepath.Join(p.PackagesDir, strings.ToLower(id), strings.ToLower(version))
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir
+	}
+	return ""
+}
+
+// minVersion returns the version restore picks for a requested version or
+// range when it is known without a lockfile: the version itself, or the
+// inclusive lower bound of a range such as [1.2.0, ). Floating versions
+// such as 1.* are left unresolved.
+func minVersion(requested string) string {
+	requested = strings.TrimSpace(requested)
+	if requested == "" || strings.Contains(requested, "*") {
+		return ""
+	}
+	if strings.HasPrefix(requested, "[") {
+		lower, _, _ := strings.Cut(requested[1:], ",")
+		return strings.TrimSpace(strings.TrimSuffix(lower, "]"))
+	}
+	if strings.HasPrefix(requested, "(") {
+		return ""
+	}
+	return requested
+}
+
+// packagesDir returns the global packages folder: NUGET_PACKAGES, the
+// globalPackagesFolder of the nearest nuget.config, or ~/.nuget/packages.
+func packagesDir(projectDir string) string {
+	if dir := os.Getenv("NUGET_PACKAGES"); dir != "" {
+		return dir
+	}
+	for current := projectDir; ; {
+		for _, name := range []string{"nuget.config", "NuGet.Config", "NuGet.config"} {
+			if dir := globalPackagesFolder(filepath.Join(current, name)); dir != "" {
+				if !filepath.IsAbs(dir) {
+					dir = filepath.Join(current, filepath.FromSlash(dir))
+				}
+				return dir
+			}
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".nuget", "packages")
+}
+
+func globalPackagesFolder(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var config struct {
+		Settings []struct {
+			Key   string `xml:"key,attr"`
+			Value string `xml:"value,attr"`
+		} `xml:"config>add"`
+	}
+	if err := xml.Unmarshal(data, &config); err != nil {
+		return ""
+	}
+	for _, setting := range config.Settings {
+		if strings.EqualFold(setting.Key, "globalPackagesFolder") {
+			return setting.Value
+		}
+	}
+	return ""
+}
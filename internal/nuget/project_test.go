@@ -0,0 +1,110 @@
+package nuget_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/nuget"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+func TestLoadProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	packages := filepath.Join(tmpDir, "packages")
+	t.Setenv("NUGET_PACKAGES", packages)
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"Directory.Packages.props": `<Project>
+  <ItemGroup>
+    <PackageVersion Include="Serilog" Version="3.1.1" />
+  </ItemGroup>
+</Project>`,
+		"src/Api/Api.csproj": `<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup>
+    <TargetFrameworks>net8.0;net6.0</TargetFrameworks>
+    <JsonVersion>13.0.3</JsonVersion>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="$(JsonVersion)" />
+    <PackageReference Include="Serilog" />
+    <PackageReference Include="Polly">
+      <Version>[8.2.0, 9.0)</Version>
+    </PackageReference>
+  </ItemGroup>
+</Project>`,
+		"src/Api/Api.Tests.csproj": `<Project />`,
+		"src/Api/packages.lock.json": `{
+  "version": 1,
+  "dependencies": {
+    "net6.0": {
+      "Polly": {"type": "Direct", "requested": "[8.2.0, 9.0)", "resolved": "8.2.0"},
+      "Polly.Core": {"type": "Transitive", "resolved": "8.2.0"}
+    },
+    "net8.0": {
+      "Newtonsoft.Json": {"type": "Direct", "requested": "[13.0.3, )", "resolved": "13.0.3"},
+      "Polly": {"type": "Direct", "requested": "[8.2.0, 9.0)", "resolved": "8.2.1"},
+      "Polly.Core": {"type": "Transitive", "resolved": "8.2.1"},
+      "Serilog": {"type": "CentralTransitive", "requested": "[3.1.1, )", "resolved": "3.1.1"},
+      "Shared": {"type": "Project"}
+    }
+  }
+}`,
+		"src/Api/Controllers/.keep":                       "",
+		"packages/newtonsoft.json/13.0.3/.nupkg.metadata": "{}",
+		"packages/polly.core/8.2.1/.nupkg.metadata":       "{}",
+	})
+
+	project, err := nuget.LoadProject(filepath.Join(tmpDir, "src", "Api", "Controllers"))
+	require.NoError(t, err)
+	apiDir := filepath.Join(tmpDir, "src", "Api")
+	assert.Equal(t, filepath.Join(apiDir, "Api.csproj"), project.File, "The project named after its directory wins")
+	assert.Equal(t, []string{"net8.0", "net6.0"}, project.TargetFrameworks)
+	assert.Equal(t, filepath.Join(apiDir, "packages.lock.json"), project.Lockfile)
+	assert.Equal(t, packages, project.PackagesDir)
+	assert.Equal(t, []string{"2 project files in " + apiDir + "; using Api.csproj"}, project.Warnings())
+
+	assert.Equal(t, []nuget.Dependency{
+		{ID: "Newtonsoft.Json", Requested: "13.0.3", Kind: nuget.KindDirect, Resolved: "13.0.3", Dir: filepath.Join(packages, "newtonsoft.json", "13.0.3")},
+		{ID: "Polly", Requested: "[8.2.0, 9.0)", Kind: nuget.KindDirect, Resolved: "8.2.1"},
+		{ID: "Serilog", Requested: "3.1.1", Kind: nuget.KindDirect, Resolved: "3.1.1"},
+		{ID: "Polly.Core", Kind: nuget.KindTransitive, Resolved: "8.2.1", Dir: filepath.Join(packages, "polly.core", "8.2.1")},
+	}, project.Dependencies(), "The first target framework decides the locked version")
+
+	dep, err := project.Dependency("newtonsoft.json")
+	require.NoError(t, err)
+	assert.Equal(t, "Newtonsoft.Json", dep.ID)
+	_, err = project.Dependency("Dapper")
+	assert.ErrorContains(t, err, "package Dapper is not a dependency of Api.csproj")
+}
+
+func TestLoadProjectWithoutLockfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("NUGET_PACKAGES", "")
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"nuget.config": `<configuration>
+  <config>
+    <add key="globalPackagesFolder" value="cache" />
+  </config>
+</configuration>`,
+		"app/app.csproj": `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup><TargetFramework>net8.0</TargetFramework></PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Dapper" Version="2.1.*" />
+    <PackageReference Include="AutoMapper" Version="[12.0.1, )" />
+  </ItemGroup>
+</Project>`,
+	})
+
+	project, err := nuget.LoadProject(filepath.Join(tmpDir, "app"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmpDir, "cache"), project.PackagesDir)
+	assert.Contains(t, project.Warnings()[0], "no packages.lock.json found")
+	assert.Equal(t, []nuget.Dependency{
+		{ID: "AutoMapper", Requested: "[12.0.1, )", Kind: nuget.KindDirect, Resolved: "12.0.1"},
+		{ID: "Dapper", Requested: "2.1.*", Kind: nuget.KindDirect},
+	}, project.Dependencies(), "Floating versions are only resolved by restore")
+
+	_, err = nuget.LoadProject(t.TempDir())
+	assert.ErrorIs(t, err, nuget.ErrNoProjectFile)
+}
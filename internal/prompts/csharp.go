@@ -0,0 +1,25 @@
+package prompts
+
+import (
+	_ "embed"
+)
+
+//go:embed csharp.md
+var csharpPromptContent string
+
+type CSharpProvider struct{}
+
+func NewCSharpProvider() *CSharpProvider {
+	return &CSharpProvider{}
+}
+
+func (c *CSharpProvider) GetPrompts() []Prompt {
+	return []Prompt{
+		{
+			Name:        "csharp-context-rule",
+			Description: "Provides a systematic approach for working with NuGet packages by reading the versions in the project file and packages.lock.json and the XML documentation of the packages in the global packages folder",
+			Content:     csharpPromptContent,
+			Language:    "csharp",
+		},
+	}
+}
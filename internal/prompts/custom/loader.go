@@ -46,6 +46,12 @@ func LoadPromptsFromDirectory(promptsDir string) ([]prompts.Provider, error) {
 		config, cleanContent := parseConfig(content)
 		title := config["title"]
 		language := config["lang"]
+		var aliases []string
+		for _, alias := range strings.Split(config["aliases"], ",") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				aliases = append(aliases, alias)
+			}
+		}
 
 		description := title
 		if description == "" {
@@ -66,7 +72,20 @@ func LoadPromptsFromDirectory(promptsDir string) ([]prompts.Provider, error) {
 			continue
 		}
 
-		provider := newCustomPromptProvider(promptName, string(cleanContent), description, language)
+		if versions := config["versions"]; versions != "" {
+			if config["module"] == "" {
+				slog.Warn("Prompt file declares versions without a module, skipping", "file", entry.Name())
+				continue
+			}
+			if _, err := prompts.ParseConstraint(versions); err != nil {
+				slog.Warn("Invalid versions in prompt file, skipping", "file", entry.Name(), "error", err)
+				continue
+			}
+		}
+
+		provider := newCustomPromptProvider(promptName, string(cleanContent), description, language, aliases)
+		provider.module = config["module"]
+		provider.versions = config["versions"]
 		providers = append(providers, provider)
 	}
 
@@ -169,14 +188,18 @@ type customPromptProvider struct {
 	content     string
 	description string
 	language    string
+	aliases     []string
+	module      string
+	versions    string
 }
 
-func newCustomPromptProvider(name, content, description, language string) *customPromptProvider {
+func newCustomPromptProvider(name, content, description, language string, aliases []string) *customPromptProvider {
 	return &customPromptProvider{
 		name:        name,
 		content:     content,
 		description: description,
 		language:    language,
+		aliases:     aliases,
 	}
 }
 
@@ -188,6 +211,9 @@ func (c *customPromptProvider) GetPrompts() []prompts.Prompt {
 			Arguments:   []prompts.PromptArgument{},
 			Content:     c.content,
 			Language:    c.language,
+			Aliases:     c.aliases,
+			Module:      c.module,
+			Versions:    c.versions,
 		},
 	}
 }
@@ -84,3 +84,52 @@ func TestLoadPromptsFromDirectoryEmpty(t *testing.T) {
 	require.NoError(t, err, "Failed to load prompts from empty directory")
 	assert.Len(t, providers, 0, "Expected 0 providers")
 }
+
+func TestLoadPromptsFromDirectoryAliases(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `lang:cobol
+aliases: cbl, cob
+
+# COBOL copybooks
+Read the copybooks first.`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "cobol.md"), []byte(content), 0644))
+
+	providers, err := custom.LoadPromptsFromDirectory(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, providers, 1)
+	prompt := providers[0].GetPrompts()[0]
+	assert.Equal(t, []string{"cbl", "cob"}, prompt.Aliases)
+	assert.NotContains(t, prompt.Content, "aliases:")
+
+	registry := prompts.NewRegistry()
+	registry.Register(providers[0])
+	assert.Len(t, registry.GetPromptsByLanguage("cbl"), 1)
+}
+
+func TestLoadPromptsFromDirectoryDependencyNotes(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"nats-legacy.md": `module: github.com/nats-io/nats.go
+versions: < v1.30
+
+JetStream lives in the legacy package.`,
+		"invalid.md": `module: github.com/nats-io/nats.go
+versions: latest
+
+Never loaded.`,
+		"no-module.md": `versions: >= 1.0
+
+Never loaded.`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	providers, err := custom.LoadPromptsFromDirectory(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, providers, 1, "Prompts with invalid versions are skipped")
+	prompt := providers[0].GetPrompts()[0]
+	assert.Equal(t, "github.com/nats-io/nats.go", prompt.Module)
+	assert.Equal(t, "< v1.30", prompt.Versions)
+	assert.Equal(t, "JetStream lives in the legacy package.", prompt.Content)
+}
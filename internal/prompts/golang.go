@@ -1,24 +1,220 @@
 package prompts
 
-import _ "embed"
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/svetlyi/mcp-local-context/internal/goenv"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+)
 
 //go:embed golang.md
 var golangPromptContent string
 
-type GolangProvider struct{}
+//go:embed golang_project.md
+var golangProjectPromptContent string
+
+var golangProjectTemplate = template.Must(template.New("golang_project.md").Parse(golangProjectPromptContent))
 
-func NewGolangProvider() *GolangProvider {
-	return &GolangProvider{}
+type GolangProvider struct {
+	envOptions []goenv.Option
+}
+
+// NewGolangProvider creates the provider of the Go prompt. The options are
+// used to resolve the Go environment when the prompt is rendered.
+func NewGolangProvider(envOptions ...goenv.Option) *GolangProvider {
+	return &GolangProvider{envOptions: envOptions}
 }
 
 func (g *GolangProvider) GetPrompts() []Prompt {
 	return []Prompt{
 		{
 			Name:        "golang-context-rule",
-			Description: "Provides a systematic approach for working with third-party Go packages by referencing the Go module cache",
-			Arguments:   []PromptArgument{},
-			Content:     golangPromptContent,
-			Language:    "go",
+			Description: "Provides a systematic approach for working with third-party Go packages by referencing the Go module cache. With project_dir, the instructions hold the project's actual module cache paths and dependency versions",
+			Arguments: []PromptArgument{
+				{
+					Name:        "project_dir",
+					Description: "Directory of the Go project (or any directory below its go.mod)",
+				},
+				{
+					Name:        "module",
+					Description: "Module or package the task is about, e.g. github.com/nats-io/nats.go",
+				},
+			},
+			Content:  golangPromptContent,
+			Language: "go",
+			Render:   g.render,
 		},
 	}
 }
+
+type golangPromptModule struct {
+	Path     string
+	Version  string
+	Dir      string
+	Replace  string
+	Missing  bool
+	Packages []string
+	// RootPackage reports whether the module root is a package.
+	RootPackage bool
+}
+
+type golangPromptData struct {
+	ProjectPath  string
+	ProjectDir   string
+	GoVersion    string
+	GOROOT       string
+	GOMODCACHE   string
+	VendorDir    string
+	Warnings     []string
+	Module       *golangPromptModule
+	Dependencies []golangPromptModule
+}
+
+// render generates the prompt for the project in args["project_dir"],
+// naming the module in args["module"] if given. Without a project it
+// returns the generic prompt.
+func (g *GolangProvider) render(args map[string]string) (string, error) {
+	if args["project_dir"] == "" {
+		return golangPromptContent, nil
+	}
+
+	env, err := goenv.Detect(g.envOptions...)
+	if err != nil {
+		return "", fmt.Errorf("failed to detect Go environment: %w", err)
+	}
+	project, err := gomod.LoadProject(args["project_dir"], env.GOMODCACHE, gomod.WithModFlag(env.ModFlag()))
+	if err != nil {
+		return "", err
+	}
+
+	data := golangPromptData{
+		ProjectPath: project.Path,
+		ProjectDir:  project.Dir,
+		GoVersion:   project.GoVersion,
+		GOROOT:      env.GOROOT,
+		GOMODCACHE:  env.GOMODCACHE,
+		Warnings:    project.Warnings(),
+	}
+	if project.Vendor != nil && project.Vendor.Enabled {
+		data.VendorDir = project.Vendor.Dir
+	}
+
+	if name := args["module"]; name != "" {
+		mod := project.ModuleForImport(name)
+		if mod == nil || mod.Main {
+			return "", fmt.Errorf("module %s is not required by %s", name, project.Path)
+		}
+		promptModule, err := newGolangPromptModule(mod)
+		if err != nil {
+			return "", err
+		}
+		if !promptModule.Missing {
+			promptModule.Packages, err = topLevelPackages(mod.Path, mod.Dir)
+			if err != nil {
+				return "", err
+			}
+			promptModule.RootPackage = len(promptModule.Packages) > 0 && promptModule.Packages[0] == mod.Path
+		}
+		data.Module = &promptModule
+	} else {
+		for _, req := range project.File.Require {
+			if req.Indirect {
+				continue
+			}
+			promptModule, err := newGolangPromptModule(project.Module(req.Mod.Path))
+			if err != nil {
+				return "", err
+			}
+			data.Dependencies = append(data.Dependencies, promptModule)
+		}
+	}
+
+	var b strings.Builder
+	if err := golangProjectTemplate.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render Go prompt: %w", err)
+	}
+	return b.String(), nil
+}
+
+func newGolangPromptModule(mod *gomod.Module) (golangPromptModule, error) {
+	promptModule := golangPromptModule{Path: mod.Path, Version: mod.Version, Dir: mod.Dir}
+	if mod.Replace != nil {
+		promptModule.Replace = mod.Replace.Path
+		if mod.Replace.Version != "" {
+			promptModule.Replace += "@" + mod.Replace.Version
+		}
+	}
+	if _, err := os.Stat(mod.Dir); err != nil {
+		if !os.IsNotExist(err) {
+			return promptModule, fmt.Errorf("failed to access module directory: %w", err)
+		}
+		promptModule.Missing = true
+	}
+	return promptModule, nil
+}
+
+// topLevelPackages returns the import paths of the module's root package
+// and of its immediate subdirectories that are Go packages themselves, so
+// that each of them can be passed to go doc.
+func topLevelPackages(modulePath, dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module directory: %w", err)
+	}
+
+	var packages []string
+	for _, entry := range entries {
+		if !entry.IsDir() && isGoSource(entry.Name()) {
+			packages = append(packages, modulePath)
+			break
+		}
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || skipPackageDir(entry.Name()) {
+			continue
+		}
+		found, err := isGoPackage(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			packages = append(packages, path.Join(modulePath, entry.Name()))
+		}
+	}
+	return packages, nil
+}
+
+// isGoPackage reports whether dir directly holds non-test Go files of the
+// same module.
+func isGoPackage(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, fmt.Errorf("failed to read package directory: %w", err)
+	}
+	found := false
+	for _, entry := range entries {
+		if entry.Name() == "go.mod" {
+			// A nested module is not part of this one.
+			return false, nil
+		}
+		if !entry.IsDir() && isGoSource(entry.Name()) {
+			found = true
+		}
+	}
+	return found, nil
+}
+
+func isGoSource(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")
+}
+
+// skipPackageDir reports whether the go command ignores a directory.
+func skipPackageDir(name string) bool {
+	return name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
@@ -1,10 +1,13 @@
 package prompts
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/goenv"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
 )
 
 func TestGolangProvider(t *testing.T) {
@@ -17,5 +20,79 @@ func TestGolangProvider(t *testing.T) {
 	assert.Equal(t, "golang-context-rule", prompt.Name)
 	assert.NotEmpty(t, prompt.Description, "Prompt description should not be empty")
 	assert.NotEmpty(t, prompt.Content, "Prompt content should not be empty")
-	assert.Len(t, prompt.Arguments, 0, "Expected 0 arguments")
+	require.Len(t, prompt.Arguments, 2, "Expected 2 arguments")
+	assert.Equal(t, "project_dir", prompt.Arguments[0].Name)
+	assert.Equal(t, "module", prompt.Arguments[1].Name)
+	require.NotNil(t, prompt.Render)
+
+	content, err := prompt.Render(map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, prompt.Content, content, "Without a project the generic prompt is returned")
+}
+
+func TestGolangProviderRender(t *testing.T) {
+	tmpDir := t.TempDir()
+	modCache := filepath.Join(tmpDir, "modcache")
+	projectDir := filepath.Join(tmpDir, "app")
+	natsDir := filepath.Join(modCache, "github.com", "nats-io", "nats.go@v1.48.0")
+
+	testutil.WriteFiles(t, tmpDir, map[string]string{
+		"app/go.mod": `module example.com/app
+
+go 1.22
+
+require (
+	github.com/nats-io/nats.go v1.48.0
+	github.com/BurntSushi/toml v1.3.2
+	golang.org/x/text v0.14.0 // indirect
+)
+`,
+		"modcache/github.com/nats-io/nats.go@v1.48.0/nats.go":            "package nats\n",
+		"modcache/github.com/nats-io/nats.go@v1.48.0/jetstream/api.go":   "package jetstream\n",
+		"modcache/github.com/nats-io/nats.go@v1.48.0/internal/a/b/b.go":  "package b\n",
+		"modcache/github.com/nats-io/nats.go@v1.48.0/examples/README.md": "examples\n",
+		"modcache/github.com/nats-io/nats.go@v1.48.0/testdata/x.go":      "package x\n",
+		"modcache/github.com/nats-io/nats.go@v1.48.0/nested/go.mod":      "module github.com/nats-io/nats.go/nested\n",
+		"modcache/github.com/nats-io/nats.go@v1.48.0/nested/n.go":        "package nested\n",
+		"vendored/go.mod":             "module example.com/vendored\n\ngo 1.22\n\nrequire github.com/BurntSushi/toml v1.3.2\n",
+		"vendored/vendor/modules.txt": "# github.com/BurntSushi/toml v1.3.2\n## explicit; go 1.18\ngithub.com/BurntSushi/toml\n",
+	})
+
+	provider := NewGolangProvider(goenv.WithOverrides(map[string]string{"GOMODCACHE": modCache, "GOFLAGS": "-mod=mod"}))
+	prompt := provider.GetPrompts()[0]
+
+	content, err := prompt.Render(map[string]string{"project_dir": projectDir, "module": "github.com/nats-io/nats.go/jetstream"})
+	require.NoError(t, err)
+	assert.Contains(t, content, "Module cache (GOMODCACHE): `"+modCache+"`")
+	assert.Contains(t, content, "requires `github.com/nats-io/nats.go` at `v1.48.0`")
+	assert.Contains(t, content, "The sources are in `"+natsDir+"`")
+	assert.Contains(t, content, "- `github.com/nats-io/nats.go`\n")
+	assert.Contains(t, content, "- `github.com/nats-io/nats.go/jetstream`\n")
+	assert.NotContains(t, content, "nats.go/internal", "Directories without Go files of their own are not packages")
+	assert.Contains(t, content, "Run `go doc github.com/nats-io/nats.go` to get the documentation")
+	assert.NotContains(t, content, "nats.go/examples")
+	assert.NotContains(t, content, "nats.go/testdata")
+	assert.NotContains(t, content, "nats.go/nested")
+
+	content, err = prompt.Render(map[string]string{"project_dir": projectDir})
+	require.NoError(t, err)
+	assert.Contains(t, content, "`github.com/nats-io/nats.go` `v1.48.0`: `"+natsDir+"`")
+	assert.Contains(t, content, "`github.com/BurntSushi/toml` `v1.3.2`")
+	assert.Contains(t, content, "run `go mod download github.com/BurntSushi/toml@v1.3.2`")
+	assert.NotContains(t, content, "golang.org/x/text", "Indirect dependencies are not listed")
+
+	vendoredProvider := NewGolangProvider(goenv.WithOverrides(map[string]string{"GOMODCACHE": modCache, "GOFLAGS": "-mod=vendor"}))
+	vendoredPrompt := vendoredProvider.GetPrompts()[0]
+	vendoredDir := filepath.Join(tmpDir, "vendored")
+	content, err = vendoredPrompt.Render(map[string]string{"project_dir": vendoredDir})
+	require.NoError(t, err)
+	assert.Contains(t, content, "(not vendored, run `go mod vendor`)")
+	assert.NotContains(t, content, "go mod download")
+	content, err = vendoredPrompt.Render(map[string]string{"project_dir": vendoredDir, "module": "github.com/BurntSushi/toml"})
+	require.NoError(t, err)
+	assert.Contains(t, content, "Run `go mod vendor` first")
+	assert.NotContains(t, content, "go mod download")
+
+	_, err = prompt.Render(map[string]string{"project_dir": projectDir, "module": "github.com/unknown/mod"})
+	assert.ErrorContains(t, err, "module github.com/unknown/mod is not required by example.com/app")
 }
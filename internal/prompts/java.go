@@ -0,0 +1,33 @@
+package prompts
+
+import (
+	_ "embed"
+)
+
+//go:embed java.md
+var javaPromptContent string
+
+// JavaProvider serves the same rule for Java and Kotlin, which share the
+// Maven and Gradle caches.
+type JavaProvider struct{}
+
+func NewJavaProvider() *JavaProvider {
+	return &JavaProvider{}
+}
+
+func (j *JavaProvider) GetPrompts() []Prompt {
+	return []Prompt{
+		{
+			Name:        "java-context-rule",
+			Description: "Provides a systematic approach for working with third-party Java libraries by reading the versions in pom.xml or the Gradle lockfiles and the -sources.jar files in the Maven and Gradle caches",
+			Content:     javaPromptContent,
+			Language:    "java",
+		},
+		{
+			Name:        "kotlin-context-rule",
+			Description: "Provides a systematic approach for working with third-party Kotlin and Java libraries by reading the versions in the Gradle lockfiles or pom.xml and the -sources.jar files in the Gradle and Maven caches",
+			Content:     javaPromptContent,
+			Language:    "kotlin",
+		},
+	}
+}
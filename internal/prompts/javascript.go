@@ -0,0 +1,33 @@
+package prompts
+
+import (
+	_ "embed"
+)
+
+//go:embed javascript.md
+var javascriptPromptContent string
+
+type JavaScriptProvider struct{}
+
+func NewJavaScriptProvider() *JavaScriptProvider {
+	return &JavaScriptProvider{}
+}
+
+// GetPrompts returns the same instructions for JavaScript and TypeScript,
+// which share their packages and tooling.
+func (j *JavaScriptProvider) GetPrompts() []Prompt {
+	return []Prompt{
+		{
+			Name:        "javascript-context-rule",
+			Description: "Provides a systematic approach for working with third-party npm packages by reading the versions locked by the project and the installed copies in node_modules",
+			Content:     javascriptPromptContent,
+			Language:    "javascript",
+		},
+		{
+			Name:        "typescript-context-rule",
+			Description: "Provides a systematic approach for working with third-party npm packages from TypeScript by reading the locked versions and the installed .d.ts typings in node_modules",
+			Content:     javascriptPromptContent,
+			Language:    "typescript",
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package prompts
+
+import (
+	_ "embed"
+)
+
+//go:embed php.md
+var phpPromptContent string
+
+type PHPProvider struct{}
+
+func NewPHPProvider() *PHPProvider {
+	return &PHPProvider{}
+}
+
+func (p *PHPProvider) GetPrompts() []Prompt {
+	return []Prompt{
+		{
+			Name:        "php-context-rule",
+			Description: "Provides a systematic approach for working with third-party PHP packages by reading the versions locked in composer.lock and the sources composer installed in the vendor directory",
+			Content:     phpPromptContent,
+			Language:    "php",
+		},
+	}
+}
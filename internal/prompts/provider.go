@@ -1,11 +1,44 @@
 package prompts
 
+import (
+	"sort"
+	"strings"
+)
+
+// defaultAliases maps the names agents commonly use for a language to the
+// language key its prompts are registered under.
+var defaultAliases = map[string]string{
+	"golang":  "go",
+	"js":      "javascript",
+	"ts":      "javascript",
+	"node":    "javascript",
+	"nodejs":  "javascript",
+	"py":      "python",
+	"python3": "python",
+	"rs":      "rust",
+	"kt":      "kotlin",
+	"rb":      "ruby",
+	"cs":      "csharp",
+	"c#":      "csharp",
+	"dotnet":  "csharp",
+}
+
 type Prompt struct {
 	Name        string           `json:"name"`
 	Description string           `json:"description"`
 	Arguments   []PromptArgument `json:"arguments,omitempty"`
 	Content     string           `json:"content"`
 	Language    string           `json:"language,omitempty"`
+	// Aliases are other names the language of the prompt is requested
+	// by, in addition to the registry's alias table.
+	Aliases []string `json:"aliases,omitempty"`
+	// Module, when set, is the dependency the prompt holds notes about,
+	// and Versions the semver constraint of the versions they apply to.
+	Module   string `json:"module,omitempty"`
+	Versions string `json:"versions,omitempty"`
+	// Render, when set, generates the content from the arguments of a
+	// prompt request, so it can refer to the caller's project.
+	Render func(args map[string]string) (string, error) `json:"-"`
 }
 
 type PromptArgument struct {
@@ -20,11 +53,25 @@ type Provider interface {
 
 type Registry struct {
 	providers []Provider
+	aliases   map[string]string
 }
 
+// NewRegistry creates a registry resolving languages through a copy of
+// the default aliases.
 func NewRegistry() *Registry {
-	return &Registry{
+	r := &Registry{
 		providers: make([]Provider, 0),
+		aliases:   make(map[string]string, len(defaultAliases)),
+	}
+	r.AddAliases(defaultAliases)
+	return r
+}
+
+// AddAliases adds entries to the alias table, replacing those with the
+// same alias. Aliases and languages are compared case-insensitively.
+func (r *Registry) AddAliases(aliases map[string]string) {
+	for alias, language := range aliases {
+		r.aliases[normalizeLanguage(alias)] = normalizeLanguage(language)
 	}
 }
 
@@ -49,11 +96,14 @@ func (r *Registry) GetPrompt(name string) *Prompt {
 	return nil
 }
 
+// GetSupportedLanguages lists the canonical languages prompts are
+// registered for, sorted. Dependency notes do not make a language
+// supported.
 func (r *Registry) GetSupportedLanguages() []string {
 	languageMap := make(map[string]bool)
 	for _, prompt := range r.GetAllPrompts() {
-		if prompt.Language != "" {
-			languageMap[prompt.Language] = true
+		if prompt.Language != "" && prompt.Module == "" {
+			languageMap[r.resolveAlias(prompt.Language)] = true
 		}
 	}
 
@@ -61,15 +111,108 @@ func (r *Registry) GetSupportedLanguages() []string {
 	for lang := range languageMap {
 		languages = append(languages, lang)
 	}
+	sort.Strings(languages)
 	return languages
 }
 
+// CanonicalLanguage resolves a language name through the alias table, then
+// through the aliases prompts declare, e.g. golang to go. Names that are
+// no alias are returned lowercased.
+func (r *Registry) CanonicalLanguage(language string) string {
+	name := normalizeLanguage(language)
+	if canonical, ok := r.aliases[name]; ok {
+		return canonical
+	}
+	for _, prompt := range r.GetAllPrompts() {
+		if prompt.Language == "" {
+			continue
+		}
+		for _, alias := range prompt.Aliases {
+			if normalizeLanguage(alias) == name {
+				return r.resolveAlias(prompt.Language)
+			}
+		}
+	}
+	return name
+}
+
+// GetPromptsByLanguage returns the prompts of the canonical language of
+// language, so that prompts registered under an alias, e.g. a custom
+// prompt for golang, are found together with those of go. Dependency notes
+// are left to GetDependencyNotes, which checks their version constraint.
 func (r *Registry) GetPromptsByLanguage(language string) []Prompt {
+	canonical := r.CanonicalLanguage(language)
 	var result []Prompt
 	for _, prompt := range r.GetAllPrompts() {
-		if prompt.Language == language {
+		if prompt.Language != "" && prompt.Module == "" && r.resolveAlias(prompt.Language) == canonical {
 			result = append(result, prompt)
 		}
 	}
 	return result
 }
+
+// resolveAlias resolves a language through the alias table only.
+func (r *Registry) resolveAlias(language string) string {
+	name := normalizeLanguage(language)
+	if canonical, ok := r.aliases[name]; ok {
+		return canonical
+	}
+	return name
+}
+
+func normalizeLanguage(language string) string {
+	return strings.ToLower(strings.TrimSpace(language))
+}
+
+// caseInsensitiveEcosystems are the ecosystems whose package names match
+// regardless of case: NuGet, Composer and PyPI.
+var caseInsensitiveEcosystems = map[string]bool{
+	"csharp": true,
+	"php":    true,
+	"python": true,
+}
+
+// languageEcosystems maps the languages that share another language's
+// package ecosystem to it.
+var languageEcosystems = map[string]string{
+	"typescript": "javascript",
+	"kotlin":     "java",
+}
+
+// GetDependencyNotes returns the prompts about the dependency module whose
+// version constraint, if any, the resolved version satisfies. Prompts that
+// declare a language only apply to that language's ecosystem. Module names
+// are compared exactly, except in ecosystems whose package names are
+// case-insensitive.
+func (r *Registry) GetDependencyNotes(ecosystem, module, version string) []Prompt {
+	foldCase := caseInsensitiveEcosystems[ecosystem]
+	var result []Prompt
+	for _, prompt := range r.GetAllPrompts() {
+		if prompt.Module == "" {
+			continue
+		}
+		if prompt.Language != "" && r.languageEcosystem(prompt.Language) != ecosystem {
+			continue
+		}
+		if prompt.Module != module && (!foldCase || !strings.EqualFold(prompt.Module, module)) {
+			continue
+		}
+		if prompt.Versions != "" {
+			constraint, err := ParseConstraint(prompt.Versions)
+			if err != nil || !constraint.Check(version) {
+				continue
+			}
+		}
+		result = append(result, prompt)
+	}
+	return result
+}
+
+// languageEcosystem returns the package ecosystem of a language.
+func (r *Registry) languageEcosystem(language string) string {
+	canonical := r.CanonicalLanguage(language)
+	if ecosystem, ok := languageEcosystems[canonical]; ok {
+		return ecosystem
+	}
+	return canonical
+}
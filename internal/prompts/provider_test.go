@@ -58,3 +58,91 @@ func TestRegistryGetPromptsByLanguage(t *testing.T) {
 	emptyPrompts := registry.GetPromptsByLanguage("nonexistent")
 	assert.Len(t, emptyPrompts, 0, "Expected 0 prompts for nonexistent language")
 }
+
+type aliasedProvider struct{}
+
+func (aliasedProvider) GetPrompts() []Prompt {
+	return []Prompt{
+		{Name: "cobol-rule", Content: "cobol", Language: "cobol", Aliases: []string{"cbl", "COB"}},
+		{Name: "golang-extra", Content: "extra", Language: "golang"},
+	}
+}
+
+func TestRegistryAliases(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewGolangProvider())
+	registry.Register(NewJavaScriptProvider())
+	registry.Register(aliasedProvider{})
+
+	assert.Equal(t, "go", registry.CanonicalLanguage("golang"))
+	assert.Equal(t, "go", registry.CanonicalLanguage(" GoLang "))
+	assert.Equal(t, "javascript", registry.CanonicalLanguage("node"))
+	assert.Equal(t, "javascript", registry.CanonicalLanguage("ts"))
+	assert.Equal(t, "python", registry.CanonicalLanguage("py"))
+	assert.Equal(t, "cobol", registry.CanonicalLanguage("cob"), "Prompts can declare aliases")
+	assert.Equal(t, "fortran", registry.CanonicalLanguage("Fortran"))
+
+	prompts := registry.GetPromptsByLanguage("golang")
+	require.Len(t, prompts, 2, "Prompts registered under an alias join the canonical language")
+	assert.Equal(t, "golang-context-rule", prompts[0].Name)
+	assert.Equal(t, "golang-extra", prompts[1].Name)
+	assert.Len(t, registry.GetPromptsByLanguage("cbl"), 1)
+	assert.Equal(t, []string{"cobol", "go", "javascript", "typescript"}, registry.GetSupportedLanguages())
+
+	prompts = registry.GetPromptsByLanguage("ts")
+	require.Len(t, prompts, 1)
+	assert.Equal(t, "javascript-context-rule", prompts[0].Name)
+
+	registry.AddAliases(map[string]string{"Gopher": "go", "ts": "typescript"})
+	assert.Equal(t, "go", registry.CanonicalLanguage("gopher"))
+	prompts = registry.GetPromptsByLanguage("ts")
+	require.Len(t, prompts, 1, "Configured aliases replace the defaults")
+	assert.Equal(t, "typescript-context-rule", prompts[0].Name)
+	assert.Equal(t, "javascript", NewRegistry().CanonicalLanguage("ts"), "Aliases added to one registry do not leak into others")
+}
+
+func TestLanguageProviders(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewGolangProvider())
+	registry.Register(NewJavaScriptProvider())
+	registry.Register(NewPythonProvider())
+	registry.Register(NewRustProvider())
+	registry.Register(NewJavaProvider())
+	registry.Register(NewRubyProvider())
+	registry.Register(NewPHPProvider())
+	registry.Register(NewCSharpProvider())
+
+	tests := []struct {
+		language string
+		aliases  []string
+		prompt   string
+		// mentions is a tool or file the instructions must point to.
+		mentions string
+	}{
+		{language: "go", aliases: []string{"golang"}, prompt: "golang-context-rule", mentions: "go doc"},
+		{language: "javascript", aliases: []string{"js", "ts", "node", "nodejs"}, prompt: "javascript-context-rule", mentions: "read_npm_typings"},
+		{language: "typescript", prompt: "typescript-context-rule", mentions: "read_npm_typings"},
+		{language: "python", aliases: []string{"py", "python3"}, prompt: "python-context-rule", mentions: "read_python_module"},
+		{language: "rust", aliases: []string{"rs"}, prompt: "rust-context-rule", mentions: "read_rust_item"},
+		{language: "java", prompt: "java-context-rule", mentions: "read_jvm_class"},
+		{language: "kotlin", aliases: []string{"kt"}, prompt: "kotlin-context-rule", mentions: "read_jvm_class"},
+		{language: "ruby", aliases: []string{"rb"}, prompt: "ruby-context-rule", mentions: "Gemfile.lock"},
+		{language: "php", prompt: "php-context-rule", mentions: "composer.lock"},
+		{language: "csharp", aliases: []string{"cs", "C#", "dotnet"}, prompt: "csharp-context-rule", mentions: "read_nuget_docs"},
+	}
+	languages := make([]string, 0, len(tests))
+	for _, tt := range tests {
+		languages = append(languages, tt.language)
+		t.Run(tt.language, func(t *testing.T) {
+			for _, name := range append([]string{tt.language}, tt.aliases...) {
+				assert.Equal(t, tt.language, registry.CanonicalLanguage(name))
+				prompts := registry.GetPromptsByLanguage(name)
+				require.Len(t, prompts, 1, "Expected 1 prompt for %s", name)
+				assert.Equal(t, tt.prompt, prompts[0].Name)
+				assert.NotEmpty(t, prompts[0].Description)
+				assert.Contains(t, prompts[0].Content, tt.mentions)
+			}
+		})
+	}
+	assert.ElementsMatch(t, languages, registry.GetSupportedLanguages())
+}
@@ -0,0 +1,25 @@
+package prompts
+
+import (
+	_ "embed"
+)
+
+//go:embed python.md
+var pythonPromptContent string
+
+type PythonProvider struct{}
+
+func NewPythonProvider() *PythonProvider {
+	return &PythonProvider{}
+}
+
+func (p *PythonProvider) GetPrompts() []Prompt {
+	return []Prompt{
+		{
+			Name:        "python-context-rule",
+			Description: "Provides a systematic approach for working with third-party Python packages by reading the versions and sources installed in the project's virtual environment",
+			Content:     pythonPromptContent,
+			Language:    "python",
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package prompts
+
+import (
+	_ "embed"
+)
+
+//go:embed ruby.md
+var rubyPromptContent string
+
+type RubyProvider struct{}
+
+func NewRubyProvider() *RubyProvider {
+	return &RubyProvider{}
+}
+
+func (r *RubyProvider) GetPrompts() []Prompt {
+	return []Prompt{
+		{
+			Name:        "ruby-context-rule",
+			Description: "Provides a systematic approach for working with third-party Ruby gems by reading the versions locked in Gemfile.lock and the sources bundler installed",
+			Content:     rubyPromptContent,
+			Language:    "ruby",
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package prompts
+
+import (
+	_ "embed"
+)
+
+//go:embed rust.md
+var rustPromptContent string
+
+type RustProvider struct{}
+
+func NewRustProvider() *RustProvider {
+	return &RustProvider{}
+}
+
+func (r *RustProvider) GetPrompts() []Prompt {
+	return []Prompt{
+		{
+			Name:        "rust-context-rule",
+			Description: "Provides a systematic approach for working with third-party Rust crates by reading the versions locked in Cargo.lock and the sources cargo unpacked in its registry cache",
+			Content:     rustPromptContent,
+			Language:    "rust",
+		},
+	}
+}
@@ -0,0 +1,107 @@
+package prompts
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Constraint is a semver constraint such as "< v1.30" or ">= 2.0, < 3 ||
+// >= 4". Comparators separated by commas or spaces must all hold, and
+// alternatives separated by || are tried in turn.
+type Constraint struct {
+	alternatives [][]comparator
+}
+
+type comparator struct {
+	op      string
+	version string
+}
+
+var comparatorRE = regexp.MustCompile(`^\s*(<=|>=|!=|==|<|>|=)?\s*([vV]?[0-9][^\s,<>=!|]*)\s*,?`)
+
+// ParseConstraint parses a semver constraint. Versions may omit the v
+// prefix and the minor and patch numbers.
+func ParseConstraint(s string) (*Constraint, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, fmt.Errorf("empty version constraint")
+	}
+	c := &Constraint{}
+	for _, alternative := range strings.Split(s, "||") {
+		var comparators []comparator
+		for rest := alternative; strings.TrimSpace(rest) != ""; {
+			m := comparatorRE.FindStringSubmatch(rest)
+			if m == nil {
+				return nil, fmt.Errorf("invalid version constraint %q", s)
+			}
+			version := normalizeVersion(m[2])
+			if !semver.IsValid(version) {
+				return nil, fmt.Errorf("invalid version %q in constraint %q", m[2], s)
+			}
+			op := m[1]
+			if op == "" || op == "==" {
+				op = "="
+			}
+			comparators = append(comparators, comparator{op: op, version: version})
+			rest = rest[len(m[0]):]
+		}
+		if len(comparators) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q", s)
+		}
+		c.alternatives = append(c.alternatives, comparators)
+	}
+	return c, nil
+}
+
+// Check reports whether version satisfies the constraint. Versions that
+// are not semver, such as a Python post-release, satisfy none.
+func (c *Constraint) Check(version string) bool {
+	version = normalizeVersion(version)
+	if !semver.IsValid(version) {
+		return false
+	}
+	for _, comparators := range c.alternatives {
+		if matchesAll(comparators, version) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAll(comparators []comparator, version string) bool {
+	for _, cmp := range comparators {
+		result := semver.Compare(version, cmp.version)
+		var ok bool
+		switch cmp.op {
+		case "<":
+			ok = result < 0
+		case "<=":
+			ok = result <= 0
+		case ">":
+			ok = result > 0
+		case ">=":
+			ok = result >= 0
+		case "!=":
+			ok = result != 0
+		default:
+			ok = result == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if version != "" && version[0] != 'v' {
+		if version[0] == 'V' {
+			version = version[1:]
+		}
+		version = "v" + version
+	}
+	return version
+}
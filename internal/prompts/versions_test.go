@@ -0,0 +1,102 @@
+package prompts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"< v1.30", "v1.29.3", true},
+		{"< v1.30", "v1.30.0", false},
+		{"<v1.30", "1.2.0", true},
+		{">= 2.0, < 3", "2.5.1", true},
+		{">=2.0 <3", "3.0.0", false},
+		{">= 2.0, < 3 || >= 4", "4.1.0", true},
+		{"1.2.3", "v1.2.3", true},
+		{"!= 1.2.3", "1.2.4", true},
+		{"> 1.0.0", "1.0.1-rc.1", true},
+		{"< 1.0.0", "1.0.0-rc.1", true},
+		{">= v1.0.0", "v1.1.0-0.20240101000000-abcdefabcdef", true},
+		{">= 1.0", "1.0.post1", false},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		require.NoError(t, err, tt.constraint)
+		assert.Equal(t, tt.want, c.Check(tt.version), "%s %s", tt.version, tt.constraint)
+	}
+
+	for _, invalid := range []string{"", "latest", ">= 1.x", "< 1.0 ||"} {
+		_, err := ParseConstraint(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+type notesProvider struct{}
+
+func (notesProvider) GetPrompts() []Prompt {
+	return []Prompt{
+		{Name: "nats-legacy", Content: "legacy", Module: "github.com/nats-io/nats.go", Versions: "< v1.30"},
+		{Name: "nats-jetstream", Content: "jetstream", Module: "github.com/nats-io/nats.go", Versions: ">= v1.30"},
+		{Name: "nats-all", Content: "all", Module: "github.com/nats-io/nats.go"},
+		{Name: "go-rule", Content: "go", Language: "go"},
+		{Name: "newtonsoft", Content: "json", Module: "Newtonsoft.Json"},
+		{Name: "requests-python", Content: "python", Module: "requests", Language: "py"},
+		{Name: "zod-typescript", Content: "typescript", Module: "zod", Language: "typescript"},
+	}
+}
+
+func TestRegistryGetDependencyNotes(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(notesProvider{})
+
+	var names []string
+	for _, prompt := range registry.GetDependencyNotes("go", "github.com/nats-io/nats.go", "v1.29.0") {
+		names = append(names, prompt.Name)
+	}
+	assert.Equal(t, []string{"nats-legacy", "nats-all"}, names)
+
+	names = nil
+	for _, prompt := range registry.GetDependencyNotes("go", "github.com/nats-io/nats.go", "v1.48.0") {
+		names = append(names, prompt.Name)
+	}
+	assert.Equal(t, []string{"nats-jetstream", "nats-all"}, names)
+
+	assert.Empty(t, registry.GetDependencyNotes("go", "github.com/stretchr/testify", "v1.11.1"))
+	assert.Empty(t, registry.GetDependencyNotes("go", "github.com/NATS-io/nats.go", "v1.48.0"), "Go module paths are case-sensitive")
+
+	notes := registry.GetDependencyNotes("csharp", "newtonsoft.json", "13.0.3")
+	require.Len(t, notes, 1, "NuGet package ids are case-insensitive")
+	assert.Equal(t, "newtonsoft", notes[0].Name)
+	assert.Empty(t, registry.GetDependencyNotes("javascript", "newtonsoft.json", "13.0.3"))
+
+	notes = registry.GetDependencyNotes("python", "requests", "2.32.0")
+	require.Len(t, notes, 1)
+	assert.Equal(t, "requests-python", notes[0].Name)
+	assert.Empty(t, registry.GetDependencyNotes("javascript", "requests", "2.32.0"), "Notes for another language's package of the same name are skipped")
+
+	notes = registry.GetDependencyNotes("javascript", "zod", "3.23.8")
+	require.Len(t, notes, 1, "TypeScript notes apply to npm packages")
+	assert.Equal(t, "zod-typescript", notes[0].Name)
+}
+
+func TestRegistryNotesNotByLanguage(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewGolangProvider())
+	registry.Register(notesProvider{})
+
+	var names []string
+	for _, prompt := range registry.GetPromptsByLanguage("go") {
+		names = append(names, prompt.Name)
+	}
+	assert.Equal(t, []string{"golang-context-rule", "go-rule"}, names, "Dependency notes are only served by GetDependencyNotes")
+	assert.Empty(t, registry.GetPromptsByLanguage("python"), "Notes declaring a language are not returned for it")
+	assert.Empty(t, registry.GetPromptsByLanguage("typescript"))
+	assert.Equal(t, []string{"go"}, registry.GetSupportedLanguages(), "A note does not make its language supported")
+}
@@ -0,0 +1,185 @@
+package pyenv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Distribution is an installed package, described by its .dist-info (or
+// legacy .egg-info) directory.
+type Distribution struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Summary string `json:"summary,omitempty"`
+	// ImportNames are the top-level modules and packages the distribution
+	// installs, e.g. yaml for PyYAML.
+	ImportNames []string `json:"import_names"`
+	MetadataDir string   `json:"metadata_dir"`
+}
+
+// Distributions lists the distributions installed in site-packages,
+// sorted by name.
+func (e *Env) Distributions() ([]Distribution, error) {
+	entries, err := os.ReadDir(e.SitePackages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read site-packages: %w", err)
+	}
+	dists := make([]Distribution, 0)
+	for _, entry := range entries {
+		name := entry.Name()
+		var metadataFile string
+		switch {
+		case !entry.IsDir():
+			continue
+		case strings.HasSuffix(name, ".dist-info"):
+			metadataFile = "METADATA"
+		case strings.HasSuffix(name, ".egg-info"):
+			metadataFile = "PKG-INFO"
+		default:
+			continue
+		}
+		dir := filepath.Join(e.SitePackages, name)
+		dist, err := readDistribution(dir, metadataFile)
+		if err != nil {
+			continue
+		}
+		dists = append(dists, *dist)
+	}
+	sort.Slice(dists, func(i, j int) bool {
+		return strings.ToLower(dists[i].Name) < strings.ToLower(dists[j].Name)
+	})
+	return dists, nil
+}
+
+// Distribution returns the installed distribution with the given name,
+// compared the way pip normalizes names.
+func (e *Env) Distribution(name string) (*Distribution, error) {
+	dists, err := e.Distributions()
+	if err != nil {
+		return nil, err
+	}
+	for _, dist := range dists {
+		if NormalizeName(dist.Name) == NormalizeName(name) {
+			return &dist, nil
+		}
+	}
+	return nil, fmt.Errorf("distribution %s is not installed in %s", name, e.Prefix)
+}
+
+// NormalizeName normalizes a distribution name as PEP 503 does: lowercase,
+// with runs of -, _ and . replaced by -.
+func NormalizeName(name string) string {
+	var b strings.Builder
+	separator := false
+	for _, r := range strings.ToLower(name) {
+		if r == '-' || r == '_' || r == '.' {
+			separator = true
+			continue
+		}
+		if separator && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		separator = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func readDistribution(dir, metadataFile string) (*Distribution, error) {
+	data, err := os.ReadFile(filepath.Join(dir, metadataFile))
+	if err != nil {
+		return nil, err
+	}
+	dist := &Distribution{MetadataDir: dir}
+	// The metadata is in email header format; the body is the description.
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Name":
+			dist.Name = value
+		case "Version":
+			dist.Version = value
+		case "Summary":
+			dist.Summary = value
+		}
+	}
+	if dist.Name == "" {
+		return nil, fmt.Errorf("%s has no Name", filepath.Join(dir, metadataFile))
+	}
+	dist.ImportNames = importNames(dir)
+	return dist, nil
+}
+
+// importNames reads top_level.txt, falling back to the top-level entries
+// of RECORD, which lists every installed file.
+func importNames(dir string) []string {
+	names := make(map[string]bool)
+	if data, err := os.ReadFile(filepath.Join(dir, "top_level.txt")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if name := strings.TrimSpace(line); name != "" {
+				names[strings.ReplaceAll(name, "/", ".")] = true
+			}
+		}
+	} else if data, err := os.ReadFile(filepath.Join(dir, "RECORD")); err == nil {
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+		records, _ := reader.ReadAll()
+		for _, record := range records {
+			if len(record) == 0 {
+				continue
+			}
+			if name := recordImportName(record[0]); name != "" {
+				names[name] = true
+			}
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// recordImportName returns the top-level import name a RECORD path
+// installs, or "" for metadata, scripts and other non-module files.
+func recordImportName(path string) string {
+	first, rest, nested := strings.Cut(path, "/")
+	if nested {
+		if first == ".." || first == "__pycache__" || strings.HasSuffix(first, ".dist-info") || strings.HasSuffix(first, ".data") {
+			return ""
+		}
+		if !strings.HasSuffix(rest, ".py") && !strings.HasSuffix(rest, ".pyi") && !isExtension(rest) {
+			return ""
+		}
+		return first
+	}
+	switch {
+	case strings.HasSuffix(first, ".py"):
+		return strings.TrimSuffix(first, ".py")
+	case isExtension(first):
+		name, _, _ := strings.Cut(first, ".")
+		return name
+	}
+	return ""
+}
+
+func isExtension(name string) bool {
+	return strings.HasSuffix(name, ".so") || strings.HasSuffix(name, ".pyd")
+}
@@ -0,0 +1,111 @@
+// Package pyenv inspects the packages installed in a Python virtual
+// environment: the distributions in site-packages, the import names they
+// provide and the sources and stubs of their modules.
+package pyenv
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+)
+
+// ErrNoEnvironment is returned when no virtual environment can be found.
+var ErrNoEnvironment = errors.New("python virtual environment not found")
+
+// venvNames are the directory names searched for a virtual environment.
+var venvNames = []string{".venv", "venv"}
+
+// Env is a Python environment and its site-packages directory.
+type Env struct {
+	Prefix string `json:"prefix"`
+	// Version is the Python version from pyvenv.cfg, when known.
+	Version      string `json:"version,omitempty"`
+	SitePackages string `json:"site_packages"`
+	// Source tells how the environment was found: "config", "VIRTUAL_ENV"
+	// or the name of the project directory it was found in.
+	Source string `json:"source"`
+}
+
+// FindEnv locates the environment of the project in projectDir. A
+// configured prefix comes first, then a .venv or venv directory in the
+// project directory or one of its parents. The VIRTUAL_ENV the server was
+// started with is only a fallback, as it may belong to another project.
+func FindEnv(projectDir, configuredPrefix string) (*Env, error) {
+	if configuredPrefix != "" {
+		return openEnv(configuredPrefix, "config")
+	}
+	dir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	for current := dir; ; {
+		for _, name := range venvNames {
+			prefix := filepath.Join(current, name)
+			if fsutil.IsFile(filepath.Join(prefix, "pyvenv.cfg")) {
+				return openEnv(prefix, current)
+			}
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+	if active := os.Getenv("VIRTUAL_ENV"); active != "" {
+		return openEnv(active, "VIRTUAL_ENV")
+	}
+	return nil, fmt.Errorf("%w: no .venv or venv directory in %s or its parents and no VIRTUAL_ENV; set python_prefix in the config", ErrNoEnvironment, dir)
+}
+
+func openEnv(prefix, source string) (*Env, error) {
+	prefix, err := filepath.Abs(prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve environment prefix: %w", err)
+	}
+	env := &Env{Prefix: prefix, Source: source, Version: venvVersion(prefix)}
+
+	// POSIX layouts use lib/pythonX.Y/site-packages, Windows Lib/site-packages.
+	candidates, _ := filepath.Glob(filepath.Join(prefix, "lib", "python*", "site-packages"))
+	sort.Strings(candidates)
+	if env.Version != "" {
+		if parts := strings.SplitN(env.Version, ".", 3); len(parts) >= 2 {
+			preferred := filepath.Join(prefix, "lib", "python"+parts[0]+"."+parts[1], "site-packages")
+			candidates = append([]string{preferred}, candidates...)
+		}
+	}
+	candidates = append(candidates, filepath.Join(prefix, "Lib", "site-packages"))
+	for _, candidate := range candidates {
+		if fsutil.IsDir(candidate) {
+			env.SitePackages = candidate
+			return env, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s has no site-packages directory", ErrNoEnvironment, prefix)
+}
+
+// venvVersion reads the Python version from the environment's pyvenv.cfg.
+func venvVersion(prefix string) string {
+	f, err := os.Open(filepath.Join(prefix, "pyvenv.cfg"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "version", "version_info":
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
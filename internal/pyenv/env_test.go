@@ -0,0 +1,115 @@
+package pyenv_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/pyenv"
+	"github.com/svetlyi/mcp-local-context/internal/testutil"
+)
+
+// writeVenv creates a virtual environment in dir/.venv with the given
+// files in its site-packages directory.
+func writeVenv(t *testing.T, dir string, packages map[string]string) string {
+	t.Helper()
+	files := map[string]string{
+		".venv/pyvenv.cfg": "home = /usr/bin\ninclude-system-site-packages = false\nversion = 3.12.4\n",
+	}
+	for name, content := range packages {
+		files[".venv/lib/python3.12/site-packages/"+name] = content
+	}
+	testutil.WriteFiles(t, dir, files)
+	sitePackages := filepath.Join(dir, ".venv", "lib", "python3.12", "site-packages")
+	require.NoError(t, os.MkdirAll(sitePackages, 0o755))
+	return sitePackages
+}
+
+func TestFindEnv(t *testing.T) {
+	t.Setenv("VIRTUAL_ENV", "")
+	tmpDir := t.TempDir()
+	sitePackages := writeVenv(t, tmpDir, nil)
+	nested := filepath.Join(tmpDir, "src", "app")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+
+	env, err := pyenv.FindEnv(nested, "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmpDir, ".venv"), env.Prefix)
+	assert.Equal(t, "3.12.4", env.Version)
+	assert.Equal(t, sitePackages, env.SitePackages)
+	assert.Equal(t, tmpDir, env.Source)
+
+	env, err = pyenv.FindEnv(t.TempDir(), filepath.Join(tmpDir, ".venv"))
+	require.NoError(t, err)
+	assert.Equal(t, "config", env.Source, "The configured prefix comes first")
+
+	_, err = pyenv.FindEnv(t.TempDir(), "")
+	assert.ErrorIs(t, err, pyenv.ErrNoEnvironment)
+}
+
+func TestFindEnvPrecedence(t *testing.T) {
+	tmpDir := t.TempDir()
+	project := filepath.Join(tmpDir, "project")
+	writeVenv(t, project, nil)
+	other := filepath.Join(tmpDir, "other")
+	writeVenv(t, other, nil)
+	t.Setenv("VIRTUAL_ENV", filepath.Join(other, ".venv"))
+
+	env, err := pyenv.FindEnv(project, "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(project, ".venv"), env.Prefix, "The project's .venv wins over the server's VIRTUAL_ENV")
+	assert.Equal(t, project, env.Source)
+
+	env, err = pyenv.FindEnv(project, filepath.Join(other, ".venv"))
+	require.NoError(t, err)
+	assert.Equal(t, "config", env.Source, "The configured prefix wins over the project's .venv")
+
+	env, err = pyenv.FindEnv(filepath.Join(tmpDir, "empty"), "")
+	require.NoError(t, err)
+	assert.Equal(t, "VIRTUAL_ENV", env.Source, "VIRTUAL_ENV is the last fallback")
+	assert.Equal(t, filepath.Join(other, ".venv"), env.Prefix)
+}
+
+func TestDistributions(t *testing.T) {
+	t.Setenv("VIRTUAL_ENV", "")
+	tmpDir := t.TempDir()
+	writeVenv(t, tmpDir, map[string]string{
+		"PyYAML-6.0.1.dist-info/METADATA":      "Metadata-Version: 2.1\nName: PyYAML\nVersion: 6.0.1\nSummary: YAML parser and emitter for Python\n\nLong description.\n",
+		"PyYAML-6.0.1.dist-info/top_level.txt": "_yaml\nyaml\n",
+		"requests-2.32.3.dist-info/METADATA":   "Metadata-Version: 2.1\nName: requests\nVersion: 2.32.3\n",
+		"requests-2.32.3.dist-info/RECORD": "requests/__init__.py,sha256=abc,100\n" +
+			"requests/adapters.py,sha256=abc,100\n" +
+			"requests-2.32.3.dist-info/METADATA,,\n" +
+			"../../../bin/normalizer,,\n",
+		"six-1.16.0.dist-info/METADATA": "Name: six\nVersion: 1.16.0\n",
+		"six-1.16.0.dist-info/RECORD":   "six.py,,\n__pycache__/six.cpython-312.pyc,,\n",
+		"legacy-0.1.egg-info/PKG-INFO":  "Name: legacy\nVersion: 0.1\n",
+	})
+	env, err := pyenv.FindEnv(tmpDir, "")
+	require.NoError(t, err)
+
+	dists, err := env.Distributions()
+	require.NoError(t, err)
+	require.Len(t, dists, 4)
+	assert.Equal(t, "legacy", dists[0].Name)
+	assert.Equal(t, "PyYAML", dists[1].Name)
+	assert.Equal(t, "6.0.1", dists[1].Version)
+	assert.Equal(t, "YAML parser and emitter for Python", dists[1].Summary)
+	assert.Equal(t, []string{"_yaml", "yaml"}, dists[1].ImportNames)
+	assert.Equal(t, []string{"requests"}, dists[2].ImportNames, "Import names come from RECORD without top_level.txt")
+	assert.Equal(t, []string{"six"}, dists[3].ImportNames)
+
+	dist, err := env.Distribution("pyyaml")
+	require.NoError(t, err)
+	assert.Equal(t, "PyYAML", dist.Name)
+
+	_, err = env.Distribution("flask")
+	assert.ErrorContains(t, err, "not installed")
+}
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "zope-interface", pyenv.NormalizeName("Zope.Interface"))
+	assert.Equal(t, "typing-extensions", pyenv.NormalizeName("typing__extensions"))
+}
@@ -0,0 +1,244 @@
+package pyenv
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/svetlyi/mcp-local-context/internal/fsutil"
+)
+
+// Module is a module or package found in site-packages for a dotted name.
+type Module struct {
+	// Name is the module part of the requested dotted name, and Symbol the
+	// attribute path that follows it, e.g. HTTPAdapter.send.
+	Name   string `json:"name"`
+	Symbol string `json:"symbol,omitempty"`
+	// Package reports that the module is a package directory, and Dir is
+	// that directory.
+	Package bool   `json:"package,omitempty"`
+	Dir     string `json:"dir,omitempty"`
+	// Source and Stub are the .py and .pyi files of the module. A stub may
+	// come from a separate <name>-stubs package.
+	Source string `json:"source,omitempty"`
+	Stub   string `json:"stub,omitempty"`
+	// Extension is the compiled extension module, for modules without
+	// Python source.
+	Extension    string        `json:"extension,omitempty"`
+	Distribution *Distribution `json:"distribution,omitempty"`
+}
+
+// FindModule resolves a dotted name such as requests.adapters.HTTPAdapter to
+// the longest prefix naming an installed module, and the distribution
+// installing it.
+func (e *Env) FindModule(dotted string) (*Module, error) {
+	parts := strings.Split(dotted, ".")
+	for _, part := range parts {
+		if !isIdentifier(part) {
+			return nil, fmt.Errorf("invalid dotted name %q", dotted)
+		}
+	}
+
+	for i := len(parts); i >= 1; i-- {
+		mod := e.resolveModule(parts[:i])
+		if mod == nil {
+			continue
+		}
+		mod.Symbol = strings.Join(parts[i:], ".")
+		mod.Distribution = e.distributionOf(parts[0])
+		return mod, nil
+	}
+	return nil, fmt.Errorf("no module %s is installed in %s", parts[0], e.SitePackages)
+}
+
+func (e *Env) resolveModule(parts []string) *Module {
+	mod := &Module{Name: strings.Join(parts, ".")}
+	parent := filepath.Join(append([]string{e.SitePackages}, parts[:len(parts)-1]...)...)
+	last := parts[len(parts)-1]
+	stubs := e.stubCandidates(parts)
+
+	if dir := filepath.Join(parent, last); fsutil.IsDir(dir) {
+		mod.Package = true
+		mod.Dir = dir
+		mod.Source = existing(filepath.Join(dir, "__init__.py"))
+		mod.Stub = existing(append([]string{filepath.Join(dir, "__init__.pyi")}, stubs...)...)
+		return mod
+	}
+
+	mod.Source = existing(filepath.Join(parent, last+".py"))
+	mod.Stub = existing(append([]string{filepath.Join(parent, last+".pyi")}, stubs...)...)
+	if extensions, _ := filepath.Glob(filepath.Join(parent, last+".*")); len(extensions) > 0 {
+		for _, extension := range extensions {
+			if isExtension(extension) {
+				mod.Extension = extension
+				break
+			}
+		}
+	}
+	if mod.Source == "" && mod.Stub == "" && mod.Extension == "" {
+		return nil
+	}
+	return mod
+}
+
+// stubCandidates returns the files of a separate <top>-stubs package that
+// may hold the stubs of the module, as that package mirrors the layout of
+// the one it types.
+func (e *Env) stubCandidates(parts []string) []string {
+	stubDir := filepath.Join(e.SitePackages, parts[0]+"-stubs")
+	if len(parts) == 1 {
+		return []string{filepath.Join(stubDir, "__init__.pyi")}
+	}
+	path := filepath.Join(append([]string{stubDir}, parts[1:]...)...)
+	return []string{path + ".pyi", filepath.Join(path, "__init__.pyi")}
+}
+
+// distributionOf returns the distribution installing the top-level import
+// name, or its stubs.
+func (e *Env) distributionOf(top string) *Distribution {
+	dists, err := e.Distributions()
+	if err != nil {
+		return nil
+	}
+	var stubs *Distribution
+	for _, dist := range dists {
+		for _, name := range dist.ImportNames {
+			switch name {
+			case top:
+				return &dist
+			case top + "-stubs":
+				if stubs == nil {
+					stubs = &dist
+				}
+			}
+		}
+	}
+	return stubs
+}
+
+// SymbolLine returns the line defining the attribute path symbol, e.g.
+// Session.request, in a Python source or stub file, or 0 when it is not
+// found. Only class, def and top-level assignments are recognized.
+func SymbolLine(path, symbol string) (int, error) {
+	if symbol == "" {
+		return 0, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	names := strings.Split(symbol, ".")
+	depthIndent, bodyIndent := -1, -1
+	openString := ""
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		// Lines inside triple-quoted strings, e.g. docstrings, are not code
+		// whatever their indentation.
+		inString := openString != ""
+		openString = tripleQuoteState(text, openString)
+		if inString {
+			continue
+		}
+		trimmed := strings.TrimLeft(text, " \t")
+		indent := len(text) - len(trimmed)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if depthIndent >= 0 {
+			// Left the body of the enclosing class.
+			if indent <= depthIndent {
+				return 0, nil
+			}
+			// Only the class body's own statements define its attributes.
+			if bodyIndent < 0 {
+				bodyIndent = indent
+			}
+			if indent != bodyIndent {
+				continue
+			}
+		} else if indent > 0 {
+			continue
+		}
+		if !definesName(trimmed, names[0], depthIndent < 0) {
+			continue
+		}
+		if len(names) == 1 {
+			return line, nil
+		}
+		names = names[1:]
+		depthIndent, bodyIndent = indent, -1
+	}
+	return 0, scanner.Err()
+}
+
+// tripleQuoteState returns the triple quote delimiter of the string still
+// open at the end of line, or "" when none is, given the delimiter of the
+// string open at its start.
+func tripleQuoteState(line, open string) string {
+	for i := 0; i < len(line); {
+		if open != "" {
+			end := strings.Index(line[i:], open)
+			if end < 0 {
+				return open
+			}
+			i += end + len(open)
+			open = ""
+			continue
+		}
+		switch c := line[i]; c {
+		case '#':
+			return ""
+		case '"', '\'':
+			if delimiter := strings.Repeat(string(c), 3); strings.HasPrefix(line[i:], delimiter) {
+				open = delimiter
+				i += len(delimiter)
+				continue
+			}
+			// Skip a single-quoted string, which ends with the line.
+			for i++; i < len(line) && line[i] != c; i++ {
+				if line[i] == '\\' {
+					i++
+				}
+			}
+		}
+		i++
+	}
+	return open
+}
+
+func definesName(line, name string, topLevel bool) bool {
+	pattern := `^(?:async\s+def|def|class)\s+` + regexp.QuoteMeta(name) + `\b`
+	if topLevel {
+		pattern = `^(?:(?:async\s+def|def|class)\s+` + regexp.QuoteMeta(name) + `\b|` + regexp.QuoteMeta(name) + `\s*[:=])`
+	}
+	return regexp.MustCompile(pattern).MatchString(line)
+}
+
+func existing(paths ...string) string {
+	for _, path := range paths {
+		if fsutil.IsFile(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || (i > 0 && '0' <= r && r <= '9') || r > 127 {
+			continue
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package pyenv_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/pyenv"
+)
+
+func TestFindModule(t *testing.T) {
+	t.Setenv("VIRTUAL_ENV", "")
+	tmpDir := t.TempDir()
+	sitePackages := writeVenv(t, tmpDir, map[string]string{
+		"requests-2.32.3.dist-info/METADATA":      "Name: requests\nVersion: 2.32.3\n",
+		"requests-2.32.3.dist-info/top_level.txt": "requests\n",
+		"requests/__init__.py":                    "from .api import get\n",
+		"requests/adapters.py": `import socket
+
+DEFAULT_POOLSIZE = 10
+
+
+class BaseAdapter:
+    def send(self, request):
+        raise NotImplementedError
+
+
+class HTTPAdapter(BaseAdapter):
+    """The built-in HTTP Adapter for urllib3.
+
+Usage:
+    >>> s.mount('https://', HTTPAdapter())
+    """
+
+    def __init__(self, pool_connections=DEFAULT_POOLSIZE):
+        def send(): ...
+        self.pool_connections = pool_connections
+
+    async def send(self, request, stream=False):
+        return None
+`,
+		"types_requests-2.32.0.dist-info/METADATA":      "Name: types-requests\nVersion: 2.32.0\n",
+		"types_requests-2.32.0.dist-info/top_level.txt": "requests-stubs\n",
+		"requests-stubs/adapters.pyi":                   "class HTTPAdapter: ...\n",
+		"_speedups.cpython-312-x86_64-linux-gnu.so":     "",
+	})
+	env, err := pyenv.FindEnv(tmpDir, "")
+	require.NoError(t, err)
+
+	mod, err := env.FindModule("requests.adapters.HTTPAdapter.send")
+	require.NoError(t, err)
+	assert.Equal(t, "requests.adapters", mod.Name)
+	assert.Equal(t, "HTTPAdapter.send", mod.Symbol)
+	assert.Equal(t, filepath.Join(sitePackages, "requests", "adapters.py"), mod.Source)
+	assert.Equal(t, filepath.Join(sitePackages, "requests-stubs", "adapters.pyi"), mod.Stub, "Stubs come from the -stubs package")
+	require.NotNil(t, mod.Distribution)
+	assert.Equal(t, "requests", mod.Distribution.Name)
+	assert.Equal(t, "2.32.3", mod.Distribution.Version)
+
+	line, err := pyenv.SymbolLine(mod.Source, mod.Symbol)
+	require.NoError(t, err)
+	assert.Equal(t, 22, line, "The method of HTTPAdapter, not of BaseAdapter, a nested def or past the docstring")
+
+	line, err = pyenv.SymbolLine(mod.Source, "DEFAULT_POOLSIZE")
+	require.NoError(t, err)
+	assert.Equal(t, 3, line)
+
+	line, err = pyenv.SymbolLine(mod.Source, "HTTPAdapter.missing")
+	require.NoError(t, err)
+	assert.Zero(t, line)
+
+	mod, err = env.FindModule("requests")
+	require.NoError(t, err)
+	assert.True(t, mod.Package)
+	assert.Equal(t, filepath.Join(sitePackages, "requests", "__init__.py"), mod.Source)
+
+	mod, err = env.FindModule("_speedups")
+	require.NoError(t, err)
+	assert.Empty(t, mod.Source)
+	assert.Equal(t, filepath.Join(sitePackages, "_speedups.cpython-312-x86_64-linux-gnu.so"), mod.Extension)
+
+	_, err = env.FindModule("flask.Flask")
+	assert.ErrorContains(t, err, "no module flask")
+
+	_, err = env.FindModule("requests..adapters")
+	assert.ErrorContains(t, err, "invalid dotted name")
+}
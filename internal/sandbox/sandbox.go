@@ -0,0 +1,337 @@
+// Package sandbox serves files from a fixed set of source directories,
+// refusing every path that leads outside of them.
+package sandbox
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrOutsideRoots is returned for paths outside every allowed root.
+var ErrOutsideRoots = errors.New("path is outside the allowed source roots")
+
+// RootKind tells why a directory is allowed.
+type RootKind string
+
+const (
+	RootModCache RootKind = "gomodcache"
+	RootGoroot   RootKind = "goroot"
+	RootVendor   RootKind = "vendor"
+	RootReplace  RootKind = "replace"
+	RootConfig   RootKind = "config"
+	// RootPackage is the installed copy of a dependency of another
+	// ecosystem, such as a package in node_modules.
+	RootPackage RootKind = "package"
+)
+
+// Root is a directory whose files may be served.
+type Root struct {
+	Path string   `json:"path"`
+	Kind RootKind `json:"kind"`
+}
+
+// Sandbox resolves paths against a set of roots.
+type Sandbox struct {
+	roots []Root
+	// resolved holds the roots with symbolic links evaluated.
+	resolved []string
+}
+
+// New creates a sandbox for the given roots. Roots that do not exist are
+// ignored, as there is nothing to serve from them.
+func New(roots []Root) (*Sandbox, error) {
+	s := &Sandbox{}
+	for _, root := range roots {
+		if !filepath.IsAbs(root.Path) {
+			return nil, fmt.Errorf("source root %s must be an absolute path", root.Path)
+		}
+		resolved, err := filepath.EvalSymlinks(root.Path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to resolve source root %s: %w", root.Path, err)
+		}
+		if slices.Contains(s.resolved, resolved) {
+			continue
+		}
+		s.roots = append(s.roots, Root{Path: filepath.Clean(root.Path), Kind: root.Kind})
+		s.resolved = append(s.resolved, resolved)
+	}
+	return s, nil
+}
+
+// Roots returns the existing roots of the sandbox.
+func (s *Sandbox) Roots() []Root {
+	return slices.Clone(s.roots)
+}
+
+// Resolve checks that path is an absolute path below a root and returns it
+// with symbolic links evaluated. Paths with ".." elements are rejected
+// outright, and links may only point to targets below a root.
+func (s *Sandbox) Resolve(path string) (string, Root, error) {
+	if !filepath.IsAbs(path) {
+		return "", Root{}, fmt.Errorf("path %s must be absolute", path)
+	}
+	if slices.Contains(strings.Split(filepath.ToSlash(path), "/"), "..") {
+		return "", Root{}, fmt.Errorf("path %s must not contain \"..\"", path)
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Do not reveal which paths exist outside the roots.
+			if _, ok := s.rootOf(filepath.Clean(path)); !ok {
+				return "", Root{}, fmt.Errorf("%s: %w", path, ErrOutsideRoots)
+			}
+			return "", Root{}, fmt.Errorf("%s does not exist", path)
+		}
+		return "", Root{}, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	i, ok := s.rootOf(resolved)
+	if !ok {
+		return "", Root{}, fmt.Errorf("%s: %w", path, ErrOutsideRoots)
+	}
+	return resolved, s.roots[i], nil
+}
+
+// rootOf returns the index of the root containing path, checked against
+// both the configured and the resolved form of each root.
+func (s *Sandbox) rootOf(path string) (int, bool) {
+	for i := range s.roots {
+		if within(s.resolved[i], path) || within(s.roots[i].Path, path) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && filepath.IsLocal(rel)
+}
+
+// ReadOptions selects the part of a file to read. Byte and line ranges
+// cannot be combined.
+type ReadOptions struct {
+	// Offset and Length select a byte range. A zero Length reads to the end.
+	Offset int64
+	Length int64
+	// StartLine and EndLine select an inclusive, 1-based line range. A zero
+	// EndLine reads to the end.
+	StartLine int
+	EndLine   int
+	// MaxBytes caps the content returned.
+	MaxBytes int
+}
+
+// FileContent is the part of a file read.
+type FileContent struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	Content string `json:"content"`
+	// Offset is the byte offset of the content in the file.
+	Offset int64 `json:"offset"`
+	// StartLine and EndLine are the lines returned, when reading by line.
+	// A truncated content not ending in a newline holds only the beginning
+	// of EndLine.
+	StartLine int `json:"start_line,omitempty"`
+	EndLine   int `json:"end_line,omitempty"`
+	// Truncated reports that the content was cut at MaxBytes.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ReadFile reads the part of a file selected by opts.
+func (s *Sandbox) ReadFile(path string, opts ReadOptions) (*FileContent, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	resolved, _, err := s.Resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+	return readContent(path, f, info.Size(), opts)
+}
+
+// ReadContent reads the part of data selected by opts, as ReadFile does
+// for files. It serves content that is not a file of a root, such as an
+// entry of an archive; path only names the content in the result.
+func ReadContent(path string, data []byte, opts ReadOptions) (*FileContent, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	return readContent(path, bytes.NewReader(data), int64(len(data)), opts)
+}
+
+func (opts ReadOptions) validate() error {
+	if (opts.Offset != 0 || opts.Length != 0) && (opts.StartLine != 0 || opts.EndLine != 0) {
+		return fmt.Errorf("byte and line ranges cannot be combined")
+	}
+	if opts.Offset < 0 || opts.Length < 0 || opts.StartLine < 0 || opts.EndLine < 0 {
+		return fmt.Errorf("ranges must not be negative")
+	}
+	if opts.EndLine != 0 && opts.EndLine < opts.StartLine {
+		return fmt.Errorf("end line %d is before start line %d", opts.EndLine, opts.StartLine)
+	}
+	return nil
+}
+
+// contentReader is the content of a file or of an archive entry.
+type contentReader interface {
+	io.Reader
+	io.ReaderAt
+}
+
+func readContent(path string, r contentReader, size int64, opts ReadOptions) (*FileContent, error) {
+	content := &FileContent{Path: path, Size: size}
+	var err error
+	if opts.StartLine != 0 || opts.EndLine != 0 {
+		err = readLines(r, opts, content)
+	} else {
+		err = readBytes(r, opts, content)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if strings.IndexByte(content.Content, 0) >= 0 {
+		return nil, fmt.Errorf("%s is a binary file", path)
+	}
+	content.Content = strings.ToValidUTF8(content.Content, "�")
+	return content, nil
+}
+
+func readBytes(f contentReader, opts ReadOptions, content *FileContent) error {
+	length := content.Size - opts.Offset
+	if opts.Length != 0 {
+		length = min(length, opts.Length)
+	}
+	if length <= 0 {
+		content.Offset = min(opts.Offset, content.Size)
+		return nil
+	}
+	if opts.MaxBytes > 0 && length > int64(opts.MaxBytes) {
+		length = int64(opts.MaxBytes)
+		content.Truncated = true
+	}
+
+	buf := make([]byte, length)
+	n, err := f.ReadAt(buf, opts.Offset)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	content.Offset = opts.Offset
+	content.Content = string(buf[:n])
+	return nil
+}
+
+func readLines(f contentReader, opts ReadOptions, content *FileContent) error {
+	start := max(opts.StartLine, 1)
+	reader := bufio.NewReader(f)
+	var b bytes.Buffer
+	var offset int64
+	for line := 1; opts.EndLine == 0 || line <= opts.EndLine; line++ {
+		text, err := reader.ReadBytes('\n')
+		if len(text) == 0 && err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		if line < start {
+			offset += int64(len(text))
+			continue
+		}
+		if opts.MaxBytes > 0 && b.Len()+len(text) > opts.MaxBytes {
+			content.Truncated = true
+			if content.StartLine == 0 {
+				// The first line alone exceeds MaxBytes: return its
+				// beginning, cut at a character boundary, so that the rest
+				// can be read as a byte range.
+				n := opts.MaxBytes
+				for n > 0 && !utf8.RuneStart(text[n]) {
+					n--
+				}
+				content.StartLine = line
+				content.EndLine = line
+				content.Offset = offset
+				b.Write(text[:n])
+			}
+			break
+		}
+		if content.StartLine == 0 {
+			content.StartLine = line
+			content.Offset = offset
+		}
+		content.EndLine = line
+		b.Write(text)
+		if err == io.EOF {
+			break
+		}
+	}
+	content.Content = b.String()
+	return nil
+}
+
+// DirEntry is an entry of a listed directory.
+type DirEntry struct {
+	Name string `json:"name"`
+	// Type is "file", "dir" or "symlink".
+	Type string `json:"type"`
+	Size int64  `json:"size,omitempty"`
+}
+
+// ListDir returns up to maxEntries entries of a directory, and whether
+// entries were left out.
+func (s *Sandbox) ListDir(path string, maxEntries int) ([]DirEntry, bool, error) {
+	resolved, _, err := s.Resolve(path)
+	if err != nil {
+		return nil, false, err
+	}
+	entries, err := os.ReadDir(resolved)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list %s: %w", path, err)
+	}
+
+	truncated := false
+	if maxEntries > 0 && len(entries) > maxEntries {
+		entries = entries[:maxEntries]
+		truncated = true
+	}
+	result := make([]DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		dirEntry := DirEntry{Name: entry.Name(), Type: "file"}
+		switch {
+		case entry.Type()&os.ModeSymlink != 0:
+			dirEntry.Type = "symlink"
+		case entry.IsDir():
+			dirEntry.Type = "dir"
+		default:
+			if info, err := entry.Info(); err == nil {
+				dirEntry.Size = info.Size()
+			}
+		}
+		result = append(result, dirEntry)
+	}
+	return result, truncated, nil
+}
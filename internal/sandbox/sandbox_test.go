@@ -0,0 +1,170 @@
+package sandbox_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svetlyi/mcp-local-context/internal/sandbox"
+)
+
+// setupSandbox creates a module cache root with a file, a link inside the
+// root, links escaping it and a secret file outside of it.
+func setupSandbox(t *testing.T) (*sandbox.Sandbox, string, string) {
+	tmpDir := t.TempDir()
+	modCache := filepath.Join(tmpDir, "modcache")
+	modDir := filepath.Join(modCache, "example.com", "dep@v1.0.0")
+	require.NoError(t, os.MkdirAll(filepath.Join(modDir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "dep.go"), []byte("line 1\nline 2\nline 3\nline 4\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "blob.bin"), []byte("a\x00b"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "secret.txt"), []byte("secret"), 0644))
+	require.NoError(t, os.Symlink(filepath.Join(modDir, "dep.go"), filepath.Join(modDir, "inside.go")))
+	require.NoError(t, os.Symlink(filepath.Join(tmpDir, "secret.txt"), filepath.Join(modDir, "escape.txt")))
+	require.NoError(t, os.Symlink(tmpDir, filepath.Join(modDir, "escape-dir")))
+
+	sb, err := sandbox.New([]sandbox.Root{
+		{Path: modCache, Kind: sandbox.RootModCache},
+		{Path: filepath.Join(tmpDir, "missing"), Kind: sandbox.RootConfig},
+	})
+	require.NoError(t, err)
+	return sb, tmpDir, modDir
+}
+
+func TestResolve(t *testing.T) {
+	sb, tmpDir, modDir := setupSandbox(t)
+	assert.Equal(t, []sandbox.Root{{Path: filepath.Join(tmpDir, "modcache"), Kind: sandbox.RootModCache}}, sb.Roots(), "Missing roots are ignored")
+
+	_, root, err := sb.Resolve(filepath.Join(modDir, "dep.go"))
+	require.NoError(t, err)
+	assert.Equal(t, sandbox.RootModCache, root.Kind)
+
+	resolved, _, err := sb.Resolve(filepath.Join(modDir, "inside.go"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(modDir, "dep.go"), resolved)
+
+	_, _, err = sb.Resolve(filepath.Join(tmpDir, "secret.txt"))
+	assert.ErrorIs(t, err, sandbox.ErrOutsideRoots)
+
+	_, _, err = sb.Resolve(filepath.Join(modDir, "escape.txt"))
+	assert.ErrorIs(t, err, sandbox.ErrOutsideRoots, "Links may not point outside the roots")
+
+	_, _, err = sb.Resolve(filepath.Join(modDir, "escape-dir", "secret.txt"))
+	assert.ErrorIs(t, err, sandbox.ErrOutsideRoots)
+
+	_, _, err = sb.Resolve(modDir + "/../../../secret.txt")
+	assert.ErrorContains(t, err, `must not contain ".."`)
+
+	_, _, err = sb.Resolve("modcache/example.com")
+	assert.ErrorContains(t, err, "must be absolute")
+
+	_, _, err = sb.Resolve(filepath.Join(tmpDir, "nothing"))
+	assert.ErrorIs(t, err, sandbox.ErrOutsideRoots, "Missing paths outside the roots are not told apart")
+
+	_, _, err = sb.Resolve(filepath.Join(modDir, "nothing"))
+	assert.ErrorContains(t, err, "does not exist")
+}
+
+func TestReadFile(t *testing.T) {
+	sb, _, modDir := setupSandbox(t)
+	file := filepath.Join(modDir, "dep.go")
+
+	content, err := sb.ReadFile(file, sandbox.ReadOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "line 1\nline 2\nline 3\nline 4\n", content.Content)
+	assert.Equal(t, int64(28), content.Size)
+
+	content, err = sb.ReadFile(file, sandbox.ReadOptions{Offset: 7, Length: 6})
+	require.NoError(t, err)
+	assert.Equal(t, "line 2", content.Content)
+	assert.Equal(t, int64(7), content.Offset)
+
+	content, err = sb.ReadFile(file, sandbox.ReadOptions{StartLine: 2, EndLine: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "line 2\nline 3\n", content.Content)
+	assert.Equal(t, 2, content.StartLine)
+	assert.Equal(t, 3, content.EndLine)
+	assert.Equal(t, int64(7), content.Offset)
+
+	content, err = sb.ReadFile(file, sandbox.ReadOptions{StartLine: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "line 3\nline 4\n", content.Content)
+	assert.Equal(t, 4, content.EndLine)
+
+	content, err = sb.ReadFile(file, sandbox.ReadOptions{MaxBytes: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "line 1\nlin", content.Content)
+	assert.True(t, content.Truncated)
+
+	content, err = sb.ReadFile(file, sandbox.ReadOptions{StartLine: 1, MaxBytes: 16})
+	require.NoError(t, err)
+	assert.Equal(t, "line 1\nline 2\n", content.Content, "Line ranges are cut at whole lines")
+	assert.Equal(t, 2, content.EndLine)
+	assert.True(t, content.Truncated)
+
+	content, err = sb.ReadFile(file, sandbox.ReadOptions{StartLine: 2, MaxBytes: 4})
+	require.NoError(t, err)
+	assert.Equal(t, "line", content.Content, "A line longer than MaxBytes is cut within the line")
+	assert.Equal(t, 2, content.StartLine)
+	assert.Equal(t, 2, content.EndLine)
+	assert.Equal(t, int64(7), content.Offset)
+	assert.True(t, content.Truncated)
+
+	content, err = sandbox.ReadContent("min.js", []byte("é€x\n"), sandbox.ReadOptions{StartLine: 1, MaxBytes: 4})
+	require.NoError(t, err)
+	assert.Equal(t, "é", content.Content, "The line is cut at a character boundary")
+
+	_, err = sb.ReadFile(file, sandbox.ReadOptions{Offset: 1, StartLine: 1})
+	assert.ErrorContains(t, err, "cannot be combined")
+
+	_, err = sb.ReadFile(file, sandbox.ReadOptions{StartLine: 3, EndLine: 2})
+	assert.Error(t, err)
+
+	_, err = sb.ReadFile(filepath.Join(modDir, "blob.bin"), sandbox.ReadOptions{})
+	assert.ErrorContains(t, err, "binary file")
+
+	_, err = sb.ReadFile(modDir, sandbox.ReadOptions{})
+	assert.ErrorContains(t, err, "is a directory")
+
+	_, err = sb.ReadFile(filepath.Join(modDir, "escape.txt"), sandbox.ReadOptions{})
+	assert.ErrorIs(t, err, sandbox.ErrOutsideRoots)
+}
+
+func TestReadContent(t *testing.T) {
+	data := []byte("line 1\nline 2\nline 3\n")
+
+	content, err := sandbox.ReadContent("lib.jar!/Lib.java", data, sandbox.ReadOptions{StartLine: 2, EndLine: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "line 2\n", content.Content)
+	assert.Equal(t, "lib.jar!/Lib.java", content.Path)
+	assert.Equal(t, int64(21), content.Size)
+
+	_, err = sandbox.ReadContent("Lib.class", []byte{0xca, 0xfe, 0, 0}, sandbox.ReadOptions{})
+	assert.ErrorContains(t, err, "binary file")
+}
+
+func TestListDir(t *testing.T) {
+	sb, tmpDir, modDir := setupSandbox(t)
+
+	entries, truncated, err := sb.ListDir(modDir, 0)
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name+":"+entry.Type)
+	}
+	assert.Equal(t, "blob.bin:file dep.go:file escape-dir:symlink escape.txt:symlink inside.go:symlink sub:dir", strings.Join(names, " "))
+
+	entries, truncated, err = sb.ListDir(modDir, 2)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.True(t, truncated)
+
+	_, _, err = sb.ListDir(tmpDir, 0)
+	assert.ErrorIs(t, err, sandbox.ErrOutsideRoots)
+
+	_, _, err = sb.ListDir(filepath.Join(modDir, "escape-dir"), 0)
+	assert.ErrorIs(t, err, sandbox.ErrOutsideRoots)
+}
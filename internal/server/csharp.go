@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/nuget"
+)
+
+const (
+	defaultDocMembers = 50
+	maxDocMembers     = 500
+)
+
+type readNuGetDocsArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the .NET project (or any directory below its .csproj)"`
+	Package    string `json:"package" jsonschema:"Package ID, e.g. Newtonsoft.Json"`
+	Member     string `json:"member,omitempty" jsonschema:"Type, member or namespace to document, qualified or by its trailing segments, e.g. Newtonsoft.Json.JsonConvert or JsonConvert.SerializeObject (default: list the documented types)"`
+	Framework  string `json:"framework,omitempty" jsonschema:"Target framework folder of the package to read, e.g. net8.0 (default: one the project targets, or the newest)"`
+	MaxMembers int    `json:"max_members,omitempty" jsonschema:"Maximum members to return (default 50, at most 500)"`
+}
+
+type readNuGetDocsOutput struct {
+	Dependency *nuget.Dependency `json:"dependency"`
+	Framework  string            `json:"framework"`
+	// Frameworks are all the frameworks the package has documentation for.
+	Frameworks []string       `json:"frameworks"`
+	Files      []string       `json:"files"`
+	Members    []nuget.Member `json:"members"`
+	Truncated  bool           `json:"truncated,omitempty"`
+}
+
+func (s *Server) registerCSharpTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "read_nuget_docs",
+		Description: "Return the XML documentation of a NuGet package of a .NET project as structured member docs (summary, parameters, return value, exceptions, remarks, examples), read from lib/<framework>/*.xml in the global packages folder at the version the project restores. NuGet packages usually ship no source, so use this instead of memory of the API. Without member, lists the documented types.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args readNuGetDocsArgs) (*mcp.CallToolResult, *readNuGetDocsOutput, error) {
+		if args.Package == "" {
+			return nil, nil, fmt.Errorf("package argument is required")
+		}
+		if args.ProjectDir == "" {
+			return nil, nil, fmt.Errorf("project_dir argument is required")
+		}
+		project, err := nuget.LoadProject(args.ProjectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		dep, err := project.Dependency(args.Package)
+		if err != nil {
+			return nil, nil, err
+		}
+		if dep.Dir == "" {
+			if dep.Resolved == "" {
+				return nil, nil, fmt.Errorf("the version of %s could not be resolved without packages.lock.json; run dotnet restore", dep.ID)
+			}
+			return nil, nil, fmt.Errorf("%s %s is not in the global packages folder %s; run dotnet restore", dep.ID, dep.Resolved, project.PackagesDir)
+		}
+
+		output := &readNuGetDocsOutput{Dependency: dep, Frameworks: nuget.DocFrameworks(dep.Dir), Members: make([]nuget.Member, 0)}
+		output.Framework, output.Files = nuget.DocFiles(dep.Dir, args.Framework, project.TargetFrameworks)
+		if len(output.Files) == 0 {
+			if len(output.Frameworks) == 0 {
+				return nil, nil, fmt.Errorf("%s %s ships no XML documentation; read its files with locate_dependency", dep.ID, dep.Resolved)
+			}
+			return nil, nil, fmt.Errorf("%s %s has no XML documentation for %s (available: %s)", dep.ID, dep.Resolved, args.Framework, strings.Join(output.Frameworks, ", "))
+		}
+
+		maxMembers := defaultDocMembers
+		if args.MaxMembers > 0 {
+			maxMembers = min(args.MaxMembers, maxDocMembers)
+		}
+		for _, file := range output.Files {
+			docs, err := nuget.ReadDocs(file)
+			if err != nil {
+				return nil, nil, err
+			}
+			members := docs.Types()
+			if args.Member != "" {
+				members = docs.Find(args.Member)
+			}
+			for _, member := range members {
+				if len(output.Members) == maxMembers {
+					output.Truncated = true
+					break
+				}
+				output.Members = append(output.Members, member)
+			}
+		}
+		if len(output.Members) == 0 && args.Member != "" {
+			return nil, nil, fmt.Errorf("%s is not documented in %s %s", args.Member, dep.ID, dep.Resolved)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatNuGetDocs(output, args.Member == "")},
+			},
+		}, output, nil
+	})
+}
+
+func formatNuGetDocs(output *readNuGetDocsOutput, typesOnly bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s (%s): %s\n", output.Dependency.ID, output.Dependency.Resolved, output.Framework, strings.Join(output.Files, ", "))
+	if len(output.Frameworks) > 1 {
+		fmt.Fprintf(&b, "Documented frameworks: %s\n", strings.Join(output.Frameworks, ", "))
+	}
+
+	if typesOnly {
+		fmt.Fprintf(&b, "\n%d documented types:\n", len(output.Members))
+		for _, member := range output.Members {
+			fmt.Fprintf(&b, "- %s", member.Name)
+			if summary, _, _ := strings.Cut(member.Summary, "\n"); summary != "" {
+				fmt.Fprintf(&b, ": %s", summary)
+			}
+			b.WriteString("\n")
+		}
+	} else {
+		for _, member := range output.Members {
+			formatNuGetMember(&b, &member)
+		}
+	}
+	if output.Truncated {
+		b.WriteString("\n[more members not shown]\n")
+	}
+	return b.String()
+}
+
+func formatNuGetMember(b *strings.Builder, member *nuget.Member) {
+	fmt.Fprintf(b, "\n## %s %s\n", member.Kind, member.Name)
+	if member.InheritDoc {
+		b.WriteString("\nInherits the documentation of the member it overrides or implements.\n")
+	}
+	if member.Summary != "" {
+		fmt.Fprintf(b, "\n%s\n", member.Summary)
+	}
+	formatParams := func(title string, params []nuget.Param) {
+		if len(params) == 0 {
+			return
+		}
+		fmt.Fprintf(b, "\n%s:\n", title)
+		for _, param := range params {
+			fmt.Fprintf(b, "- %s: %s\n", param.Name, param.Text)
+		}
+	}
+	formatParams("Type parameters", member.TypeParams)
+	formatParams("Parameters", member.Params)
+	if member.Returns != "" {
+		fmt.Fprintf(b, "\nReturns: %s\n", member.Returns)
+	}
+	if member.Value != "" {
+		fmt.Fprintf(b, "\nValue: %s\n", member.Value)
+	}
+	formatParams("Exceptions", member.Exceptions)
+	if member.Remarks != "" {
+		fmt.Fprintf(b, "\nRemarks:\n%s\n", member.Remarks)
+	}
+	if member.Example != "" {
+		fmt.Fprintf(b, "\nExample:\n%s\n", member.Example)
+	}
+}
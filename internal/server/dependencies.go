@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/ecosystem"
+	"github.com/svetlyi/mcp-local-context/internal/sandbox"
+)
+
+type listDependenciesArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the project (or any directory below its manifest)"`
+	Ecosystem  string `json:"ecosystem,omitempty" jsonschema:"Ecosystem to use instead of the detected one: go, javascript, python, rust, java, ruby, php or csharp"`
+}
+
+type locateDependencyArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the project (or any directory below its manifest)"`
+	Ecosystem  string `json:"ecosystem,omitempty" jsonschema:"Ecosystem to use instead of the detected one: go, javascript, python, rust, java, ruby, php or csharp"`
+	Name       string `json:"name" jsonschema:"Name of the dependency as its ecosystem spells it, e.g. github.com/nats-io/nats.go, react, requests, tokio or com.google.guava:guava"`
+	Path       string `json:"path,omitempty" jsonschema:"Directory to list, relative to the source root of the dependency (default: the root)"`
+	MaxEntries int    `json:"max_entries,omitempty" jsonschema:"Maximum entries to return (default 500, at most 5000)"`
+}
+
+type locateDependencyOutput struct {
+	Ecosystem  string                `json:"ecosystem"`
+	Dependency *ecosystem.Dependency `json:"dependency"`
+	Path       string                `json:"path,omitempty"`
+	Entries    []sandbox.DirEntry    `json:"entries,omitempty"`
+	Truncated  bool                  `json:"truncated,omitempty"`
+}
+
+type readDependencyFileArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the project (or any directory below its manifest)"`
+	Ecosystem  string `json:"ecosystem,omitempty" jsonschema:"Ecosystem to use instead of the detected one: go, javascript, python, rust, java, ruby, php or csharp"`
+	Name       string `json:"name" jsonschema:"Name of the dependency as its ecosystem spells it, e.g. github.com/nats-io/nats.go, react, requests, tokio or com.google.guava:guava"`
+	Path       string `json:"path" jsonschema:"Path of the file, relative to the source root of the dependency"`
+	Offset     int64  `json:"offset,omitempty" jsonschema:"Byte offset to start reading at"`
+	Length     int64  `json:"length,omitempty" jsonschema:"Number of bytes to read from offset"`
+	StartLine  int    `json:"start_line,omitempty" jsonschema:"First line to read, starting at 1. Cannot be combined with offset and length"`
+	EndLine    int    `json:"end_line,omitempty" jsonschema:"Last line to read, inclusive"`
+	MaxBytes   int    `json:"max_bytes,omitempty" jsonschema:"Maximum bytes of content to return (default 65536, at most 262144)"`
+}
+
+type readDependencyFileOutput struct {
+	Ecosystem  string                `json:"ecosystem"`
+	Dependency *ecosystem.Dependency `json:"dependency"`
+	Content    *sandbox.FileContent  `json:"content"`
+}
+
+func (s *Server) registerDependencyTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_dependencies",
+		Description: "List the dependencies of a project in any supported ecosystem (Go, JavaScript/TypeScript, Python, Rust, Java/Kotlin, Ruby, PHP, C#), detected from the manifest nearest to project_dir, with the exact version the project builds against and the source root of each: a directory, or a -sources.jar for Java. Use locate_dependency and read_dependency_file to read the sources.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listDependenciesArgs) (*mcp.CallToolResult, *ecosystem.Project, error) {
+		eco, err := s.resolveEcosystem(args.ProjectDir, args.Ecosystem)
+		if err != nil {
+			return nil, nil, err
+		}
+		project, err := eco.Load(args.ProjectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatDependencies(project)},
+			},
+		}, project, nil
+	})
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "locate_dependency",
+		Description: "Return the version and source root of one dependency of a project in any supported ecosystem, and list a directory of its sources (the root by default). Paths are relative to the source root and can be passed to read_dependency_file.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args locateDependencyArgs) (*mcp.CallToolResult, *locateDependencyOutput, error) {
+		eco, dep, err := s.locateDependency(args.ProjectDir, args.Ecosystem, args.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &locateDependencyOutput{Ecosystem: eco.Name(), Dependency: dep, Path: args.Path}
+		if dep.Root != "" {
+			maxEntries := defaultDirEntries
+			if args.MaxEntries > 0 {
+				maxEntries = min(args.MaxEntries, maxDirEntries)
+			}
+			output.Entries, output.Truncated, err = eco.ListFiles(dep, args.Path, maxEntries)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatLocatedDependency(output)},
+			},
+		}, output, nil
+	})
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "read_dependency_file",
+		Description: "Read a file of a dependency's sources in any supported ecosystem, given its path relative to the source root locate_dependency returns, at the version the project builds against. Read a byte range with offset/length or a line range with start_line/end_line; content is capped at max_bytes and marked truncated when cut.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args readDependencyFileArgs) (*mcp.CallToolResult, *readDependencyFileOutput, error) {
+		if args.Path == "" {
+			return nil, nil, fmt.Errorf("path argument is required")
+		}
+		eco, dep, err := s.locateDependency(args.ProjectDir, args.Ecosystem, args.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		opts := sandbox.ReadOptions{
+			Offset:    args.Offset,
+			Length:    args.Length,
+			StartLine: args.StartLine,
+			EndLine:   args.EndLine,
+			MaxBytes:  defaultReadBytes,
+		}
+		if args.MaxBytes > 0 {
+			opts.MaxBytes = min(args.MaxBytes, maxReadBytes)
+		}
+		content, err := eco.ReadFile(dep, args.Path, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &readDependencyFileOutput{Ecosystem: eco.Name(), Dependency: dep, Content: content}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatFileContent(content)},
+			},
+		}, output, nil
+	})
+}
+
+// resolveEcosystem returns the named ecosystem, or the one detected for the
+// project in projectDir.
+func (s *Server) resolveEcosystem(projectDir, name string) (ecosystem.Ecosystem, error) {
+	if projectDir == "" {
+		return nil, fmt.Errorf("project_dir argument is required")
+	}
+	return s.ecosystems.Resolve(projectDir, name)
+}
+
+func (s *Server) locateDependency(projectDir, ecosystemName, name string) (ecosystem.Ecosystem, *ecosystem.Dependency, error) {
+	if name == "" {
+		return nil, nil, fmt.Errorf("name argument is required")
+	}
+	eco, err := s.resolveEcosystem(projectDir, ecosystemName)
+	if err != nil {
+		return nil, nil, err
+	}
+	dep, err := eco.Locate(projectDir, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return eco, dep, nil
+}
+
+func formatDependency(b *strings.Builder, dep *ecosystem.Dependency) {
+	fmt.Fprintf(b, "- %s", dep.Name)
+	if dep.Version != "" {
+		fmt.Fprintf(b, " %s", dep.Version)
+	}
+	if dep.Requested != "" && dep.Requested != dep.Version {
+		fmt.Fprintf(b, " (requested %s)", dep.Requested)
+	}
+	if dep.Kind != "" {
+		fmt.Fprintf(b, " [%s]", dep.Kind)
+	}
+	if dep.Note != "" {
+		fmt.Fprintf(b, ", %s", dep.Note)
+	}
+	b.WriteString("\n")
+	if dep.Root != "" {
+		fmt.Fprintf(b, "    %s\n", dep.Root)
+	}
+}
+
+func formatDependencies(project *ecosystem.Project) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d dependencies of %s (%s)\n\n", len(project.Dependencies), project.Dir, project.Ecosystem)
+	for _, dep := range project.Dependencies {
+		formatDependency(&b, &dep)
+	}
+
+	if len(project.Warnings) > 0 {
+		b.WriteString("\nWarnings:")
+		for _, warning := range project.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
+
+func formatLocatedDependency(output *locateDependencyOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s dependency\n", output.Ecosystem)
+	formatDependency(&b, output.Dependency)
+	if output.Dependency.Root == "" {
+		return b.String()
+	}
+
+	path := output.Path
+	if path == "" {
+		path = "."
+	}
+	fmt.Fprintf(&b, "\n%s:\n", path)
+	for _, entry := range output.Entries {
+		switch entry.Type {
+		case "dir":
+			fmt.Fprintf(&b, "%s/\n", entry.Name)
+		case "symlink":
+			fmt.Fprintf(&b, "%s@\n", entry.Name)
+		default:
+			fmt.Fprintf(&b, "%s\t%d\n", entry.Name, entry.Size)
+		}
+	}
+	if output.Truncated {
+		b.WriteString("[more entries not shown]\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/goanalysis"
+	"github.com/svetlyi/mcp-local-context/internal/goload"
+)
+
+type listDeprecatedArgs struct {
+	buildContextArgs
+	ProjectDir   string `json:"project_dir" jsonschema:"Directory of the Go project (or any directory below its go.mod)"`
+	Package      string `json:"package,omitempty" jsonschema:"Import path of a package to list all deprecated symbols of"`
+	Module       string `json:"module,omitempty" jsonschema:"Path of a required module to list all deprecated symbols of"`
+	IncludeTests bool   `json:"include_tests,omitempty" jsonschema:"Also look for uses in _test.go files"`
+}
+
+func (s *Server) registerDeprecationTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_deprecated_apis",
+		Description: "Report deprecated dependency APIs (symbols documented with a \"Deprecated:\" paragraph) together with their suggested replacement text, and modules whose newest go.mod in the module cache is marked deprecated. Without package or module, it reports every deprecated symbol the project actually uses, with the file:line of each use. With package or module, it lists all deprecated symbols there and marks which ones the project uses. Call this before copying API usage from a dependency so you do not spread deprecated calls.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listDeprecatedArgs) (*mcp.CallToolResult, *goanalysis.DeprecationReport, error) {
+		loader, err := s.newGoLoader(args.ProjectDir, args.buildContextArgs)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var pkgs []*goload.Package
+		warnings := loader.Project().Warnings()
+		if args.Package != "" {
+			pkg, err := loader.Load(args.Package)
+			if err != nil {
+				return nil, nil, err
+			}
+			pkgs = append(pkgs, pkg)
+		}
+		if args.Module != "" {
+			modulePkgs, loadErrs, err := loader.LoadModule(args.Module)
+			if err != nil {
+				return nil, nil, err
+			}
+			pkgs = append(pkgs, modulePkgs...)
+			for _, loadErr := range loadErrs {
+				warnings = append(warnings, loadErr.Error())
+			}
+		}
+
+		report, err := goanalysis.ReportDeprecations(loader, pkgs, args.IncludeTests)
+		if err != nil {
+			return nil, nil, err
+		}
+		report.Warnings = append(warnings, report.Warnings...)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatDeprecations(report)},
+			},
+		}, report, nil
+	})
+}
+
+func formatDeprecations(report *goanalysis.DeprecationReport) string {
+	var b strings.Builder
+	if len(report.Modules) > 0 {
+		b.WriteString("Deprecated modules:\n")
+		for _, mod := range report.Modules {
+			fmt.Fprintf(&b, "- %s@%s: %s\n", mod.Path, mod.Version, mod.Message)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "%d deprecated symbols\n", len(report.Symbols))
+	for _, d := range report.Symbols {
+		fmt.Fprintf(&b, "\n%s %s (%s)\n\tDeprecated: %s\n", d.Kind, d.Symbol, d.Position, d.Message)
+		if len(d.UsedAt) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\tUsed %d times in the project:\n", len(d.UsedAt))
+		for _, ref := range d.UsedAt {
+			fmt.Fprintf(&b, "\t- %s:%d", ref.File, ref.Line)
+			if ref.Function != "" {
+				fmt.Fprintf(&b, " in %s", ref.Function)
+			}
+			b.WriteString(generatedNote(ref))
+			b.WriteString("\n")
+		}
+	}
+
+	if len(report.Warnings) > 0 {
+		b.WriteString("\nWarnings (results may be incomplete):")
+		for _, warning := range report.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/detect"
+)
+
+type detectProjectLanguagesArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the project to scan"`
+	MaxDepth   int    `json:"max_depth,omitempty" jsonschema:"Directory levels below project_dir to scan (default 3, at most 8)"`
+}
+
+type detectedLanguage struct {
+	Name     string   `json:"name"`
+	Evidence []string `json:"evidence"`
+	// Prompts are the context instructions returned for the language,
+	// empty when the server has none.
+	Prompts []string `json:"prompts"`
+}
+
+type detectProjectLanguagesOutput struct {
+	Dir       string             `json:"dir"`
+	Languages []detectedLanguage `json:"languages"`
+	Truncated bool               `json:"truncated,omitempty"`
+	Warnings  []string           `json:"warnings,omitempty"`
+}
+
+func (s *Server) registerDetectTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "detect_project_languages",
+		Description: "Call this FIRST instead of guessing the language: scans a project directory (a few levels deep, honoring .gitignore and skipping node_modules, vendor, build output and virtual environments) for manifests such as go.mod, package.json, tsconfig.json, pyproject.toml, Cargo.toml, pom.xml and build.gradle, and returns the detected languages with the files that show them, main language first, together with the context instructions for each supported language in the same call.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args detectProjectLanguagesArgs) (*mcp.CallToolResult, *detectProjectLanguagesOutput, error) {
+		if args.ProjectDir == "" {
+			return nil, nil, fmt.Errorf("project_dir argument is required")
+		}
+		result, err := detect.Scan(args.ProjectDir, args.MaxDepth)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &detectProjectLanguagesOutput{Dir: result.Dir, Languages: make([]detectedLanguage, 0, len(result.Languages)), Truncated: result.Truncated}
+		var instructions []mcp.Content
+		// Each prompt is returned once, and instructions shared by several
+		// prompts, such as those of JavaScript and TypeScript, are only
+		// repeated by reference.
+		seen := make(map[string]bool)
+		sentContent := make(map[string]string)
+		for _, lang := range result.Languages {
+			detected := detectedLanguage{Name: lang.Name, Evidence: lang.Evidence, Prompts: make([]string, 0)}
+			// Prompts that can refer to the project are rendered for the
+			// directory of the shallowest manifest.
+			projectDir := filepath.Join(result.Dir, filepath.Dir(filepath.FromSlash(lang.Evidence[0])))
+			for _, prompt := range s.registry.GetPromptsByLanguage(lang.Name) {
+				detected.Prompts = append(detected.Prompts, prompt.Name)
+				if seen[prompt.Name] {
+					continue
+				}
+				seen[prompt.Name] = true
+				content := prompt.Content
+				if prompt.Render != nil {
+					rendered, err := prompt.Render(map[string]string{"project_dir": projectDir})
+					if err != nil {
+						output.Warnings = append(output.Warnings, fmt.Sprintf("failed to render %s for %s, returning the generic instructions: %v", prompt.Name, projectDir, err))
+					} else {
+						content = rendered
+					}
+				}
+				text := content
+				if first, ok := sentContent[content]; ok {
+					text = fmt.Sprintf("Same instructions as %s above.", first)
+				} else {
+					sentContent[content] = prompt.Name
+				}
+				instructions = append(instructions, &mcp.TextContent{
+					Text: fmt.Sprintf("# %s\n\n%s", prompt.Name, text),
+				})
+			}
+			output.Languages = append(output.Languages, detected)
+		}
+
+		contents := append([]mcp.Content{&mcp.TextContent{Text: formatDetectedLanguages(output)}}, instructions...)
+		return &mcp.CallToolResult{
+			Content: contents,
+		}, output, nil
+	})
+}
+
+func formatDetectedLanguages(output *detectProjectLanguagesOutput) string {
+	var b strings.Builder
+	if len(output.Languages) == 0 {
+		fmt.Fprintf(&b, "No project manifests found in %s. Pass a larger max_depth, or call list_supported_languages.\n", output.Dir)
+	} else {
+		fmt.Fprintf(&b, "%d languages detected in %s\n\n", len(output.Languages), output.Dir)
+	}
+	for _, lang := range output.Languages {
+		fmt.Fprintf(&b, "- %s: %s", lang.Name, strings.Join(lang.Evidence, ", "))
+		if len(lang.Prompts) > 0 {
+			fmt.Fprintf(&b, "\n    instructions: %s\n", strings.Join(lang.Prompts, ", "))
+		} else {
+			b.WriteString("\n    no context instructions available\n")
+		}
+	}
+	if output.Truncated {
+		b.WriteString("\nSome evidence files are not listed.\n")
+	}
+
+	if len(output.Warnings) > 0 {
+		b.WriteString("\nWarnings:")
+		for _, warning := range output.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/goanalysis"
+)
+
+type listPackageErrorsArgs struct {
+	buildContextArgs
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the Go project (or any directory below its go.mod)"`
+	Package    string `json:"package" jsonschema:"Import path of the package, e.g. database/sql or github.com/nats-io/nats.go"`
+}
+
+type listPackageErrorsOutput struct {
+	*goanalysis.ErrorCatalog
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func (s *Server) registerErrorTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_package_errors",
+		Description: "List the errors a Go package exposes at the dependency version in go.mod: exported sentinel error variables (ErrX, io.EOF) to match with errors.Is, exported types implementing error to match with errors.As, and exported functions whose documentation names the errors they return. Reports doc comments, the messages and %w-wrapped errors of sentinels, whether errors.As needs a pointer target, and Unwrap, Is and As methods that affect matching.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listPackageErrorsArgs) (*mcp.CallToolResult, *listPackageErrorsOutput, error) {
+		if args.Package == "" {
+			return nil, nil, fmt.Errorf("package argument is required")
+		}
+
+		loader, err := s.newGoLoader(args.ProjectDir, args.buildContextArgs)
+		if err != nil {
+			return nil, nil, err
+		}
+		pkg, err := loader.Load(args.Package)
+		if err != nil {
+			return nil, nil, err
+		}
+		catalog, err := goanalysis.ListErrors(loader, pkg)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &listPackageErrorsOutput{ErrorCatalog: catalog, Warnings: loader.Project().Warnings()}
+		if len(pkg.Errors) > 0 {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("%s: %v", pkg.Path, pkg.Errors[0]))
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatPackageErrors(output)},
+			},
+		}, output, nil
+	})
+}
+
+func formatPackageErrors(output *listPackageErrorsOutput) string {
+	var b strings.Builder
+	name := path.Base(output.Package)
+	fmt.Fprintf(&b, "Errors of %s\n", output.Package)
+
+	fmt.Fprintf(&b, "\nSentinel errors (%d), match with errors.Is(err, %s.ErrX):\n", len(output.Values), name)
+	for _, value := range output.Values {
+		fmt.Fprintf(&b, "- %s %s", value.Name, value.Type)
+		if value.Message != "" {
+			fmt.Fprintf(&b, " %q", value.Message)
+		}
+		if len(value.Wraps) > 0 {
+			fmt.Fprintf(&b, ", wraps %s", strings.Join(value.Wraps, ", "))
+		}
+		fmt.Fprintf(&b, " (%s)\n", value.Position)
+		writeIndentedDoc(&b, value.Doc)
+	}
+
+	fmt.Fprintf(&b, "\nError types (%d), match with errors.As:\n", len(output.Types))
+	for _, errType := range output.Types {
+		target := name + "." + errType.Name
+		if errType.Pointer {
+			target = "*" + target
+		}
+		fmt.Fprintf(&b, "- %s: var target %s; errors.As(err, &target)", errType.Name, target)
+		switch errType.Unwrap {
+		case "":
+			b.WriteString(", no Unwrap")
+		default:
+			fmt.Fprintf(&b, ", Unwrap() %s", errType.Unwrap)
+		}
+		if errType.HasIs {
+			b.WriteString(", custom Is")
+		}
+		if errType.HasAs {
+			b.WriteString(", custom As")
+		}
+		fmt.Fprintf(&b, " (%s)\n", errType.Position)
+		writeIndentedDoc(&b, errType.Doc)
+	}
+
+	if len(output.Functions) > 0 {
+		fmt.Fprintf(&b, "\nFunctions documenting their errors (%d):\n", len(output.Functions))
+		for _, fn := range output.Functions {
+			fmt.Fprintf(&b, "- %s%s returns %s (%s)\n", fn.Name, fn.Signature, strings.Join(fn.Errors, ", "), fn.Position)
+			writeIndentedDoc(&b, fn.Doc)
+		}
+	}
+
+	if len(output.Warnings) > 0 {
+		b.WriteString("\nWarnings:")
+		for _, warning := range output.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
+
+func writeIndentedDoc(b *strings.Builder, doc string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		if line == "" {
+			b.WriteString("\n")
+			continue
+		}
+		fmt.Fprintf(b, "    %s\n", line)
+	}
+}
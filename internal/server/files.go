@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/goenv"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+	"github.com/svetlyi/mcp-local-context/internal/sandbox"
+)
+
+const (
+	defaultReadBytes  = 64 * 1024
+	maxReadBytes      = 256 * 1024
+	defaultDirEntries = 500
+	maxDirEntries     = 5000
+)
+
+type readFileArgs struct {
+	Path       string `json:"path" jsonschema:"Absolute path of a file under the module cache, GOROOT, a vendor directory, a local replace target or a configured source root"`
+	ProjectDir string `json:"project_dir,omitempty" jsonschema:"Directory of the Go project, which allows its vendor directory and local replace targets"`
+	Offset     int64  `json:"offset,omitempty" jsonschema:"Byte offset to start reading at"`
+	Length     int64  `json:"length,omitempty" jsonschema:"Number of bytes to read from offset"`
+	StartLine  int    `json:"start_line,omitempty" jsonschema:"First line to read, starting at 1. Cannot be combined with offset and length"`
+	EndLine    int    `json:"end_line,omitempty" jsonschema:"Last line to read, inclusive"`
+	MaxBytes   int    `json:"max_bytes,omitempty" jsonschema:"Maximum bytes of content to return (default 65536, at most 262144)"`
+}
+
+type listDirArgs struct {
+	Path       string `json:"path,omitempty" jsonschema:"Absolute path of a directory under the allowed source roots. Leave empty to list the roots themselves"`
+	ProjectDir string `json:"project_dir,omitempty" jsonschema:"Directory of the Go project, which allows its vendor directory and local replace targets"`
+	MaxEntries int    `json:"max_entries,omitempty" jsonschema:"Maximum entries to return (default 500, at most 5000)"`
+}
+
+type listDirOutput struct {
+	Path      string             `json:"path,omitempty"`
+	Entries   []sandbox.DirEntry `json:"entries,omitempty"`
+	Roots     []sandbox.Root     `json:"roots,omitempty"`
+	Truncated bool               `json:"truncated,omitempty"`
+	Warnings  []string           `json:"warnings,omitempty"`
+}
+
+// newSandbox allows the module cache, GOROOT and the configured source
+// roots, plus the vendor directory and local replace targets of the
+// project in projectDir, if given.
+func (s *Server) newSandbox(projectDir string) (*sandbox.Sandbox, []string, error) {
+	var env *goenv.Env
+	var project *gomod.Project
+	var err error
+	if projectDir != "" {
+		env, project, err = s.loadGoProject(projectDir)
+	} else {
+		env, err = s.goEnv()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roots := []sandbox.Root{
+		{Path: env.GOMODCACHE, Kind: sandbox.RootModCache},
+		{Path: env.GOROOT, Kind: sandbox.RootGoroot},
+	}
+	var warnings []string
+	if project != nil {
+		if project.Vendor != nil {
+			roots = append(roots, sandbox.Root{Path: project.Vendor.Dir, Kind: sandbox.RootVendor})
+		}
+		for _, mod := range project.Modules() {
+			// Only directory replacements have no version.
+			if mod.Replace == nil || mod.Replace.Version != "" {
+				continue
+			}
+			dir := mod.Replace.Path
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(project.Dir, dir)
+			}
+			roots = append(roots, sandbox.Root{Path: dir, Kind: sandbox.RootReplace})
+		}
+		warnings = project.Warnings()
+	}
+	for _, dir := range s.cfg.SourceRoots {
+		roots = append(roots, sandbox.Root{Path: dir, Kind: sandbox.RootConfig})
+	}
+
+	sb, err := sandbox.New(roots)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sb, warnings, nil
+}
+
+func (s *Server) registerFileTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "read_file",
+		Description: "Read a dependency source file without shell access. Only files under the Go module cache, GOROOT, the project's vendor directory and local replace targets (with project_dir), and configured source roots can be read; paths with \"..\" or symbolic links leading elsewhere are rejected. Read a byte range with offset/length or a line range with start_line/end_line; content is capped at max_bytes and marked truncated when cut.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args readFileArgs) (*mcp.CallToolResult, *sandbox.FileContent, error) {
+		if args.Path == "" {
+			return nil, nil, fmt.Errorf("path argument is required")
+		}
+		sb, _, err := s.newSandbox(args.ProjectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		opts := sandbox.ReadOptions{
+			Offset:    args.Offset,
+			Length:    args.Length,
+			StartLine: args.StartLine,
+			EndLine:   args.EndLine,
+			MaxBytes:  defaultReadBytes,
+		}
+		if args.MaxBytes > 0 {
+			opts.MaxBytes = min(args.MaxBytes, maxReadBytes)
+		}
+		content, err := sb.ReadFile(args.Path, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatFileContent(content)},
+			},
+		}, content, nil
+	})
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_dir",
+		Description: "List a directory of dependency sources without shell access. Only directories under the Go module cache, GOROOT, the project's vendor directory and local replace targets (with project_dir), and configured source roots can be listed. Without path, returns the allowed roots.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listDirArgs) (*mcp.CallToolResult, *listDirOutput, error) {
+		sb, warnings, err := s.newSandbox(args.ProjectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &listDirOutput{Path: args.Path, Warnings: warnings}
+		if args.Path == "" {
+			output.Roots = sb.Roots()
+		} else {
+			maxEntries := defaultDirEntries
+			if args.MaxEntries > 0 {
+				maxEntries = min(args.MaxEntries, maxDirEntries)
+			}
+			output.Entries, output.Truncated, err = sb.ListDir(args.Path, maxEntries)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatListDir(output)},
+			},
+		}, output, nil
+	})
+}
+
+func formatFileContent(content *sandbox.FileContent) string {
+	var b strings.Builder
+	if content.StartLine != 0 {
+		fmt.Fprintf(&b, "%s lines %d-%d (%d bytes total)", content.Path, content.StartLine, content.EndLine, content.Size)
+	} else {
+		fmt.Fprintf(&b, "%s bytes %d-%d of %d", content.Path, content.Offset, content.Offset+int64(len(content.Content)), content.Size)
+	}
+	switch {
+	case content.Truncated && content.StartLine != 0 && !strings.HasSuffix(content.Content, "\n"):
+		fmt.Fprintf(&b, ", truncated within line %d; continue with offset %d", content.EndLine, content.Offset+int64(len(content.Content)))
+	case content.Truncated:
+		b.WriteString(", truncated; request the next range to continue")
+	}
+	b.WriteString("\n\n")
+	b.WriteString(content.Content)
+	return b.String()
+}
+
+func formatListDir(output *listDirOutput) string {
+	var b strings.Builder
+	if output.Path == "" {
+		b.WriteString("Allowed source roots:\n")
+		for _, root := range output.Roots {
+			fmt.Fprintf(&b, "%s (%s)\n", root.Path, root.Kind)
+		}
+	} else {
+		fmt.Fprintf(&b, "%s:\n", output.Path)
+		for _, entry := range output.Entries {
+			switch entry.Type {
+			case "dir":
+				fmt.Fprintf(&b, "%s/\n", entry.Name)
+			case "symlink":
+				fmt.Fprintf(&b, "%s@\n", entry.Name)
+			default:
+				fmt.Fprintf(&b, "%s\t%d\n", entry.Name, entry.Size)
+			}
+		}
+		if output.Truncated {
+			b.WriteString("[more entries not shown]\n")
+		}
+	}
+
+	if len(output.Warnings) > 0 {
+		b.WriteString("\nWarnings:")
+		for _, warning := range output.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/goenv"
+)
+
+type goEnvArgs struct{}
+
+func (s *Server) registerGoEnvTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "go_env",
+		Description: "Return the Go environment this server uses to locate sources: GOROOT (standard library), GOPATH, GOMODCACHE (module cache), GOFLAGS and GOPRIVATE, and where each value came from. Values are resolved like the go command does (environment variables, then the go/env file written by `go env -w`, then defaults) without running the go binary, so use this instead of `go env` in shells where the go command is unavailable or not permitted. Modules are found in the cache at GOMODCACHE/<module path>@<version>, with uppercase letters escaped as !<lowercase>.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args goEnvArgs) (*mcp.CallToolResult, *goenv.Env, error) {
+		env, err := s.goEnv()
+		if err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatGoEnv(env)},
+			},
+		}, env, nil
+	})
+}
+
+func formatGoEnv(env *goenv.Env) string {
+	values := map[string]string{
+		"GOROOT":     env.GOROOT,
+		"GOPATH":     env.GOPATH,
+		"GOMODCACHE": env.GOMODCACHE,
+		"GOFLAGS":    env.GOFLAGS,
+		"GOPRIVATE":  env.GOPRIVATE,
+	}
+
+	var b strings.Builder
+	for _, name := range goenv.Variables {
+		fmt.Fprintf(&b, "%s=%s (%s)\n", name, values[name], env.Sources[name])
+	}
+	if env.EnvFile != "" {
+		fmt.Fprintf(&b, "\ngo/env file: %s\n", env.EnvFile)
+	}
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/svetlyi/mcp-local-context/internal/goenv"
+	"github.com/svetlyi/mcp-local-context/internal/goload"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+)
+
+// buildContextArgs lets a tool call override the configured build context.
+type buildContextArgs struct {
+	GOOS   string   `json:"goos,omitempty" jsonschema:"Target GOOS whose build constraints apply, e.g. linux, darwin, windows. Defaults to the configured or host value"`
+	GOARCH string   `json:"goarch,omitempty" jsonschema:"Target GOARCH whose build constraints apply, e.g. amd64, arm64. Defaults to the configured or host value"`
+	Cgo    *bool    `json:"cgo_enabled,omitempty" jsonschema:"Whether cgo files and the cgo build constraint are enabled, as with CGO_ENABLED. Defaults to the configured value, else CGO_ENABLED or the host default, off when cross-compiling"`
+	Tags   []string `json:"tags,omitempty" jsonschema:"Build tags to enable, as with go build -tags. Replaces the configured tags"`
+}
+
+func (a buildContextArgs) buildContext() goload.BuildContext {
+	return goload.BuildContext{GOOS: a.GOOS, GOARCH: a.GOARCH, CgoEnabled: a.Cgo, Tags: a.Tags}
+}
+
+// goEnv resolves the Go environment, applying the configured overrides.
+func (s *Server) goEnv() (*goenv.Env, error) {
+	env, err := goenv.Detect(goenv.WithOverrides(s.cfg.GoEnv))
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect Go environment: %w", err)
+	}
+	return env, nil
+}
+
+// loadGoProject resolves the Go environment and the module governing dir.
+func (s *Server) loadGoProject(dir string) (*goenv.Env, *gomod.Project, error) {
+	if dir == "" {
+		return nil, nil, fmt.Errorf("project_dir argument is required")
+	}
+
+	env, err := s.goEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	project, err := gomod.LoadProject(dir, env.GOMODCACHE, gomod.WithModFlag(env.ModFlag()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return env, project, nil
+}
+
+// newGoLoader creates a source loader for the module governing dir. The
+// configured build context is used unless the tool call overrides it.
+func (s *Server) newGoLoader(dir string, override buildContextArgs) (*goload.Loader, error) {
+	bc := goload.BuildContext{
+		GOOS:       s.cfg.GOOS,
+		GOARCH:     s.cfg.GOARCH,
+		CgoEnabled: s.cfg.CgoEnabled,
+		Tags:       s.cfg.BuildTags,
+	}.Override(override.buildContext())
+	if err := bc.Validate(); err != nil {
+		return nil, err
+	}
+
+	env, project, err := s.loadGoProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	return goload.New(project, env.GOROOT, bc), nil
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+)
+
+type checkGoVersionsArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the Go project (or any directory below its go.mod)"`
+	All        bool   `json:"all,omitempty" jsonschema:"List every module of the build list, not only the ones requiring a newer Go than the project"`
+}
+
+type checkGoVersionsOutput struct {
+	*gomod.GoVersionReport
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func (s *Server) registerGoVersionTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "check_go_versions",
+		Description: "Compare the go and toolchain directives of every module in the project's build list, read from the module cache, with the project's own go directive. Flags dependencies requiring a newer Go than the project declares and reports the minimum Go version the whole module graph implies. Run it before suggesting a dependency upgrade: an upgrade whose go.mod declares a newer go line raises the project's required Go version.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args checkGoVersionsArgs) (*mcp.CallToolResult, *checkGoVersionsOutput, error) {
+		_, project, err := s.loadGoProject(args.ProjectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		report := project.GoVersions()
+		if !args.All {
+			modules := make([]gomod.ModuleGoVersion, 0)
+			for _, mod := range report.Modules {
+				if mod.NewerThanMain || mod.Error != "" {
+					modules = append(modules, mod)
+				}
+			}
+			report.Modules = modules
+		}
+
+		output := &checkGoVersionsOutput{GoVersionReport: report, Warnings: project.Warnings()}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatGoVersions(project.Path, output)},
+			},
+		}, output, nil
+	})
+}
+
+func formatGoVersions(projectPath string, output *checkGoVersionsOutput) string {
+	var b strings.Builder
+	goVersion := output.Go
+	if goVersion == "" {
+		goVersion = "(no go directive)"
+	}
+	fmt.Fprintf(&b, "%s declares go %s", projectPath, goVersion)
+	if output.Toolchain != "" {
+		fmt.Fprintf(&b, ", toolchain %s", output.Toolchain)
+	}
+	b.WriteString("\n")
+	if output.MinimumGoModule == projectPath {
+		fmt.Fprintf(&b, "Minimum Go for the module graph: %s (the project's own go directive)\n", output.MinimumGo)
+	} else {
+		fmt.Fprintf(&b, "Minimum Go for the module graph: %s, required by %s\n", output.MinimumGo, output.MinimumGoModule)
+	}
+
+	var newer, others []gomod.ModuleGoVersion
+	for _, mod := range output.Modules {
+		if mod.NewerThanMain {
+			newer = append(newer, mod)
+		} else {
+			others = append(others, mod)
+		}
+	}
+	if len(newer) > 0 {
+		fmt.Fprintf(&b, "\nModules requiring a newer Go than the project (%d):\n", len(newer))
+		writeModuleGoVersions(&b, newer)
+	} else {
+		b.WriteString("\nNo module requires a newer Go than the project.\n")
+	}
+	if len(others) > 0 {
+		b.WriteString("\nOther modules:\n")
+		writeModuleGoVersions(&b, others)
+	}
+
+	if len(output.Warnings) > 0 {
+		b.WriteString("\nWarnings:")
+		for _, warning := range output.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
+
+func writeModuleGoVersions(b *strings.Builder, modules []gomod.ModuleGoVersion) {
+	for _, mod := range modules {
+		fmt.Fprintf(b, "- %s", mod.Path)
+		if mod.Version != "" {
+			fmt.Fprintf(b, "@%s", mod.Version)
+		}
+		switch {
+		case mod.Error != "":
+			fmt.Fprintf(b, ": %s", mod.Error)
+		case mod.Go == "":
+			b.WriteString(": no go directive")
+		default:
+			fmt.Fprintf(b, ": go %s", mod.Go)
+		}
+		if mod.Toolchain != "" {
+			fmt.Fprintf(b, ", toolchain %s", mod.Toolchain)
+		}
+		b.WriteString("\n")
+	}
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/generated"
+	"github.com/svetlyi/mcp-local-context/internal/modsearch"
+)
+
+const (
+	defaultGrepResults = 50
+	maxGrepResults     = 500
+	defaultGrepContext = 2
+	maxGrepContext     = 10
+)
+
+type grepModuleArgs struct {
+	ProjectDir   string `json:"project_dir" jsonschema:"Directory of the Go project (or any directory below its go.mod)"`
+	Module       string `json:"module" jsonschema:"Path of a module required by the project, e.g. github.com/nats-io/nats.go. The version in go.mod is searched"`
+	Pattern      string `json:"pattern" jsonschema:"Regular expression (RE2 syntax) to search for, or plain text when literal is set"`
+	Literal      bool   `json:"literal,omitempty" jsonschema:"Treat pattern as plain text instead of a regular expression"`
+	IgnoreCase   bool   `json:"ignore_case,omitempty" jsonschema:"Match case-insensitively"`
+	Package      string `json:"package,omitempty" jsonschema:"Restrict the search to a package: its import path or its directory relative to the module root, e.g. jetstream"`
+	Glob         string `json:"glob,omitempty" jsonschema:"Restrict the search to files matching this pattern, e.g. *.go or *_test.go. Patterns containing / match the path from the module root"`
+	MaxResults   int    `json:"max_results,omitempty" jsonschema:"Maximum number of matches to return (default 50, at most 500)"`
+	ContextLines *int   `json:"context_lines,omitempty" jsonschema:"Lines of context to return before and after each match (default 2, at most 10)"`
+	Generated    string `json:"generated,omitempty" jsonschema:"How to treat generated files (marked // Code generated ... DO NOT EDIT.): include (default), exclude, only, or last to return their matches after hand-written code"`
+}
+
+type grepModuleOutput struct {
+	Module  string `json:"module"`
+	Version string `json:"version"`
+	Dir     string `json:"dir"`
+	*modsearch.Result
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func (s *Server) registerGrepTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "grep_module",
+		Description: "Search the file contents of one Go dependency at exactly the version the project resolves it to, instead of running grep over the whole module cache, where every cached version of a module matches. Supports regular expressions or literal text, restricting the search to a package directory and a file glob, and returns each match with file:line and surrounding lines. Generated files can be excluded or listed last.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args grepModuleArgs) (*mcp.CallToolResult, *grepModuleOutput, error) {
+		if args.Module == "" {
+			return nil, nil, fmt.Errorf("module argument is required")
+		}
+		if args.Pattern == "" {
+			return nil, nil, fmt.Errorf("pattern argument is required")
+		}
+		mode, err := generated.ParseMode(args.Generated)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		expr := args.Pattern
+		if args.Literal {
+			expr = regexp.QuoteMeta(expr)
+		}
+		if args.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+
+		opts := modsearch.Options{
+			Pattern:    pattern,
+			Glob:       args.Glob,
+			MaxResults: defaultGrepResults,
+			Context:    defaultGrepContext,
+			Generated:  mode,
+		}
+		if args.MaxResults > 0 {
+			opts.MaxResults = min(args.MaxResults, maxGrepResults)
+		}
+		if args.ContextLines != nil {
+			opts.Context = min(max(*args.ContextLines, 0), maxGrepContext)
+		}
+
+		_, project, err := s.loadGoProject(args.ProjectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		mod := project.Module(args.Module)
+		if mod == nil || mod.Main {
+			return nil, nil, fmt.Errorf("module %s is not required by %s", args.Module, project.Path)
+		}
+		if _, err := os.Stat(mod.Dir); err != nil {
+			return nil, nil, fmt.Errorf("sources of %s@%s not found in %s; run `go mod download %s@%s`", mod.Path, mod.Version, mod.Dir, mod.Path, mod.Version)
+		}
+
+		opts.Subdir = args.Package
+		if rel, ok := strings.CutPrefix(args.Package, mod.Path); ok && (rel == "" || rel[0] == '/') {
+			opts.Subdir = strings.TrimPrefix(rel, "/")
+		}
+
+		result, err := modsearch.Grep(mod.Dir, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &grepModuleOutput{
+			Module:   mod.Path,
+			Version:  mod.Version,
+			Dir:      mod.Dir,
+			Result:   result,
+			Warnings: project.Warnings(),
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatGrepModule(output)},
+			},
+		}, output, nil
+	})
+}
+
+func formatGrepModule(output *grepModuleOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d matches in %s@%s (%d files searched in %s)", len(output.Matches), output.Module, output.Version, output.FilesScanned, output.Dir)
+	if output.Truncated {
+		b.WriteString(", stopped at the result limit")
+	}
+	b.WriteString("\n")
+
+	for _, match := range output.Matches {
+		fmt.Fprintf(&b, "\n%s:%d:%d", match.File, match.Line, match.Column)
+		switch {
+		case match.Proto != "":
+			fmt.Fprintf(&b, " [generated from %s]", match.Proto)
+		case match.Generated:
+			b.WriteString(" [generated]")
+		}
+		b.WriteString("\n")
+		for i, line := range match.Before {
+			fmt.Fprintf(&b, "%6d  %s\n", match.Line-len(match.Before)+i, line)
+		}
+		fmt.Fprintf(&b, "%6d> %s\n", match.Line, match.Text)
+		for i, line := range match.After {
+			fmt.Fprintf(&b, "%6d  %s\n", match.Line+1+i, line)
+		}
+	}
+
+	if len(output.Warnings) > 0 {
+		b.WriteString("\nWarnings:")
+		for _, warning := range output.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/goload"
+)
+
+const maxCheckedImports = 200
+
+type checkImportsArgs struct {
+	buildContextArgs
+	ProjectDir string   `json:"project_dir" jsonschema:"Directory of the Go project (or any directory below its go.mod)"`
+	Imports    []string `json:"imports" jsonschema:"Import paths to check, e.g. [\"github.com/go-chi/chi/v5/middleware\", \"net/http\"]"`
+}
+
+type checkImportsOutput struct {
+	Results  []goload.ImportCheck `json:"results"`
+	Warnings []string             `json:"warnings,omitempty"`
+}
+
+func (s *Server) registerImportTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "check_imports",
+		Description: "Validate Go import paths before writing them. Each path is checked against the project's go.mod requirements and the package directories of the resolved module versions (or the standard library), and reported as ok, wrong_major_version with the correct path (e.g. github.com/foo/bar instead of the required github.com/foo/bar/v3), missing_package with the nearest existing packages, internal_package for internal packages of other modules, not_a_dependency, or not_downloaded.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args checkImportsArgs) (*mcp.CallToolResult, *checkImportsOutput, error) {
+		if len(args.Imports) == 0 {
+			return nil, nil, fmt.Errorf("imports argument is required")
+		}
+		if len(args.Imports) > maxCheckedImports {
+			return nil, nil, fmt.Errorf("at most %d imports can be checked at once", maxCheckedImports)
+		}
+
+		loader, err := s.newGoLoader(args.ProjectDir, args.buildContextArgs)
+		if err != nil {
+			return nil, nil, err
+		}
+		output := &checkImportsOutput{
+			Results:  make([]goload.ImportCheck, 0, len(args.Imports)),
+			Warnings: loader.Project().Warnings(),
+		}
+		for _, importPath := range args.Imports {
+			output.Results = append(output.Results, loader.CheckImport(strings.TrimSpace(importPath)))
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatCheckImports(output)},
+			},
+		}, output, nil
+	})
+}
+
+func formatCheckImports(output *checkImportsOutput) string {
+	var b strings.Builder
+	for _, check := range output.Results {
+		fmt.Fprintf(&b, "%s: %s", check.ImportPath, check.Status)
+		switch {
+		case check.Standard:
+			b.WriteString(" (standard library)")
+		case check.Module != "":
+			fmt.Fprintf(&b, " (%s@%s)", check.Module, check.Version)
+		}
+		if check.Suggestion != "" {
+			fmt.Fprintf(&b, "\n    use %s", check.Suggestion)
+		}
+		if check.Message != "" && check.Suggestion == "" {
+			fmt.Fprintf(&b, "\n    %s", check.Message)
+		}
+		if len(check.Nearest) > 0 {
+			fmt.Fprintf(&b, "\n    nearest packages: %s", strings.Join(check.Nearest, ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(output.Warnings) > 0 {
+		b.WriteString("\nWarnings:")
+		for _, warning := range output.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/jvm"
+	"github.com/svetlyi/mcp-local-context/internal/sandbox"
+)
+
+type listJVMDependenciesArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the Maven or Gradle project (or any directory below its pom.xml or build.gradle)"`
+}
+
+type listJVMDependenciesOutput struct {
+	Dir          string           `json:"dir"`
+	Build        string           `json:"build"`
+	BuildFiles   []string         `json:"build_files"`
+	Dependencies []jvm.Dependency `json:"dependencies"`
+	Warnings     []string         `json:"warnings,omitempty"`
+}
+
+type listJVMClassesArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the Maven or Gradle project (or any directory below its pom.xml or build.gradle)"`
+	Artifact   string `json:"artifact" jsonschema:"Dependency as group:artifact, e.g. com.google.guava:guava, or the artifact alone when unambiguous"`
+	Package    string `json:"package,omitempty" jsonschema:"Only list this package and its subpackages, e.g. com.google.common.collect"`
+}
+
+type listJVMClassesOutput struct {
+	Dependency *jvm.Dependency `json:"dependency"`
+	Jar        string          `json:"jar"`
+	// BinaryOnly is set when no -sources.jar exists and the classes come
+	// from the binary jar.
+	BinaryOnly bool          `json:"binary_only,omitempty"`
+	Packages   []jvm.Package `json:"packages"`
+}
+
+type readJVMClassArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the Maven or Gradle project (or any directory below its pom.xml or build.gradle)"`
+	Artifact   string `json:"artifact" jsonschema:"Dependency as group:artifact, e.g. com.google.guava:guava, or the artifact alone when unambiguous"`
+	Class      string `json:"class" jsonschema:"Qualified name of the class, e.g. com.google.common.collect.ImmutableList"`
+	StartLine  int    `json:"start_line,omitempty" jsonschema:"First line to return (1-based, inclusive)"`
+	EndLine    int    `json:"end_line,omitempty" jsonschema:"Last line to return (1-based, inclusive)"`
+	MaxBytes   int    `json:"max_bytes,omitempty" jsonschema:"Maximum bytes of content to return (default 65536, at most 262144)"`
+}
+
+type readJVMClassOutput struct {
+	Dependency *jvm.Dependency      `json:"dependency"`
+	Class      string               `json:"class"`
+	Entry      string               `json:"entry"`
+	Content    *sandbox.FileContent `json:"content"`
+}
+
+func (s *Server) registerJVMTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_jvm_dependencies",
+		Description: "List the dependencies of a Java or Kotlin project with their group:artifact:version coordinates, read from pom.xml (resolving properties, parent POMs and dependencyManagement) or from the lockfiles Gradle writes with dependency locking, and the -sources.jar and binary jar found for each in ~/.m2/repository or ~/.gradle/caches/modules-2/files-2.1. Read these local copies instead of relying on memory of a library's API.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listJVMDependenciesArgs) (*mcp.CallToolResult, *listJVMDependenciesOutput, error) {
+		project, err := jvm.LoadProject(args.ProjectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &listJVMDependenciesOutput{
+			Dir:          project.Dir,
+			Build:        project.Build,
+			BuildFiles:   project.BuildFiles,
+			Dependencies: make([]jvm.Dependency, 0, len(project.Dependencies())),
+			Warnings:     project.Warnings(),
+		}
+		output.Dependencies = append(output.Dependencies, project.Dependencies()...)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatJVMDependencies(output)},
+			},
+		}, output, nil
+	})
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_jvm_classes",
+		Description: "List the packages of a Java or Kotlin dependency and the source files in each, from its -sources.jar at the resolved version. When only the binary jar was downloaded, the top-level classes of the binary jar are listed instead and the result says so; their source cannot be read.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listJVMClassesArgs) (*mcp.CallToolResult, *listJVMClassesOutput, error) {
+		dep, err := loadJVMDependency(args.ProjectDir, args.Artifact)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &listJVMClassesOutput{Dependency: dep, Jar: dep.SourcesJar}
+		if output.Jar == "" {
+			if dep.Jar == "" {
+				return nil, nil, fmt.Errorf("no jar of %s is in the Maven repository or the Gradle cache; %s", dep.Coordinates(), downloadAdvice(dep))
+			}
+			output.Jar, output.BinaryOnly = dep.Jar, true
+		}
+		output.Packages, err = jvm.ListPackages(output.Jar, !output.BinaryOnly, args.Package)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(output.Packages) == 0 && args.Package != "" {
+			return nil, nil, fmt.Errorf("%s has no package %s", dep.Coordinates(), args.Package)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatJVMClasses(output)},
+			},
+		}, output, nil
+	})
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "read_jvm_class",
+		Description: "Return the source of a class of a Java or Kotlin dependency from its -sources.jar, given its qualified name. Nested classes resolve to the file of their outer class, and Kotlin classes to the .kt file declaring them. Fails with a clear message when only the binary jar was downloaded.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args readJVMClassArgs) (*mcp.CallToolResult, *readJVMClassOutput, error) {
+		if args.Class == "" {
+			return nil, nil, fmt.Errorf("class argument is required")
+		}
+		dep, err := loadJVMDependency(args.ProjectDir, args.Artifact)
+		if err != nil {
+			return nil, nil, err
+		}
+		if dep.SourcesJar == "" {
+			if dep.Jar == "" {
+				return nil, nil, fmt.Errorf("no jar of %s is in the Maven repository or the Gradle cache; %s", dep.Coordinates(), downloadAdvice(dep))
+			}
+			if found, _ := jvm.HasClass(dep.Jar, args.Class); !found {
+				return nil, nil, fmt.Errorf("%s is not in %s", args.Class, dep.Jar)
+			}
+			return nil, nil, fmt.Errorf("only the binary jar of %s exists (%s), so the source of %s cannot be read; %s", dep.Coordinates(), dep.Jar, args.Class, downloadAdvice(dep))
+		}
+		entry, data, err := jvm.ReadSource(dep.SourcesJar, args.Class)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		opts := sandbox.ReadOptions{StartLine: args.StartLine, EndLine: args.EndLine, MaxBytes: defaultReadBytes}
+		if args.MaxBytes > 0 {
+			opts.MaxBytes = min(args.MaxBytes, maxReadBytes)
+		}
+		content, err := sandbox.ReadContent(dep.SourcesJar+"!/"+entry, data, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &readJVMClassOutput{Dependency: dep, Class: args.Class, Entry: entry, Content: content}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatJVMClass(output)},
+			},
+		}, output, nil
+	})
+}
+
+// loadJVMDependency finds a dependency of the Maven or Gradle project in
+// projectDir.
+func loadJVMDependency(projectDir, artifact string) (*jvm.Dependency, error) {
+	if artifact == "" {
+		return nil, fmt.Errorf("artifact argument is required")
+	}
+	project, err := jvm.LoadProject(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	dep, err := project.Dependency(artifact)
+	if err != nil {
+		return nil, err
+	}
+	if dep.Version == "" {
+		return nil, fmt.Errorf("the version of %s could not be resolved; run mvn dependency:resolve", dep.Coordinates())
+	}
+	return dep, nil
+}
+
+// downloadAdvice tells how to download the sources of dep.
+func downloadAdvice(dep *jvm.Dependency) string {
+	return fmt.Sprintf("download its sources with mvn dependency:get -Dartifact=%s:jar:sources, or with the IDE's or Gradle's download sources option", dep.Coordinates())
+}
+
+func formatJVMDependencies(output *listJVMDependenciesOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d dependencies of %s (%s, from %s)\n\n", len(output.Dependencies), output.Dir, output.Build, strings.Join(output.BuildFiles, ", "))
+	for _, dep := range output.Dependencies {
+		fmt.Fprintf(&b, "- %s", dep.Coordinates())
+		if dep.Scope != "" {
+			fmt.Fprintf(&b, " [%s]", dep.Scope)
+		}
+		switch {
+		case dep.SourcesJar != "":
+			fmt.Fprintf(&b, "\n    %s\n", dep.SourcesJar)
+		case dep.Jar != "":
+			fmt.Fprintf(&b, ", binary jar only\n    %s\n", dep.Jar)
+		case dep.Version != "":
+			b.WriteString(", not downloaded\n")
+		default:
+			b.WriteString("\n")
+		}
+	}
+
+	if len(output.Warnings) > 0 {
+		b.WriteString("\nWarnings:")
+		for _, warning := range output.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
+
+func formatJVMClasses(output *listJVMClassesOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d packages of %s in %s\n", len(output.Packages), output.Dependency.Coordinates(), output.Jar)
+	if output.BinaryOnly {
+		fmt.Fprintf(&b, "Only the binary jar exists: these are compiled classes and their source cannot be read; %s.\n", downloadAdvice(output.Dependency))
+	}
+	for _, pkg := range output.Packages {
+		name := pkg.Name
+		if name == "" {
+			name = "(default package)"
+		}
+		fmt.Fprintf(&b, "\n%s\n  %s\n", name, strings.Join(pkg.Classes, ", "))
+	}
+	return b.String()
+}
+
+func formatJVMClass(output *readJVMClassOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s of %s (%s)\n\n", output.Class, output.Dependency.Coordinates(), output.Entry)
+	b.WriteString(formatFileContent(output.Content))
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/licenses"
+)
+
+type listLicensesArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the Go project (or any directory below its go.mod)"`
+}
+
+type listLicensesOutput struct {
+	Licenses []licenses.Entry `json:"licenses"`
+	Warnings []string         `json:"warnings,omitempty"`
+}
+
+func (s *Server) registerLicenseTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_dependency_licenses",
+		Description: "List the license of every module the Go project requires, detected by matching each module's LICENSE/COPYING files in the local module cache against bundled SPDX license texts. Returns module, version, SPDX identifier, match confidence (share of the license text found) and license file path. NONE means the module has no license file, NOASSERTION means its license file matched no known license. Use this before adding a dependency to check its license.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listLicensesArgs) (*mcp.CallToolResult, *listLicensesOutput, error) {
+		_, project, err := s.loadGoProject(args.ProjectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		entries, warnings, err := licenses.Inventory(project)
+		if err != nil {
+			return nil, nil, err
+		}
+		warnings = append(project.Warnings(), warnings...)
+
+		var b strings.Builder
+		if err := licenses.WriteTable(&b, entries); err != nil {
+			return nil, nil, err
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(&b, "\nWarning: %s", warning)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: b.String()},
+			},
+		}, &listLicensesOutput{Licenses: entries, Warnings: warnings}, nil
+	})
+}
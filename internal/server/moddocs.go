@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/mod/semver"
+
+	"github.com/svetlyi/mcp-local-context/internal/gomod"
+	"github.com/svetlyi/mcp-local-context/internal/moddocs"
+)
+
+// maxDocBytes caps the size of a single documentation file, or of the
+// changelog sections taken from it, in a response.
+const maxDocBytes = 64 * 1024
+
+type getModuleDocsArgs struct {
+	ProjectDir    string   `json:"project_dir" jsonschema:"Directory of the Go project (or any directory below its go.mod)"`
+	Module        string   `json:"module" jsonschema:"Path of a module required by the project, e.g. github.com/nats-io/nats.go"`
+	TargetVersion string   `json:"target_version,omitempty" jsonschema:"Version you plan to upgrade to, e.g. v1.50.0. Changelogs are then reduced to the sections after the project's current version up to this version"`
+	Kinds         []string `json:"kinds,omitempty" jsonschema:"Documents to return: readme, changelog, migration, upgrading. Defaults to all"`
+}
+
+type moduleDocFile struct {
+	Kind      moddocs.Kind      `json:"kind"`
+	Path      string            `json:"path"`
+	Content   string            `json:"content,omitempty"`
+	Sections  []moddocs.Section `json:"sections,omitempty"`
+	Truncated bool              `json:"truncated,omitempty"`
+}
+
+type getModuleDocsOutput struct {
+	Module         string          `json:"module"`
+	CurrentVersion string          `json:"current_version"`
+	TargetVersion  string          `json:"target_version,omitempty"`
+	Dir            string          `json:"dir"`
+	Files          []moduleDocFile `json:"files"`
+	Warnings       []string        `json:"warnings,omitempty"`
+}
+
+func (s *Server) registerModuleDocsTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_module_docs",
+		Description: "Retrieve a dependency's README, CHANGELOG, MIGRATION and UPGRADING files from the local module cache. Use this when upgrading a dependency: pass target_version and the changelog is sliced to only the release sections between the version the project currently requires and the target version, so you read exactly the release notes that apply to the upgrade.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args getModuleDocsArgs) (*mcp.CallToolResult, *getModuleDocsOutput, error) {
+		if args.Module == "" {
+			return nil, nil, fmt.Errorf("module argument is required")
+		}
+		if args.TargetVersion != "" && !semver.IsValid(args.TargetVersion) {
+			return nil, nil, fmt.Errorf("invalid target_version %q: expected a semantic version such as v1.2.3", args.TargetVersion)
+		}
+		for _, kind := range args.Kinds {
+			if _, err := moddocs.ParseKind(kind); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		_, project, err := s.loadGoProject(args.ProjectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		mod := project.Module(args.Module)
+		if mod == nil || mod.Main {
+			return nil, nil, fmt.Errorf("module %s is not required by %s", args.Module, project.Path)
+		}
+
+		output := &getModuleDocsOutput{
+			Module:         mod.Path,
+			CurrentVersion: mod.Version,
+			TargetVersion:  args.TargetVersion,
+			Dir:            mod.Dir,
+			Files:          make([]moduleDocFile, 0),
+			Warnings:       project.Warnings(),
+		}
+
+		// Release notes for the target version only exist in its own copy
+		// of the module.
+		if args.TargetVersion != "" && mod.Replace == nil {
+			targetDir, err := gomod.CacheDir(project.ModCache(), mod.Path, args.TargetVersion)
+			if err != nil {
+				return nil, nil, err
+			}
+			if info, err := os.Stat(targetDir); err == nil && info.IsDir() {
+				output.Dir = targetDir
+			} else {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("%s@%s is not in the module cache, showing docs of %s; run `go mod download %s@%s` to get its release notes", mod.Path, args.TargetVersion, mod.Version, mod.Path, args.TargetVersion))
+			}
+		}
+
+		docs, err := moddocs.FindDocs(output.Dir)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, doc := range docs {
+			if len(args.Kinds) > 0 && !slices.Contains(args.Kinds, string(doc.Kind)) {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(output.Dir, doc.Path))
+			if err != nil {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("failed to read %s: %v", doc.Path, err))
+				continue
+			}
+
+			file := moduleDocFile{Kind: doc.Kind, Path: doc.Path}
+			if doc.Kind == moddocs.KindChangelog && args.TargetVersion != "" {
+				sections := moddocs.ParseChangelog(string(data))
+				if len(sections) > 0 {
+					file.Sections, file.Truncated = moddocs.LimitSections(moddocs.SliceChangelog(sections, mod.Version, args.TargetVersion), maxDocBytes)
+					output.Files = append(output.Files, file)
+					continue
+				}
+				output.Warnings = append(output.Warnings, fmt.Sprintf("no version headings found in %s, returning it whole", doc.Path))
+			}
+
+			if len(data) > maxDocBytes {
+				data = data[:maxDocBytes]
+				file.Truncated = true
+			}
+			file.Content = strings.ToValidUTF8(string(data), "")
+			output.Files = append(output.Files, file)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatModuleDocs(output)},
+			},
+		}, output, nil
+	})
+}
+
+func formatModuleDocs(output *getModuleDocsOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Documentation of %s (project requires %s", output.Module, output.CurrentVersion)
+	if output.TargetVersion != "" {
+		fmt.Fprintf(&b, ", target %s", output.TargetVersion)
+	}
+	fmt.Fprintf(&b, ") from %s\n", output.Dir)
+
+	if len(output.Files) == 0 {
+		b.WriteString("\nNo README, CHANGELOG, MIGRATION or UPGRADING files found.\n")
+	}
+	for _, file := range output.Files {
+		fmt.Fprintf(&b, "\n# %s (%s)\n\n", file.Path, file.Kind)
+		if file.Sections != nil {
+			if len(file.Sections) == 0 {
+				b.WriteString("No release sections between the current and target version.\n")
+			}
+			for _, section := range file.Sections {
+				fmt.Fprintf(&b, "## %s\n\n%s\n\n", section.Heading, section.Content)
+			}
+			if file.Truncated {
+				fmt.Fprintf(&b, "[truncated after %d bytes; narrow target_version to read the remaining sections]\n", maxDocBytes)
+			}
+			continue
+		}
+		b.WriteString(file.Content)
+		if file.Truncated {
+			fmt.Fprintf(&b, "\n\n[truncated after %d bytes]", maxDocBytes)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(output.Warnings) > 0 {
+		b.WriteString("\nWarnings:")
+		for _, warning := range output.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type getDependencyNotesArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the project (or any directory below its manifest)"`
+	Ecosystem  string `json:"ecosystem,omitempty" jsonschema:"Ecosystem to use instead of the detected one: go, javascript, python, rust, java, ruby, php or csharp"`
+}
+
+type dependencyNote struct {
+	Prompt   string `json:"prompt"`
+	Module   string `json:"module"`
+	Version  string `json:"version"`
+	Versions string `json:"versions,omitempty"`
+	Content  string `json:"content"`
+}
+
+type getDependencyNotesOutput struct {
+	Ecosystem string           `json:"ecosystem"`
+	Dir       string           `json:"dir"`
+	Notes     []dependencyNote `json:"notes"`
+	Warnings  []string         `json:"warnings,omitempty"`
+}
+
+func (s *Server) registerDependencyNoteTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_dependency_notes",
+		Description: "Return the team's notes about the dependencies of a project that apply to the versions it actually resolves: custom prompts declaring a module and a semver versions constraint, e.g. notes for github.com/nats-io/nats.go < v1.30, are matched against the exact versions from go.mod, lockfiles or installed packages. Call it before working with a project's dependencies to learn version-specific pitfalls.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args getDependencyNotesArgs) (*mcp.CallToolResult, *getDependencyNotesOutput, error) {
+		eco, err := s.resolveEcosystem(args.ProjectDir, args.Ecosystem)
+		if err != nil {
+			return nil, nil, err
+		}
+		project, err := eco.Load(args.ProjectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &getDependencyNotesOutput{Ecosystem: project.Ecosystem, Dir: project.Dir, Notes: make([]dependencyNote, 0), Warnings: project.Warnings}
+		for _, dep := range project.Dependencies {
+			if dep.Version == "" {
+				continue
+			}
+			for _, prompt := range s.registry.GetDependencyNotes(project.Ecosystem, dep.Name, dep.Version) {
+				output.Notes = append(output.Notes, dependencyNote{
+					Prompt:   prompt.Name,
+					Module:   dep.Name,
+					Version:  dep.Version,
+					Versions: prompt.Versions,
+					Content:  prompt.Content,
+				})
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatDependencyNotes(output)},
+			},
+		}, output, nil
+	})
+}
+
+func formatDependencyNotes(output *getDependencyNotesOutput) string {
+	var b strings.Builder
+	if len(output.Notes) == 0 {
+		fmt.Fprintf(&b, "No notes apply to the dependency versions of %s (%s).\n", output.Dir, output.Ecosystem)
+	} else {
+		fmt.Fprintf(&b, "%d notes apply to the dependency versions of %s (%s)\n", len(output.Notes), output.Dir, output.Ecosystem)
+	}
+	for _, note := range output.Notes {
+		fmt.Fprintf(&b, "\n# %s: %s %s", note.Prompt, note.Module, note.Version)
+		if note.Versions != "" {
+			fmt.Fprintf(&b, " (matches %s)", note.Versions)
+		}
+		fmt.Fprintf(&b, "\n\n%s\n", strings.TrimRight(note.Content, "\n"))
+	}
+
+	if len(output.Warnings) > 0 {
+		b.WriteString("\nWarnings:")
+		for _, warning := range output.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/npm"
+	"github.com/svetlyi/mcp-local-context/internal/sandbox"
+)
+
+const maxDeclarationFiles = 200
+
+type listNpmDependenciesArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the JavaScript or TypeScript project (or any directory below its package.json)"`
+	Production bool   `json:"production,omitempty" jsonschema:"Leave out devDependencies"`
+}
+
+type npmDependency struct {
+	npm.Dependency
+	// Types is the absolute path of the package's main declaration file.
+	Types string `json:"types,omitempty"`
+}
+
+type listNpmDependenciesOutput struct {
+	Name         string          `json:"name,omitempty"`
+	Dir          string          `json:"dir"`
+	Lockfile     string          `json:"lockfile,omitempty"`
+	Dependencies []npmDependency `json:"dependencies"`
+	Warnings     []string        `json:"warnings,omitempty"`
+}
+
+type readNpmTypingsArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the JavaScript or TypeScript project (or any directory below its package.json)"`
+	Package    string `json:"package" jsonschema:"Name of the dependency, e.g. zod or @tanstack/react-query"`
+	Subpath    string `json:"subpath,omitempty" jsonschema:"Export subpath whose declarations to read, e.g. ./client. Defaults to the package root"`
+	File       string `json:"file,omitempty" jsonschema:"Declaration file to read instead, relative to the package directory, e.g. dist/types.d.ts as referenced from the entry file"`
+	StartLine  int    `json:"start_line,omitempty" jsonschema:"First line to read, starting at 1"`
+	EndLine    int    `json:"end_line,omitempty" jsonschema:"Last line to read, inclusive"`
+	MaxBytes   int    `json:"max_bytes,omitempty" jsonschema:"Maximum bytes of content to return (default 65536, at most 262144)"`
+}
+
+type readNpmTypingsOutput struct {
+	*npm.Typings
+	// DeclarationFiles lists the declaration files of the package, relative
+	// to its directory.
+	DeclarationFiles []string             `json:"declaration_files"`
+	FilesTruncated   bool                 `json:"files_truncated,omitempty"`
+	Content          *sandbox.FileContent `json:"content"`
+}
+
+func (s *Server) registerNpmTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_npm_dependencies",
+		Description: "List the dependencies of a JavaScript or TypeScript project from its package.json, with the version range declared, the exact version its lockfile (package-lock.json, pnpm-lock.yaml or yarn.lock) resolves, the version installed in node_modules or the pnpm .pnpm store, the installed directory and the path of the package's .d.ts typings. Read these local copies instead of relying on memory of a package's API.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listNpmDependenciesArgs) (*mcp.CallToolResult, *listNpmDependenciesOutput, error) {
+		project, err := npm.LoadProject(args.ProjectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &listNpmDependenciesOutput{
+			Name:         project.Manifest.Name,
+			Dir:          project.Dir,
+			Lockfile:     project.Lockfile,
+			Dependencies: make([]npmDependency, 0),
+			Warnings:     project.Warnings(),
+		}
+		for _, dep := range project.Dependencies() {
+			if args.Production && dep.Kind == npm.KindDev {
+				continue
+			}
+			info := npmDependency{Dependency: dep}
+			if dep.Dir != "" {
+				if typings, err := npm.ResolveTypings(project, dep.Name, dep.Dir, "."); err == nil {
+					info.Types = filepath.Join(typings.Dir, filepath.FromSlash(typings.Entry))
+				}
+			}
+			output.Dependencies = append(output.Dependencies, info)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatNpmDependencies(output)},
+			},
+		}, output, nil
+	})
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "read_npm_typings",
+		Description: "Read the TypeScript declarations (.d.ts) of an installed npm dependency. The entry file is found through the package's exports (types conditions), types or typings fields, falling back to the @types package. Returns the entry file content, the subpaths the package exports with their declaration files, and the package's other declaration files, any of which can be read with the file argument.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args readNpmTypingsArgs) (*mcp.CallToolResult, *readNpmTypingsOutput, error) {
+		if args.Package == "" {
+			return nil, nil, fmt.Errorf("package argument is required")
+		}
+		project, err := npm.LoadProject(args.ProjectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		dep, err := project.Dependency(args.Package)
+		if err != nil {
+			return nil, nil, err
+		}
+		if dep.Dir == "" {
+			return nil, nil, fmt.Errorf("%s is not installed in node_modules; run the package manager's install command", dep.Name)
+		}
+
+		typings, err := npm.ResolveTypings(project, dep.Name, dep.Dir, args.Subpath)
+		if err != nil {
+			return nil, nil, err
+		}
+		file := typings.Entry
+		if args.File != "" {
+			file = args.File
+		}
+		sb, err := sandbox.New([]sandbox.Root{{Path: typings.Dir, Kind: sandbox.RootPackage}})
+		if err != nil {
+			return nil, nil, err
+		}
+		opts := sandbox.ReadOptions{StartLine: args.StartLine, EndLine: args.EndLine, MaxBytes: defaultReadBytes}
+		if args.MaxBytes > 0 {
+			opts.MaxBytes = min(args.MaxBytes, maxReadBytes)
+		}
+		content, err := sb.ReadFile(filepath.Join(typings.Dir, filepath.FromSlash(file)), opts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &readNpmTypingsOutput{Typings: typings, Content: content}
+		output.DeclarationFiles, output.FilesTruncated, err = npm.DeclarationFiles(typings.Dir, maxDeclarationFiles)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatNpmTypings(output)},
+			},
+		}, output, nil
+	})
+}
+
+func formatNpmDependencies(output *listNpmDependenciesOutput) string {
+	var b strings.Builder
+	name := output.Name
+	if name == "" {
+		name = output.Dir
+	}
+	fmt.Fprintf(&b, "%d dependencies of %s", len(output.Dependencies), name)
+	if output.Lockfile != "" {
+		fmt.Fprintf(&b, " (locked by %s)", output.Lockfile)
+	}
+	b.WriteString("\n\n")
+
+	for _, dep := range output.Dependencies {
+		fmt.Fprintf(&b, "- %s %s", dep.Name, dep.Range)
+		if dep.Kind != npm.KindProd {
+			fmt.Fprintf(&b, " [%s]", dep.Kind)
+		}
+		if dep.Locked != "" {
+			fmt.Fprintf(&b, ", locked %s", dep.Locked)
+		}
+		switch {
+		case dep.Dir == "":
+			b.WriteString(", not installed\n")
+			continue
+		case dep.Locked != "" && dep.Installed != dep.Locked:
+			fmt.Fprintf(&b, ", but node_modules has %s; reinstall before trusting it", dep.Installed)
+		case dep.Locked == "":
+			fmt.Fprintf(&b, ", installed %s", dep.Installed)
+		}
+		fmt.Fprintf(&b, "\n    %s\n", dep.Dir)
+		if dep.Types != "" {
+			fmt.Fprintf(&b, "    types: %s\n", dep.Types)
+		}
+	}
+
+	if len(output.Warnings) > 0 {
+		b.WriteString("\nWarnings:")
+		for _, warning := range output.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
+
+func formatNpmTypings(output *readNpmTypingsOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s@%s declarations in %s (entry %s, from %s)\n", output.Package, output.Version, output.Dir, output.Entry, output.Source)
+	if len(output.Exports) > 0 {
+		b.WriteString("\nExported subpaths:\n")
+		for _, export := range output.Exports {
+			fmt.Fprintf(&b, "- %s: %s\n", export.Subpath, export.Types)
+		}
+	}
+	fmt.Fprintf(&b, "\nDeclaration files (%d", len(output.DeclarationFiles))
+	if output.FilesTruncated {
+		b.WriteString(", more not shown")
+	}
+	b.WriteString("):\n")
+	for _, file := range output.DeclarationFiles {
+		fmt.Fprintf(&b, "- %s\n", file)
+	}
+	b.WriteString("\n")
+	b.WriteString(formatFileContent(output.Content))
+	return b.String()
+}
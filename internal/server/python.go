@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/pyenv"
+	"github.com/svetlyi/mcp-local-context/internal/sandbox"
+)
+
+type listPythonPackagesArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the Python project (or any directory below its .venv or venv directory's parent)"`
+	Filter     string `json:"filter,omitempty" jsonschema:"Only list distributions whose name or import names contain this text"`
+}
+
+type listPythonPackagesOutput struct {
+	*pyenv.Env
+	Distributions []pyenv.Distribution `json:"distributions"`
+}
+
+type readPythonModuleArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the Python project (or any directory below its .venv or venv directory's parent)"`
+	Module     string `json:"module" jsonschema:"Dotted name of a module, optionally followed by a class, function or attribute, e.g. requests.adapters or requests.adapters.HTTPAdapter.send"`
+	Stub       bool   `json:"stub,omitempty" jsonschema:"Read the .pyi stub instead of the .py source"`
+	StartLine  int    `json:"start_line,omitempty" jsonschema:"First line to read, starting at 1. Defaults to the definition of the named class, function or attribute"`
+	EndLine    int    `json:"end_line,omitempty" jsonschema:"Last line to read, inclusive"`
+	MaxBytes   int    `json:"max_bytes,omitempty" jsonschema:"Maximum bytes of content to return (default 65536, at most 262144)"`
+}
+
+type readPythonModuleOutput struct {
+	*pyenv.Module
+	// SymbolLine is the line defining Symbol in the file read, 0 when it
+	// was not found.
+	SymbolLine int                  `json:"symbol_line,omitempty"`
+	Content    *sandbox.FileContent `json:"content"`
+}
+
+func (s *Server) registerPythonTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_python_packages",
+		Description: "List the distributions installed in a Python project's virtual environment (a configured python_prefix, a .venv or venv directory next to the project, or the active VIRTUAL_ENV), with the exact version from their METADATA and the top-level import names each one provides, e.g. yaml for PyYAML. Read these local copies instead of relying on memory of a package's API.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listPythonPackagesArgs) (*mcp.CallToolResult, *listPythonPackagesOutput, error) {
+		env, err := pyenv.FindEnv(args.ProjectDir, s.cfg.PythonPrefix)
+		if err != nil {
+			return nil, nil, err
+		}
+		dists, err := env.Distributions()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &listPythonPackagesOutput{Env: env, Distributions: make([]pyenv.Distribution, 0)}
+		filter := strings.ToLower(args.Filter)
+		for _, dist := range dists {
+			if filter == "" || strings.Contains(strings.ToLower(dist.Name), filter) || containsFold(dist.ImportNames, filter) {
+				output.Distributions = append(output.Distributions, dist)
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatPythonPackages(output)},
+			},
+		}, output, nil
+	})
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "read_python_module",
+		Description: "Read the source (.py) or type stub (.pyi) of a module installed in a Python project's virtual environment, given its dotted name. A trailing class, function or attribute name, e.g. requests.adapters.HTTPAdapter.send, starts the read at its definition. Stubs are also looked up in a separate <package>-stubs distribution. Reports the distribution and version providing the module.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args readPythonModuleArgs) (*mcp.CallToolResult, *readPythonModuleOutput, error) {
+		if args.Module == "" {
+			return nil, nil, fmt.Errorf("module argument is required")
+		}
+		env, err := pyenv.FindEnv(args.ProjectDir, s.cfg.PythonPrefix)
+		if err != nil {
+			return nil, nil, err
+		}
+		mod, err := env.FindModule(args.Module)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		file := mod.Source
+		if args.Stub || file == "" {
+			file = mod.Stub
+		}
+		if file == "" {
+			if args.Stub && mod.Source != "" {
+				return nil, nil, fmt.Errorf("%s has no .pyi stub; read its source instead", mod.Name)
+			}
+			if mod.Extension != "" {
+				return nil, nil, fmt.Errorf("%s is a compiled extension module (%s) without Python source or stubs", mod.Name, mod.Extension)
+			}
+			return nil, nil, fmt.Errorf("%s is a namespace package without an __init__.py; read one of its submodules", mod.Name)
+		}
+
+		output := &readPythonModuleOutput{Module: mod}
+		output.SymbolLine, err = pyenv.SymbolLine(file, mod.Symbol)
+		if err != nil {
+			return nil, nil, err
+		}
+		sb, err := sandbox.New([]sandbox.Root{{Path: env.SitePackages, Kind: sandbox.RootPackage}})
+		if err != nil {
+			return nil, nil, err
+		}
+		opts := sandbox.ReadOptions{StartLine: args.StartLine, EndLine: args.EndLine, MaxBytes: defaultReadBytes}
+		if opts.StartLine == 0 && opts.EndLine == 0 {
+			opts.StartLine = output.SymbolLine
+		}
+		if args.MaxBytes > 0 {
+			opts.MaxBytes = min(args.MaxBytes, maxReadBytes)
+		}
+		output.Content, err = sb.ReadFile(file, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatPythonModule(output)},
+			},
+		}, output, nil
+	})
+}
+
+func containsFold(values []string, lowerSubstr string) bool {
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), lowerSubstr) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatPythonPackages(output *listPythonPackagesOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d distributions in %s", len(output.Distributions), output.SitePackages)
+	if output.Version != "" {
+		fmt.Fprintf(&b, " (Python %s)", output.Version)
+	}
+	b.WriteString("\n\n")
+	for _, dist := range output.Distributions {
+		fmt.Fprintf(&b, "- %s %s", dist.Name, dist.Version)
+		if len(dist.ImportNames) > 0 {
+			fmt.Fprintf(&b, ", imports %s", strings.Join(dist.ImportNames, ", "))
+		}
+		b.WriteString("\n")
+		if dist.Summary != "" {
+			fmt.Fprintf(&b, "    %s\n", dist.Summary)
+		}
+	}
+	return b.String()
+}
+
+func formatPythonModule(output *readPythonModuleOutput) string {
+	var b strings.Builder
+	b.WriteString(output.Name)
+	if output.Distribution != nil {
+		fmt.Fprintf(&b, " from %s %s", output.Distribution.Name, output.Distribution.Version)
+	}
+	b.WriteString("\n")
+	if output.Source != "" {
+		fmt.Fprintf(&b, "source: %s\n", output.Source)
+	}
+	if output.Stub != "" {
+		fmt.Fprintf(&b, "stub: %s\n", output.Stub)
+	}
+	if output.Symbol != "" {
+		if output.SymbolLine > 0 {
+			fmt.Fprintf(&b, "%s is defined at line %d\n", output.Symbol, output.SymbolLine)
+		} else {
+			fmt.Fprintf(&b, "%s was not found as a class, def or assignment; it may be imported from another module\n", output.Symbol)
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(formatFileContent(output.Content))
+	return b.String()
+}
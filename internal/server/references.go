@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/generated"
+	"github.com/svetlyi/mcp-local-context/internal/goanalysis"
+)
+
+type findReferencesArgs struct {
+	buildContextArgs
+	ProjectDir   string `json:"project_dir" jsonschema:"Directory of the Go project (or any directory below its go.mod)"`
+	Symbol       string `json:"symbol" jsonschema:"Fully qualified dependency symbol, e.g. github.com/nats-io/nats.go.Conn.Subscribe or net/http.Client.Do"`
+	IncludeTests bool   `json:"include_tests,omitempty" jsonschema:"Also search _test.go files"`
+	Generated    string `json:"generated,omitempty" jsonschema:"How to treat references in generated files (marked // Code generated ... DO NOT EDIT.): include (default), exclude, only, or last to list them after hand-written code"`
+}
+
+func (s *Server) registerReferenceTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "find_dependency_references",
+		Description: "Find every place the current Go project references a dependency symbol (function, type, method, field, variable or constant). References are resolved with go/types against the exact module versions in go.mod, so they are precise rather than text matches. Each reference reports file:line:column, the enclosing function and the source line. Use this BEFORE changing how the project uses a library to see every existing call site.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args findReferencesArgs) (*mcp.CallToolResult, *goanalysis.ReferencesResult, error) {
+		if args.Symbol == "" {
+			return nil, nil, fmt.Errorf("symbol argument is required")
+		}
+		mode, err := generated.ParseMode(args.Generated)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		loader, err := s.newGoLoader(args.ProjectDir, args.buildContextArgs)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		symbol, err := goanalysis.ResolveSymbol(loader, args.Symbol)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		result, err := goanalysis.FindReferences(loader, symbol, args.IncludeTests, mode)
+		if err != nil {
+			return nil, nil, err
+		}
+		result.Warnings = append(loader.Project().Warnings(), result.Warnings...)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatReferences(result)},
+			},
+		}, result, nil
+	})
+}
+
+func formatReferences(result *goanalysis.ReferencesResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d references to %s %s", len(result.References), result.Kind, result.Symbol)
+	if result.Omitted > 0 {
+		fmt.Fprintf(&b, " (%d omitted by the generated file filter)", result.Omitted)
+	}
+	b.WriteString("\n")
+	for _, ref := range result.References {
+		fmt.Fprintf(&b, "\n%s:%d:%d", ref.File, ref.Line, ref.Column)
+		if ref.Function != "" {
+			fmt.Fprintf(&b, " in %s", ref.Function)
+		}
+		b.WriteString(generatedNote(ref))
+		fmt.Fprintf(&b, "\n\t%s", ref.Snippet)
+	}
+	if len(result.Warnings) > 0 {
+		b.WriteString("\n\nWarnings (references in these packages may be incomplete):")
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
+
+// generatedNote marks references in generated files, which are changed by
+// editing their source (such as a .proto file) and regenerating them.
+func generatedNote(ref goanalysis.Reference) string {
+	switch {
+	case !ref.Generated:
+		return ""
+	case ref.Proto != "":
+		return fmt.Sprintf(" [generated from %s]", ref.Proto)
+	default:
+		return " [generated]"
+	}
+}
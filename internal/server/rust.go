@@ -0,0 +1,309 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/cargo"
+	"github.com/svetlyi/mcp-local-context/internal/sandbox"
+)
+
+const maxRustItems = 2000
+
+type listCargoDependenciesArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the Rust project (or any directory below its Cargo.toml)"`
+	Normal     bool   `json:"normal,omitempty" jsonschema:"Leave out dev-dependencies and build-dependencies"`
+}
+
+type listCargoDependenciesOutput struct {
+	Name         string             `json:"name,omitempty"`
+	Dir          string             `json:"dir"`
+	Lockfile     string             `json:"lockfile,omitempty"`
+	Dependencies []cargo.Dependency `json:"dependencies"`
+	Warnings     []string           `json:"warnings,omitempty"`
+}
+
+type listRustModulesArgs struct {
+	ProjectDir     string `json:"project_dir" jsonschema:"Directory of the Rust project (or any directory below its Cargo.toml)"`
+	Crate          string `json:"crate" jsonschema:"Name of the dependency, e.g. tokio or serde_json"`
+	Module         string `json:"module,omitempty" jsonschema:"Only list this module and its submodules, e.g. sync"`
+	IncludePrivate bool   `json:"include_private,omitempty" jsonschema:"Also list private modules and items"`
+}
+
+type listRustModulesOutput struct {
+	Crate     string          `json:"crate"`
+	Version   string          `json:"version"`
+	Dir       string          `json:"dir"`
+	Modules   []*cargo.Module `json:"modules"`
+	Truncated bool            `json:"truncated,omitempty"`
+}
+
+type readRustItemArgs struct {
+	ProjectDir string `json:"project_dir" jsonschema:"Directory of the Rust project (or any directory below its Cargo.toml)"`
+	Crate      string `json:"crate" jsonschema:"Name of the dependency, e.g. tokio or serde_json"`
+	Item       string `json:"item" jsonschema:"Path of the item within the crate, e.g. sync::Mutex, sync::Mutex::lock for a method, or a module path such as sync"`
+	MaxBytes   int    `json:"max_bytes,omitempty" jsonschema:"Maximum bytes of content to return (default 65536, at most 262144)"`
+}
+
+type readRustItemOutput struct {
+	Crate   string `json:"crate"`
+	Version string `json:"version"`
+	*cargo.ItemSource
+	Content *sandbox.FileContent `json:"content"`
+}
+
+func (s *Server) registerRustTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_cargo_dependencies",
+		Description: "List the dependencies of a Rust project from its Cargo.toml (including workspace-inherited and target-specific ones), with the version requirement declared, the exact version Cargo.lock resolves and the directory cargo unpacked the sources into under ~/.cargo/registry/src or ~/.cargo/git/checkouts. Read these local copies instead of relying on memory of a crate's API.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listCargoDependenciesArgs) (*mcp.CallToolResult, *listCargoDependenciesOutput, error) {
+		project, err := cargo.LoadProject(args.ProjectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &listCargoDependenciesOutput{
+			Name:         project.Name,
+			Dir:          project.Dir,
+			Lockfile:     project.Lockfile,
+			Dependencies: make([]cargo.Dependency, 0),
+			Warnings:     project.Warnings(),
+		}
+		for _, dep := range project.Dependencies() {
+			if args.Normal && dep.Kind != cargo.KindNormal {
+				continue
+			}
+			output.Dependencies = append(output.Dependencies, dep)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatCargoDependencies(output)},
+			},
+		}, output, nil
+	})
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "list_rust_modules",
+		Description: "List the modules of a Rust dependency at the version Cargo.lock resolves, with the pub items (fn, struct, enum, trait, type, const, static, macro and impl methods) each declares and the names it re-exports with pub use. Modules and items come from a lightweight parse of the crate sources: items generated by macros or excluded by cfg are not resolved.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listRustModulesArgs) (*mcp.CallToolResult, *listRustModulesOutput, error) {
+		crate, err := s.loadCrate(args.ProjectDir, args.Crate)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &listRustModulesOutput{Crate: crate.Name, Version: crate.Version, Dir: crate.Dir, Modules: make([]*cargo.Module, 0)}
+		items := 0
+		for _, mod := range crate.Modules {
+			if args.Module != "" && mod.Path != args.Module && !strings.HasPrefix(mod.Path, args.Module+"::") {
+				continue
+			}
+			if !mod.Public && !args.IncludePrivate {
+				continue
+			}
+			listed := *mod
+			listed.Items = make([]cargo.Item, 0, len(mod.Items))
+			for _, item := range mod.Items {
+				if !item.Public && !args.IncludePrivate {
+					continue
+				}
+				if items == maxRustItems {
+					output.Truncated = true
+					break
+				}
+				listed.Items = append(listed.Items, item)
+				items++
+			}
+			output.Modules = append(output.Modules, &listed)
+		}
+		if len(output.Modules) == 0 && args.Module != "" {
+			return nil, nil, fmt.Errorf("crate %s has no module %s", crate.Name, args.Module)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatRustModules(output)},
+			},
+		}, output, nil
+	})
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "read_rust_item",
+		Description: "Return the source of a named item of a Rust dependency, with its doc comments and attributes: a fn, struct, enum, trait, type, const, static, macro, a method of an impl block (Type::method) or an inline module. Follows pub use re-exports within the crate to the declaration, e.g. tokio's sync::Mutex to sync/mutex.rs.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args readRustItemArgs) (*mcp.CallToolResult, *readRustItemOutput, error) {
+		if args.Item == "" {
+			return nil, nil, fmt.Errorf("item argument is required")
+		}
+		crate, err := s.loadCrate(args.ProjectDir, args.Crate)
+		if err != nil {
+			return nil, nil, err
+		}
+		source, err := crate.FindItem(args.Item)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		sb, err := sandbox.New([]sandbox.Root{{Path: crate.Dir, Kind: sandbox.RootPackage}})
+		if err != nil {
+			return nil, nil, err
+		}
+		opts := sandbox.ReadOptions{StartLine: source.StartLine, EndLine: source.EndLine, MaxBytes: defaultReadBytes}
+		if args.MaxBytes > 0 {
+			opts.MaxBytes = min(args.MaxBytes, maxReadBytes)
+		}
+		content, err := sb.ReadFile(source.File, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &readRustItemOutput{Crate: crate.Name, Version: crate.Version, ItemSource: source, Content: content}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatRustItem(output)},
+			},
+		}, output, nil
+	})
+}
+
+// loadCrate parses the sources of a dependency of the Rust project in
+// projectDir, or of the project itself.
+func (s *Server) loadCrate(projectDir, name string) (*cargo.Crate, error) {
+	if name == "" {
+		return nil, fmt.Errorf("crate argument is required")
+	}
+	project, err := cargo.LoadProject(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	dir := project.Dir
+	if strings.ReplaceAll(name, "-", "_") != strings.ReplaceAll(project.Name, "-", "_") {
+		dep, err := project.Dependency(name)
+		if err != nil {
+			return nil, err
+		}
+		if dep.Dir == "" {
+			if dep.Locked == "" {
+				return nil, fmt.Errorf("%s is not in Cargo.lock; run cargo generate-lockfile", dep.CrateName())
+			}
+			return nil, fmt.Errorf("the sources of %s %s are not in %s; run cargo fetch", dep.CrateName(), dep.Locked, project.CargoHome)
+		}
+		dir = dep.Dir
+	}
+	return cargo.ParseCrate(dir)
+}
+
+func formatCargoDependencies(output *listCargoDependenciesOutput) string {
+	var b strings.Builder
+	name := output.Name
+	if name == "" {
+		name = output.Dir
+	}
+	fmt.Fprintf(&b, "%d dependencies of %s", len(output.Dependencies), name)
+	if output.Lockfile != "" {
+		fmt.Fprintf(&b, " (locked by %s)", output.Lockfile)
+	}
+	b.WriteString("\n\n")
+
+	for _, dep := range output.Dependencies {
+		fmt.Fprintf(&b, "- %s", dep.Name)
+		if dep.Package != "" {
+			fmt.Fprintf(&b, " (package %s)", dep.Package)
+		}
+		if dep.Requirement != "" {
+			fmt.Fprintf(&b, " %s", dep.Requirement)
+		}
+		var notes []string
+		if dep.Kind != cargo.KindNormal {
+			notes = append(notes, dep.Kind)
+		}
+		if dep.Target != "" {
+			notes = append(notes, dep.Target)
+		}
+		if dep.Optional {
+			notes = append(notes, "optional")
+		}
+		if len(notes) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(notes, ", "))
+		}
+		if dep.Locked != "" {
+			fmt.Fprintf(&b, ", locked %s", dep.Locked)
+		}
+		switch {
+		case dep.Dir != "":
+			fmt.Fprintf(&b, "\n    %s\n", dep.Dir)
+		case dep.Path != "":
+			fmt.Fprintf(&b, ", path %s not found\n", dep.Path)
+		case dep.Locked != "":
+			b.WriteString(", sources not downloaded\n")
+		default:
+			b.WriteString("\n")
+		}
+	}
+
+	if len(output.Warnings) > 0 {
+		b.WriteString("\nWarnings:")
+		for _, warning := range output.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
+
+func formatRustModules(output *listRustModulesOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d modules of %s %s in %s\n", len(output.Modules), output.Crate, output.Version, output.Dir)
+	for _, mod := range output.Modules {
+		path := mod.Path
+		if path == "" {
+			path = "(crate root)"
+		}
+		fmt.Fprintf(&b, "\n%s", path)
+		if !mod.Public {
+			b.WriteString(" [private]")
+		}
+		fmt.Fprintf(&b, " %s", mod.File)
+		if mod.Line > 0 {
+			fmt.Fprintf(&b, ":%d", mod.Line)
+		}
+		b.WriteString("\n")
+		for _, item := range mod.Items {
+			name := item.Name
+			if item.Parent != "" {
+				name = item.Parent + "::" + item.Name
+			}
+			fmt.Fprintf(&b, "  %s %s (line %d)", item.Kind, name, item.Line)
+			if !item.Public {
+				b.WriteString(" [private]")
+			}
+			b.WriteString("\n")
+		}
+		for _, reexport := range mod.Reexports {
+			fmt.Fprintf(&b, "  pub use %s", reexport.Path)
+			if reexport.Name != "*" && !strings.HasSuffix(reexport.Path, "::"+reexport.Name) {
+				fmt.Fprintf(&b, " as %s", reexport.Name)
+			}
+			b.WriteString("\n")
+		}
+	}
+	if output.Truncated {
+		fmt.Fprintf(&b, "\nOnly the first %d items are listed; pass module to narrow the list.\n", maxRustItems)
+	}
+	return b.String()
+}
+
+func formatRustItem(output *readRustItemOutput) string {
+	var b strings.Builder
+	kind := "module"
+	if output.Item != nil {
+		kind = output.Item.Kind
+	}
+	fmt.Fprintf(&b, "%s %s of %s %s\n", kind, output.Path, output.Crate, output.Version)
+	for _, via := range output.Via {
+		fmt.Fprintf(&b, "re-exported by %s\n", via)
+	}
+	b.WriteString("\n")
+	b.WriteString(formatFileContent(output.Content))
+	return b.String()
+}
@@ -7,15 +7,19 @@ import (
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/config"
+	"github.com/svetlyi/mcp-local-context/internal/ecosystem"
 	"github.com/svetlyi/mcp-local-context/internal/prompts"
 )
 
 type Server struct {
-	registry  *prompts.Registry
-	mcpServer *mcp.Server
+	registry   *prompts.Registry
+	ecosystems *ecosystem.Registry
+	cfg        *config.Config
+	mcpServer  *mcp.Server
 }
 
-func New(registry *prompts.Registry) (*Server, error) {
+func New(registry *prompts.Registry, ecosystems *ecosystem.Registry, cfg *config.Config) (*Server, error) {
 	mcpServer := mcp.NewServer(&mcp.Implementation{
 		Name:    "mcp-local-context",
 		Title:   "Local Context Instructions Server",
@@ -23,8 +27,10 @@ func New(registry *prompts.Registry) (*Server, error) {
 	}, nil)
 
 	s := &Server{
-		registry:  registry,
-		mcpServer: mcpServer,
+		registry:   registry,
+		ecosystems: ecosystems,
+		cfg:        cfg,
+		mcpServer:  mcpServer,
 	}
 
 	allPrompts := registry.GetAllPrompts()
@@ -66,11 +72,20 @@ func (s *Server) registerPrompt(prompt prompts.Prompt) {
 			return nil, fmt.Errorf("prompt not found: %s", req.Params.Name)
 		}
 
+		content := p.Content
+		if p.Render != nil {
+			rendered, err := p.Render(req.Params.Arguments)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render prompt %s: %w", p.Name, err)
+			}
+			content = rendered
+		}
+
 		// Convert to MCP message format
 		messages := []*mcp.PromptMessage{
 			{
 				Role:    mcp.Role("user"),
-				Content: &mcp.TextContent{Text: p.Content},
+				Content: &mcp.TextContent{Text: content},
 			},
 		}
 
@@ -107,14 +122,19 @@ func (s *Server) registerTools() error {
 	})
 
 	type getContextInstructionsArgs struct {
-		// Language is the programming language of the third-party package/library you're working with. REQUIRED. Examples: "golang" for Go modules/packages, "python" for pip packages, "javascript" for npm packages, etc. Use list_supported_languages first to see available options.
+		// Language is the programming language of the third-party package/library you're working with. REQUIRED. Examples: "go" for Go modules/packages, "python" for pip packages, "javascript" for npm packages, etc. Common aliases such as "golang", "js", "ts" and "py" are accepted. Use list_supported_languages first to see available options.
 		Language string `json:"language" jsonschema:"required"`
 	}
+	type getContextInstructionsOutput struct {
+		// Language is the canonical language the requested one resolved to.
+		Language string   `json:"language"`
+		Prompts  []string `json:"prompts"`
+	}
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "get_context_instructions",
 		Description: "MANDATORY when working with ANY third-party package, library, dependency, or external module. This tool provides authoritative, systematic instructions for correctly understanding and working with code in a specific language ecosystem. These instructions are CRITICAL because: 1) They show you how to locate and inspect the ACTUAL source code in local caches (not outdated online docs), 2) They provide proven methods for understanding package structures, module systems, and dependency resolution, 3) They prevent common mistakes and incorrect assumptions that lead to bugs. BEFORE calling: Use list_supported_languages first to verify support. WHEN to use: IMMEDIATELY when you see imports, require statements, package declarations, or any reference to external code. These instructions are your source of truth - ignore them at your peril. They are specifically designed to help you work with real, local codebases rather than relying on potentially outdated or incomplete documentation.",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, args getContextInstructionsArgs) (*mcp.CallToolResult, any, error) {
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args getContextInstructionsArgs) (*mcp.CallToolResult, *getContextInstructionsOutput, error) {
 		if args.Language == "" {
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{
@@ -123,7 +143,8 @@ func (s *Server) registerTools() error {
 			}, nil, fmt.Errorf("language argument is required")
 		}
 
-		prompts := s.registry.GetPromptsByLanguage(args.Language)
+		language := s.registry.CanonicalLanguage(args.Language)
+		prompts := s.registry.GetPromptsByLanguage(language)
 		if len(prompts) == 0 {
 			supportedLanguages := s.registry.GetSupportedLanguages()
 			message := fmt.Sprintf("No context instructions found for language: %s", args.Language)
@@ -142,8 +163,14 @@ func (s *Server) registerTools() error {
 			}, nil, nil
 		}
 
-		var contents []mcp.Content
+		output := &getContextInstructionsOutput{Language: language, Prompts: make([]string, 0, len(prompts))}
+		header := fmt.Sprintf("Context instructions for language: %s", language)
+		if language != args.Language {
+			header += fmt.Sprintf(" (requested as %q)", args.Language)
+		}
+		contents := []mcp.Content{&mcp.TextContent{Text: header}}
 		for _, prompt := range prompts {
+			output.Prompts = append(output.Prompts, prompt.Name)
 			contents = append(contents, &mcp.TextContent{
 				Text: fmt.Sprintf("# %s\n\n%s", prompt.Name, prompt.Content),
 			})
@@ -151,9 +178,29 @@ func (s *Server) registerTools() error {
 
 		return &mcp.CallToolResult{
 			Content: contents,
-		}, nil, nil
+		}, output, nil
 	})
 
+	s.registerReferenceTools()
+	s.registerDeprecationTools()
+	s.registerModuleDocsTools()
+	s.registerLicenseTools()
+	s.registerGoEnvTools()
+	s.registerGrepTools()
+	s.registerFileTools()
+	s.registerTypeMemberTools()
+	s.registerErrorTools()
+	s.registerImportTools()
+	s.registerGoVersionTools()
+	s.registerNpmTools()
+	s.registerPythonTools()
+	s.registerRustTools()
+	s.registerJVMTools()
+	s.registerCSharpTools()
+	s.registerDependencyTools()
+	s.registerDetectTools()
+	s.registerDependencyNoteTools()
+
 	return nil
 }
 
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/svetlyi/mcp-local-context/internal/goanalysis"
+)
+
+type getTypeMembersArgs struct {
+	buildContextArgs
+	ProjectDir        string `json:"project_dir" jsonschema:"Directory of the Go project (or any directory below its go.mod)"`
+	Type              string `json:"type" jsonschema:"Fully qualified type, e.g. github.com/nats-io/nats.go.Conn or net/http.Client"`
+	IncludeUnexported bool   `json:"include_unexported,omitempty" jsonschema:"Also list unexported methods and fields"`
+}
+
+type getTypeMembersOutput struct {
+	*goanalysis.TypeMembers
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func (s *Server) registerTypeMemberTools() {
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_type_members",
+		Description: "Return the complete method set of a Go type for both T and *T, and its flattened fields, including methods and fields promoted from embedded structs and interfaces, with the chain of embedded fields each promoted member comes through. Computed with go/types over the exact dependency versions in go.mod. Use this instead of reading a single type declaration, which misses promoted members such as the methods of an embedded *http.Client.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args getTypeMembersArgs) (*mcp.CallToolResult, *getTypeMembersOutput, error) {
+		if args.Type == "" {
+			return nil, nil, fmt.Errorf("type argument is required")
+		}
+
+		loader, err := s.newGoLoader(args.ProjectDir, args.buildContextArgs)
+		if err != nil {
+			return nil, nil, err
+		}
+		symbol, err := goanalysis.ResolveSymbol(loader, args.Type)
+		if err != nil {
+			return nil, nil, err
+		}
+		members, err := goanalysis.ExpandType(loader, symbol, args.IncludeUnexported)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output := &getTypeMembersOutput{TypeMembers: members, Warnings: loader.Project().Warnings()}
+		if errs := symbol.Package.Errors; len(errs) > 0 {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("%s: %v", symbol.Package.Path, errs[0]))
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatTypeMembers(output)},
+			},
+		}, output, nil
+	})
+}
+
+func formatTypeMembers(output *getTypeMembersOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "type %s %s\n", output.Type, output.Underlying)
+
+	fmt.Fprintf(&b, "\nMethods (%d):\n", len(output.Methods))
+	for _, method := range output.Methods {
+		fmt.Fprintf(&b, "- (%s) %s%s", method.Receiver, method.Name, method.Signature)
+		if method.PointerOnly {
+			b.WriteString(" [pointer receiver: only on *T]")
+		}
+		if len(method.Via) > 0 {
+			fmt.Fprintf(&b, " via %s", formatEmbedChain(method.Via))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(output.Fields) > 0 {
+		fmt.Fprintf(&b, "\nFields (%d):\n", len(output.Fields))
+		for _, field := range output.Fields {
+			fmt.Fprintf(&b, "- %s %s", field.Name, field.Type)
+			if field.Embedded {
+				b.WriteString(" (embedded)")
+			}
+			if field.Tag != "" {
+				fmt.Fprintf(&b, " `%s`", field.Tag)
+			}
+			if len(field.Via) > 0 {
+				fmt.Fprintf(&b, " via %s", formatEmbedChain(field.Via))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(output.Ambiguous) > 0 {
+		fmt.Fprintf(&b, "\nAmbiguous selectors, promoted from several embedded fields: %s\n", strings.Join(output.Ambiguous, ", "))
+	}
+	if len(output.Warnings) > 0 {
+		b.WriteString("\nWarnings:")
+		for _, warning := range output.Warnings {
+			fmt.Fprintf(&b, "\n- %s", warning)
+		}
+	}
+	return b.String()
+}
+
+func formatEmbedChain(chain []goanalysis.EmbedStep) string {
+	steps := make([]string, 0, len(chain))
+	for _, step := range chain {
+		steps = append(steps, fmt.Sprintf("%s (%s)", step.Field, step.Type))
+	}
+	return strings.Join(steps, " -> ")
+}
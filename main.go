@@ -2,12 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/svetlyi/mcp-local-context/internal/cli"
 	"github.com/svetlyi/mcp-local-context/internal/config"
+	"github.com/svetlyi/mcp-local-context/internal/ecosystem"
+	"github.com/svetlyi/mcp-local-context/internal/goenv"
 	"github.com/svetlyi/mcp-local-context/internal/logging"
 	"github.com/svetlyi/mcp-local-context/internal/prompts"
 	"github.com/svetlyi/mcp-local-context/internal/prompts/custom"
@@ -21,6 +27,17 @@ func main() {
 		cfg = config.DefaultConfig()
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "licenses" {
+		if err := cli.Licenses(cfg, os.Args[2:], os.Stdout, os.Stderr); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return
+			}
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	closeLog, err := logging.Setup(cfg)
 	if err != nil {
 		slog.Error("Failed to setup logging", "error", err)
@@ -29,7 +46,25 @@ func main() {
 	defer closeLog()
 
 	registry := prompts.NewRegistry()
-	registry.Register(prompts.NewGolangProvider())
+	registry.AddAliases(cfg.LanguageAliases)
+	registry.Register(prompts.NewGolangProvider(goenv.WithOverrides(cfg.GoEnv)))
+	registry.Register(prompts.NewJavaScriptProvider())
+	registry.Register(prompts.NewPythonProvider())
+	registry.Register(prompts.NewRustProvider())
+	registry.Register(prompts.NewJavaProvider())
+	registry.Register(prompts.NewRubyProvider())
+	registry.Register(prompts.NewPHPProvider())
+	registry.Register(prompts.NewCSharpProvider())
+
+	ecosystems := ecosystem.NewRegistry()
+	ecosystems.Register(ecosystem.NewGolang(goenv.WithOverrides(cfg.GoEnv)))
+	ecosystems.Register(ecosystem.NewJavaScript())
+	ecosystems.Register(ecosystem.NewPython(cfg.PythonPrefix))
+	ecosystems.Register(ecosystem.NewRust())
+	ecosystems.Register(ecosystem.NewJava())
+	ecosystems.Register(ecosystem.NewRuby())
+	ecosystems.Register(ecosystem.NewPHP())
+	ecosystems.Register(ecosystem.NewCSharp())
 
 	customProviders, err := custom.LoadPromptsFromDirectories(cfg.CustomPromptDirs)
 	if err != nil {
@@ -43,7 +78,7 @@ func main() {
 		slog.Info("Loaded custom prompts", "count", len(customProviders))
 	}
 
-	srv, err := server.New(registry)
+	srv, err := server.New(registry, ecosystems, cfg)
 	if err != nil {
 		slog.Error("Failed to create server", "error", err)
 		os.Exit(1)